@@ -0,0 +1,135 @@
+// Package client is a Go client for the collector's gRPC MarketDataService
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	binanceProto "github.com/binance-live/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// Client wraps a connection to the MarketDataService
+type Client struct {
+	conn       *grpc.ClientConn
+	MarketData binanceProto.MarketDataServiceClient
+}
+
+// New creates a client for the server at target, such as "localhost:9090". Without
+// options the connection is unencrypted.
+func New(target string, opts ...grpc.DialOption) (*Client, error) {
+	if len(opts) == 0 {
+		opts = []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	}
+
+	conn, err := grpc.NewClient(target, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create gRPC client: %w", err)
+	}
+
+	return &Client{
+		conn:       conn,
+		MarketData: binanceProto.NewMarketDataServiceClient(conn),
+	}, nil
+}
+
+// Close closes the connection
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// Klines passes every kline of symbol with open time in [startTime, endTime) to fn,
+// oldest first, requesting page after page. An endTime of 0 is unbounded.
+func (c *Client) Klines(ctx context.Context, symbol, interval string, startTime, endTime int64, fn func(*binanceProto.LiveData) error) error {
+	req := &binanceProto.GetKlinesRequest{
+		Symbol:    symbol,
+		Interval:  interval,
+		StartTime: startTime,
+		EndTime:   endTime,
+	}
+
+	for {
+		page, err := c.MarketData.GetKlines(ctx, req)
+		if err != nil {
+			return fmt.Errorf("failed to get klines: %w", err)
+		}
+
+		for _, item := range page.Items {
+			if err := fn(item); err != nil {
+				return err
+			}
+		}
+
+		if page.NextCursor == "" {
+			return nil
+		}
+		req.Cursor = page.NextCursor
+	}
+}
+
+// Trades passes every aggregated trade of symbol with timestamp in [startTime, endTime)
+// to fn in trade order, requesting page after page. An endTime of 0 is unbounded.
+func (c *Client) Trades(ctx context.Context, symbol string, startTime, endTime int64, fn func(*binanceProto.LiveData) error) error {
+	req := &binanceProto.GetTradesRequest{
+		Symbol:    symbol,
+		StartTime: startTime,
+		EndTime:   endTime,
+	}
+
+	for {
+		page, err := c.MarketData.GetTrades(ctx, req)
+		if err != nil {
+			return fmt.Errorf("failed to get trades: %w", err)
+		}
+
+		for _, item := range page.Items {
+			if err := fn(item); err != nil {
+				return err
+			}
+		}
+
+		if page.NextCursor == "" {
+			return nil
+		}
+		req.Cursor = page.NextCursor
+	}
+}
+
+// LatestTicker returns the newest stored ticker of symbol
+func (c *Client) LatestTicker(ctx context.Context, symbol string) (*binanceProto.LiveData, error) {
+	ticker, err := c.MarketData.GetLatestTicker(ctx, &binanceProto.GetLatestTickerRequest{Symbol: symbol})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest ticker: %w", err)
+	}
+	return ticker, nil
+}
+
+// Subscribe passes the live data matching req to fn until ctx is cancelled, fn returns
+// an error or the stream ends. A RESOURCE_EXHAUSTED error means the client fell
+// behind and was disconnected; subscribe again to resume.
+func (c *Client) Subscribe(ctx context.Context, req *binanceProto.SubscribeRequest, fn func(*binanceProto.LiveData) error) error {
+	stream, err := c.MarketData.Subscribe(ctx, req)
+	if err != nil {
+		return fmt.Errorf("failed to subscribe: %w", err)
+	}
+
+	for {
+		liveData, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("subscription failed: %w", err)
+		}
+
+		if err := fn(liveData); err != nil {
+			return err
+		}
+	}
+}
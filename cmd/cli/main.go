@@ -27,6 +27,9 @@ func init() {
 	rootCmd.AddCommand(cli.NewSyncCmd())
 	rootCmd.AddCommand(cli.NewSymbolsCmd())
 	rootCmd.AddCommand(cli.NewStatusCmd())
+	rootCmd.AddCommand(cli.NewDBCmd())
+	rootCmd.AddCommand(cli.NewBarsCmd())
+	rootCmd.AddCommand(cli.NewRedisCmd())
 }
 
 func main() {
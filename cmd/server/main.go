@@ -6,8 +6,10 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"slices"
 	"syscall"
 
+	"github.com/binance-live/internal/api"
 	"github.com/binance-live/internal/binance"
 	"github.com/binance-live/internal/config"
 	"github.com/binance-live/internal/database"
@@ -16,6 +18,7 @@ import (
 	"github.com/binance-live/internal/redis"
 	"github.com/binance-live/internal/repository"
 	"github.com/binance-live/internal/service"
+	"github.com/binance-live/sql/migrations"
 	"go.uber.org/zap"
 )
 
@@ -77,6 +80,23 @@ func run(ctx context.Context, cfg *config.Config, log *zap.Logger) error {
 	}
 	defer db.Close()
 
+	// Apply pending schema migrations
+	if cfg.Database.MigrateOnStart {
+
+		migrator, err := database.NewMigrator(db, migrations.FS)
+		if err != nil {
+
+			return err
+		}
+
+		applied, err := migrator.Up(ctx, 0)
+		if err != nil {
+
+			return fmt.Errorf("failed to apply migrations: %w", err)
+		}
+		log.Info("Schema migrations applied", zap.Int("count", applied))
+	}
+
 	// Initialize Redis client
 	log.Info("Connecting to Redis...")
 	redisClient, err := redis.New(&cfg.Redis, log)
@@ -93,7 +113,25 @@ func run(ctx context.Context, cfg *config.Config, log *zap.Logger) error {
 	syncStatusRepo := repository.NewSyncStatusRepository(db)
 
 	// Initialize publisher
-	pub := publisher.New(redisClient, log)
+	pub, err := publisher.New(redisClient, &cfg.Publisher, log)
+	if err != nil {
+
+		return fmt.Errorf("failed to create publisher: %w", err)
+	}
+	log.Info("Publisher",
+		zap.Strings("backends", cfg.Publisher.Backends),
+		zap.String("format", cfg.Publisher.Format),
+		zap.String("mode", cfg.Publisher.Mode),
+		zap.Int("flush_interval_ms", cfg.Publisher.FlushInterval),
+	)
+
+	// Fan live data out in-process to gRPC subscribers and the WebSocket gateway
+	var hub *publisher.Hub
+	if cfg.GRPC.Enabled || (cfg.API.WebSocket.Enabled && cfg.API.WebSocket.Source == api.GatewaySourceInProcess) {
+
+		hub = publisher.NewHub(pub)
+		pub = hub
+	}
 
 	// Initialize Binance client
 	binanceClient := binance.NewClient(cfg, log)
@@ -126,12 +164,42 @@ func run(ctx context.Context, cfg *config.Config, log *zap.Logger) error {
 		log.Warn("Failed to publish symbols to Redis", zap.Error(err))
 	}
 
+	// Initialize bar builder
+	var barService *service.BarService
+	if cfg.Bars.Enabled {
+
+		specs, err := service.ParseBarSpecs(cfg.Bars.Specs)
+		if err != nil {
+
+			return fmt.Errorf("invalid bar specs: %w", err)
+		}
+
+		var barRepo *repository.BarRepository
+		if cfg.Bars.Store {
+
+			barRepo = repository.NewBarRepository(db)
+		}
+
+		barService = service.NewBarService(specs, pub, barRepo, log)
+		log.Info("Bar builder enabled", zap.Strings("specs", cfg.Bars.Specs), zap.Bool("store", cfg.Bars.Store))
+	}
+
+	// Initialize ticker storage sampling
+	tickerSampler, err := service.NewTickerSampler(&cfg.Tickers)
+	if err != nil {
+
+		return fmt.Errorf("invalid tickers config: %w", err)
+	}
+	log.Info("Ticker storage", zap.String("mode", string(tickerSampler.Mode())))
+
 	streamService := service.NewStreamService(
 		binanceClient,
 		klineRepo,
 		tickerRepo,
 		syncStatusRepo,
 		&pub,
+		barService,
+		tickerSampler,
 		log,
 	)
 
@@ -142,6 +210,83 @@ func run(ctx context.Context, cfg *config.Config, log *zap.Logger) error {
 		return fmt.Errorf("failed to start streaming: %w", err)
 	}
 
+	// Start HTTP and gRPC query APIs
+	if cfg.API.Enabled || cfg.GRPC.Enabled {
+
+		// The HTTP API and WebSocket gateway decode the protobuf values cached in Redis
+		if cfg.API.Enabled && cfg.Publisher.Format == string(publisher.FormatJSON) {
+
+			return fmt.Errorf("the HTTP API needs publisher.format protobuf or both")
+		}
+		if cfg.API.Enabled && len(cfg.Publisher.Backends) > 0 && !slices.Contains(cfg.Publisher.Backends, string(publisher.BackendRedis)) {
+
+			return fmt.Errorf("the HTTP API needs the redis publisher backend")
+		}
+
+		// Serve derived intervals from their continuous aggregates
+		views, err := service.BuildDerivedKlineViews(&cfg.Binance)
+		if err != nil {
+
+			return fmt.Errorf("invalid derived intervals: %w", err)
+		}
+		klineRepo.UseDerivedViews(views)
+
+		apiServer := api.NewServer(
+			&cfg.API,
+			klineRepo,
+			tickerRepo,
+			repository.NewTradeRepository(db),
+			symbolRepo,
+			syncStatusRepo,
+			redisClient,
+			log,
+		)
+
+		if cfg.API.Enabled && cfg.API.WebSocket.Enabled {
+
+			// The Redis source relays pub/sub, which the streams mode does not publish
+			if cfg.API.WebSocket.Source == api.GatewaySourceRedis && cfg.Publisher.Mode == string(publisher.PublishStreams) {
+
+				return fmt.Errorf("the redis WebSocket source needs publisher.mode pubsub or both")
+			}
+
+			gateway, err := api.NewGateway(&cfg.API.WebSocket, redisClient, hub, log)
+			if err != nil {
+
+				return fmt.Errorf("invalid WebSocket gateway config: %w", err)
+			}
+			apiServer.UseGateway(gateway)
+
+			go func() {
+
+				if err := gateway.Run(ctx); err != nil {
+					log.Error("WebSocket gateway error", zap.Error(err))
+				}
+			}()
+		}
+
+		if cfg.API.Enabled {
+
+			go func() {
+
+				if err := apiServer.Run(ctx); err != nil {
+					log.Error("HTTP API error", zap.Error(err))
+				}
+			}()
+		}
+
+		if cfg.GRPC.Enabled {
+
+			grpcServer := api.NewGRPCServer(&cfg.GRPC, apiServer, hub, log)
+			go func() {
+
+				if err := grpcServer.Run(ctx); err != nil {
+					log.Error("gRPC API error", zap.Error(err))
+				}
+			}()
+		}
+	}
+
 	// Wait for shutdown signal
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
@@ -160,6 +305,12 @@ func run(ctx context.Context, cfg *config.Config, log *zap.Logger) error {
 		log.Error("Error stopping stream service", zap.Error(err))
 	}
 
+	// Send the live data still batched
+	if err := pub.Close(); err != nil {
+
+		log.Error("Error closing publisher", zap.Error(err))
+	}
+
 	log.Info("Services stopped successfully")
 	return nil
 }
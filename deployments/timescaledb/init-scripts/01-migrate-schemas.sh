@@ -5,29 +5,39 @@ echo "========================================"
 echo "Starting schema migrations..."
 echo "========================================"
 
-# Define the schema directory relative to where SQL files are mounted in the container
-SCHEMA_DIR="/sql/schemas"
+# Define the migration directory relative to where SQL files are mounted in the container
+MIGRATION_DIR="/sql/migrations"
 
-# Array of schema files in the order they should be executed
-SCHEMA_FILES=(
-    "symbols.sql"
-    "sync_status.sql"
-    "klines.sql"
-    "tickers.sql"
-)
+# Applied migrations are recorded like `binance-cli db migrate up` does, so the
+# application only runs migrations added after the container was created
+psql -v ON_ERROR_STOP=1 --username "$POSTGRES_USER" --dbname "$POSTGRES_DB" <<-EOSQL
+    CREATE TABLE IF NOT EXISTS schema_migrations (
+        version BIGINT PRIMARY KEY,
+        name VARCHAR(100) NOT NULL,
+        applied_at BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW()) * 1000
+    );
+EOSQL
 
-# Execute each schema file
-for file in "${SCHEMA_FILES[@]}"; do
-    if [ -f "$SCHEMA_DIR/$file" ]; then
-        echo "Executing schema: $file"
-        psql -v ON_ERROR_STOP=1 --username "$POSTGRES_USER" --dbname "$POSTGRES_DB" -f "$SCHEMA_DIR/$file"
-        echo "✓ Successfully executed: $file"
-    else
-        echo "⚠ Warning: Schema file not found: $SCHEMA_DIR/$file"
+# Execute each up migration in version order, together with its schema_migrations row.
+# Migrations starting with "-- migrate:no-transaction" run statement by statement.
+for path in $(ls "$MIGRATION_DIR"/*.up.sql | sort); do
+    file=$(basename "$path")
+    base=${file%.up.sql}
+    version=$((10#${base%%_*}))
+    name=${base#*_}
+
+    transaction=--single-transaction
+    if head -n 1 "$path" | grep -q '^-- migrate:no-transaction'; then
+        transaction=
     fi
+
+    echo "Applying migration: $file"
+    psql -v ON_ERROR_STOP=1 $transaction --username "$POSTGRES_USER" --dbname "$POSTGRES_DB" \
+        -f "$path" \
+        -c "INSERT INTO schema_migrations (version, name) VALUES ($version, '$name') ON CONFLICT DO NOTHING"
+    echo "✓ Successfully applied: $file"
 done
 
 echo "========================================"
 echo "Schema migrations completed!"
 echo "========================================"
-
@@ -0,0 +1,174 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/binance-live/internal/binance"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// Response media types
+const (
+	contentTypeJSON     = "application/json"
+	contentTypeProtobuf = "application/x-protobuf"
+)
+
+// jsonOptions renders messages with their proto field names, including zero values.
+// 64-bit integers are strings, as in every proto3 JSON mapping.
+var jsonOptions = protojson.MarshalOptions{
+	UseProtoNames:   true,
+	EmitUnpopulated: true,
+}
+
+// wantsProtobuf reports whether the client asked for protobuf, with format=protobuf or
+// an Accept header listing application/x-protobuf or application/protobuf
+func wantsProtobuf(r *http.Request) bool {
+	if format := r.URL.Query().Get("format"); format != "" {
+		return format == "protobuf"
+	}
+
+	for _, accept := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(accept))
+		if err != nil {
+			continue
+		}
+		if mediaType == contentTypeProtobuf || mediaType == "application/protobuf" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// writeMessage writes msg as protobuf or JSON, as negotiated with the client
+func (s *Server) writeMessage(w http.ResponseWriter, r *http.Request, msg proto.Message) {
+	contentType, marshal := contentTypeJSON, jsonOptions.Marshal
+	if wantsProtobuf(r) {
+		contentType, marshal = contentTypeProtobuf, proto.Marshal
+	}
+
+	body, err := marshal(msg)
+	if err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, fmt.Errorf("failed to encode response: %w", err))
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Add("Vary", "Accept")
+	w.Write(body)
+}
+
+// writeError writes a JSON error body. Server errors are logged and not shown to the client.
+func (s *Server) writeError(w http.ResponseWriter, r *http.Request, status int, err error) {
+	message := err.Error()
+	if status >= http.StatusInternalServerError {
+		s.logger.Error("HTTP API request failed",
+			zap.String("path", r.URL.Path),
+			zap.String("query", r.URL.RawQuery),
+			zap.Error(err),
+		)
+		message = http.StatusText(status)
+	}
+
+	w.Header().Set("Content-Type", contentTypeJSON)
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
+
+// requireSymbol returns the upper-cased symbol parameter
+func requireSymbol(query url.Values) (string, error) {
+	symbol := strings.ToUpper(strings.TrimSpace(query.Get("symbol")))
+	if symbol == "" {
+		return "", fmt.Errorf("symbol is required")
+	}
+	return symbol, nil
+}
+
+// requireInterval returns the interval parameter, which must be a Binance kline interval
+func requireInterval(query url.Values) (string, error) {
+	value := query.Get("interval")
+	if value == "" {
+		return "", fmt.Errorf("interval is required")
+	}
+
+	interval, err := binance.ParseInterval(value)
+	if err != nil {
+		return "", err
+	}
+	return interval.String(), nil
+}
+
+// parseTimeRange returns the start (inclusive) and end (exclusive) parameters in Unix
+// milliseconds. Both accept milliseconds or RFC 3339; the range is unbounded by default.
+func parseTimeRange(query url.Values) (int64, int64, error) {
+	start, err := parseTime(query, "start", 0)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	end, err := parseTime(query, "end", math.MaxInt64)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if end <= start {
+		return 0, 0, fmt.Errorf("end must be after start")
+	}
+	return start, end, nil
+}
+
+// parseTime parses a Unix millisecond or RFC 3339 time parameter
+func parseTime(query url.Values, name string, defaultValue int64) (int64, error) {
+	value := query.Get(name)
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	if millis, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return millis, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q: use Unix milliseconds or RFC 3339", name, value)
+	}
+	return t.UnixMilli(), nil
+}
+
+// parseLimit returns the limit parameter, between 1 and the configured maximum
+func (s *Server) parseLimit(query url.Values) (int, error) {
+	value := query.Get("limit")
+	if value == "" {
+		return s.config.DefaultLimit, nil
+	}
+
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 1 || limit > s.config.MaxLimit {
+		return 0, fmt.Errorf("limit must be between 1 and %d", s.config.MaxLimit)
+	}
+	return limit, nil
+}
+
+// parseBool parses an optional boolean parameter
+func parseBool(query url.Values, name string) (bool, error) {
+	value := query.Get(name)
+	if value == "" {
+		return false, nil
+	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s %q", name, value)
+	}
+	return b, nil
+}
@@ -0,0 +1,39 @@
+package api
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWantsProtobuf(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+		accept string
+		want   bool
+	}{
+		{name: "default", target: "/v1/klines", want: false},
+		{name: "format protobuf", target: "/v1/klines?format=protobuf", want: true},
+		{name: "format json", target: "/v1/klines?format=json", want: false},
+		{name: "accept x-protobuf", target: "/v1/klines", accept: "application/x-protobuf", want: true},
+		{name: "accept protobuf", target: "/v1/klines", accept: "application/protobuf", want: true},
+		{name: "accept list", target: "/v1/klines", accept: "text/html, application/x-protobuf;q=0.9", want: true},
+		{name: "accept json", target: "/v1/klines", accept: "application/json", want: false},
+		{name: "accept invalid", target: "/v1/klines", accept: ";;;", want: false},
+		{name: "format overrides accept", target: "/v1/klines?format=json", accept: "application/x-protobuf", want: false},
+		{name: "format protobuf overrides accept", target: "/v1/klines?format=protobuf", accept: "application/json", want: true},
+		{name: "unknown format", target: "/v1/klines?format=xml", accept: "application/x-protobuf", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tt.target, nil)
+			if tt.accept != "" {
+				r.Header.Set("Accept", tt.accept)
+			}
+			if got := wantsProtobuf(r); got != tt.want {
+				t.Errorf("wantsProtobuf() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,549 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/binance-live/internal/config"
+	"github.com/binance-live/internal/publisher"
+	"github.com/binance-live/internal/redis"
+	binanceProto "github.com/binance-live/proto"
+	"github.com/gorilla/websocket"
+	goredis "github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+)
+
+// Gateway sources
+const (
+	GatewaySourceRedis     = "redis"     // Relay the Redis pub/sub channels
+	GatewaySourceInProcess = "inprocess" // Relay the server's own publisher through the hub
+)
+
+const (
+	gatewayWriteWait   = 10 * time.Second
+	gatewayPongWait    = 60 * time.Second
+	gatewayPingPeriod  = gatewayPongWait * 9 / 10
+	gatewayMaxRequest  = 4096 // Largest client message in bytes
+	gatewayMaxChannels = 256  // Channels one client may subscribe to
+	gatewayHubBuffer   = 8192 // Messages buffered between the hub and the gateway
+)
+
+// Gateway relays live data channels to browser WebSocket clients. Clients subscribe to
+// channels named like the Redis ones, e.g. binance:kline:BTCUSDT:1m, and receive
+// ChannelData messages as JSON text frames, or protobuf binary frames when connecting
+// with format=protobuf. Each client has a bounded send buffer; a client that falls
+// further behind is disconnected.
+type Gateway struct {
+	config   *config.WebSocketConfig
+	redis    *redis.Client
+	hub      *publisher.Hub  // nil: relay from Redis
+	pubsub   *goredis.PubSub // nil: relay from the hub
+	upgrader websocket.Upgrader
+	mu       sync.RWMutex
+	channels map[string]map[*gatewayClient]struct{}
+	clients  map[*gatewayClient]struct{}
+
+	// redisMu serializes the Redis subscription changes, made outside mu so that a slow
+	// Redis does not hold up dispatching
+	redisMu    sync.Mutex
+	subscribed map[string]struct{} // guarded by redisMu
+
+	logger *zap.Logger
+}
+
+// gatewayClient is one WebSocket connection
+type gatewayClient struct {
+	conn     *websocket.Conn
+	protobuf bool
+	send     chan gatewayFrame
+	channels map[string]struct{} // guarded by Gateway.mu
+	slow     chan struct{}       // closed when the send buffer overflows
+	slowOnce sync.Once
+	closed   chan struct{} // closed when the client stops reading
+}
+
+// gatewayFrame is an encoded WebSocket message
+type gatewayFrame struct {
+	messageType int
+	data        []byte
+}
+
+// gatewayRequest is a client message, e.g.
+// {"action": "subscribe", "channels": ["binance:ticker:BTCUSDT"]}
+type gatewayRequest struct {
+	Action   string   `json:"action"`
+	Channels []string `json:"channels"`
+}
+
+// gatewayEvent is a reply to a client message, always sent as JSON
+type gatewayEvent struct {
+	Event    string   `json:"event"`
+	Channels []string `json:"channels,omitempty"`
+	Error    string   `json:"error,omitempty"`
+}
+
+// NewGateway creates a new WebSocket gateway relaying from Redis or, with the
+// inprocess source, from hub
+func NewGateway(cfg *config.WebSocketConfig, redisClient *redis.Client, hub *publisher.Hub, logger *zap.Logger) (*Gateway, error) {
+	g := &Gateway{
+		config:   cfg,
+		redis:    redisClient,
+		channels: make(map[string]map[*gatewayClient]struct{}),
+		clients:  make(map[*gatewayClient]struct{}),
+		logger:   logger,
+
+		subscribed: make(map[string]struct{}),
+	}
+
+	switch cfg.Source {
+	case GatewaySourceRedis:
+		g.pubsub = redisClient.Subscribe(context.Background())
+	case GatewaySourceInProcess:
+		if hub == nil {
+			return nil, fmt.Errorf("the inprocess WebSocket source needs the publisher hub")
+		}
+		g.hub = hub
+	default:
+		return nil, fmt.Errorf("unknown WebSocket source %q", cfg.Source)
+	}
+
+	g.upgrader = websocket.Upgrader{
+		ReadBufferSize:  1024,
+		WriteBufferSize: 4096,
+		CheckOrigin:     g.checkOrigin,
+	}
+
+	return g, nil
+}
+
+// Run relays live data to the clients until ctx is cancelled, then disconnects them
+func (g *Gateway) Run(ctx context.Context) error {
+	defer g.closeClients()
+
+	if g.hub != nil {
+		return g.relayHub(ctx)
+	}
+	return g.relayRedis(ctx)
+}
+
+// relayHub relays the hub's messages, subscribing again if the gateway falls behind
+func (g *Gateway) relayHub(ctx context.Context) error {
+	for {
+		sub := g.hub.Subscribe(publisher.SubscriptionFilter{}, gatewayHubBuffer)
+
+		var dropped bool
+		func() {
+			defer sub.Close()
+			for {
+				select {
+				case msg := <-sub.C():
+					g.dispatch(msg.Channel, msg.Data)
+
+					// Closed klines also go to their own channel, numbered by its own sequence as
+					// with the Redis source
+					if msg.Closed != nil {
+						g.dispatch(publisher.ClosedKlineChannel(msg.Closed.Symbol, msg.Closed.GetKline().GetInterval()), msg.Closed)
+					}
+				case <-sub.Done():
+					dropped = true
+					return
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+
+		if !dropped {
+			return nil
+		}
+		g.logger.Warn("WebSocket gateway fell behind the publisher, messages were dropped")
+	}
+}
+
+// relayRedis relays the messages of the Redis channels clients are subscribed to
+func (g *Gateway) relayRedis(ctx context.Context) error {
+	defer g.pubsub.Close()
+
+	messages := g.pubsub.Channel()
+	for {
+		select {
+		case msg, ok := <-messages:
+			if !ok {
+				return fmt.Errorf("redis subscription closed")
+			}
+
+			var liveData binanceProto.LiveData
+			if err := proto.Unmarshal([]byte(msg.Payload), &liveData); err != nil {
+				g.logger.Warn("Failed to decode live data from Redis",
+					zap.String("channel", msg.Channel),
+					zap.Error(err),
+				)
+				continue
+			}
+			g.dispatch(msg.Channel, &liveData)
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// dispatch sends liveData to the clients subscribed to channel, encoding it at most
+// once per format. Clients of a format that fails to encode are skipped.
+func (g *Gateway) dispatch(channel string, liveData *binanceProto.LiveData) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	var (
+		frames [2]*gatewayFrame // JSON, protobuf
+		failed [2]bool
+	)
+	for client := range g.channels[channel] {
+		format := 0
+		if client.protobuf {
+			format = 1
+		}
+		if failed[format] {
+			continue
+		}
+
+		if frames[format] == nil {
+			frame, err := encodeChannelData(channel, liveData, client.protobuf)
+			if err != nil {
+				g.logger.Warn("Failed to encode live data", zap.String("channel", channel), zap.Error(err))
+				failed[format] = true
+				continue
+			}
+			frames[format] = &frame
+		}
+
+		client.enqueue(*frames[format])
+	}
+}
+
+// encodeChannelData encodes liveData of channel as a ChannelData frame
+func encodeChannelData(channel string, liveData *binanceProto.LiveData, protobuf bool) (gatewayFrame, error) {
+	msg := &binanceProto.ChannelData{Channel: channel, Data: liveData}
+	if protobuf {
+		data, err := proto.Marshal(msg)
+		return gatewayFrame{messageType: websocket.BinaryMessage, data: data}, err
+	}
+
+	data, err := jsonOptions.Marshal(msg)
+	return gatewayFrame{messageType: websocket.TextMessage, data: data}, err
+}
+
+// handleWebSocket serves GET /v1/ws?format, upgrading the request to a gateway connection
+func (g *Gateway) handleWebSocket(w http.ResponseWriter, r *http.Request) {
+	conn, err := g.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade has already replied with an HTTP error
+		return
+	}
+
+	client := &gatewayClient{
+		conn:     conn,
+		protobuf: wantsProtobuf(r),
+		send:     make(chan gatewayFrame, max(g.config.ClientBuffer, 1)),
+		channels: make(map[string]struct{}),
+		slow:     make(chan struct{}),
+		closed:   make(chan struct{}),
+	}
+
+	g.mu.Lock()
+	g.clients[client] = struct{}{}
+	g.mu.Unlock()
+
+	go g.writePump(client)
+	g.readPump(client)
+}
+
+// readPump handles the client's messages until the connection fails or closes
+func (g *Gateway) readPump(client *gatewayClient) {
+	defer func() {
+		g.removeClient(client)
+		close(client.closed)
+		client.conn.Close()
+	}()
+
+	client.conn.SetReadLimit(gatewayMaxRequest)
+	client.conn.SetReadDeadline(time.Now().Add(gatewayPongWait))
+	client.conn.SetPongHandler(func(string) error {
+		return client.conn.SetReadDeadline(time.Now().Add(gatewayPongWait))
+	})
+
+	for {
+		_, message, err := client.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		var req gatewayRequest
+		if err := json.Unmarshal(message, &req); err != nil {
+			client.sendEvent(gatewayEvent{Event: "error", Error: "invalid message: " + err.Error()})
+			continue
+		}
+
+		switch req.Action {
+		case "subscribe":
+			g.subscribe(client, req.Channels)
+		case "unsubscribe":
+			g.unsubscribe(client, req.Channels)
+		default:
+			client.sendEvent(gatewayEvent{Event: "error", Error: fmt.Sprintf("unknown action %q", req.Action)})
+		}
+	}
+}
+
+// writePump writes the client's frames and keepalive pings until the client stops
+// reading, a write fails or the client falls behind
+func (g *Gateway) writePump(client *gatewayClient) {
+	ticker := time.NewTicker(gatewayPingPeriod)
+	defer func() {
+		ticker.Stop()
+		client.conn.Close()
+	}()
+
+	for {
+		select {
+		case frame := <-client.send:
+			client.conn.SetWriteDeadline(time.Now().Add(gatewayWriteWait))
+			if err := client.conn.WriteMessage(frame.messageType, frame.data); err != nil {
+				return
+			}
+		case <-ticker.C:
+			client.conn.SetWriteDeadline(time.Now().Add(gatewayWriteWait))
+			if err := client.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		case <-client.slow:
+			closeMessage := websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "client too slow")
+			client.conn.WriteControl(websocket.CloseMessage, closeMessage, time.Now().Add(gatewayWriteWait))
+			return
+		case <-client.closed:
+			return
+		}
+	}
+}
+
+// subscribe adds the client to channels, then sends the latest cached value of each
+func (g *Gateway) subscribe(client *gatewayClient, channels []string) {
+	subscribed := []string{}
+	for _, name := range channels {
+		channel, err := publisher.ParseChannel(name)
+		if err == nil {
+			err = g.addSubscription(client, channel)
+		}
+		if err != nil {
+			client.sendEvent(gatewayEvent{Event: "error", Error: err.Error()})
+			continue
+		}
+		subscribed = append(subscribed, channel)
+	}
+
+	client.sendEvent(gatewayEvent{Event: "subscribed", Channels: subscribed})
+
+	// Live updates may arrive before the cached value; both carry timestamps
+	for _, channel := range subscribed {
+		g.sendLatest(client, channel)
+	}
+}
+
+// unsubscribe removes the client from channels
+func (g *Gateway) unsubscribe(client *gatewayClient, channels []string) {
+	unsubscribed := []string{}
+	var emptied []string
+
+	g.mu.Lock()
+	for _, name := range channels {
+		channel, err := publisher.ParseChannel(name)
+		if err != nil {
+			continue
+		}
+		if _, ok := client.channels[channel]; ok {
+			if g.removeSubscription(client, channel) {
+				emptied = append(emptied, channel)
+			}
+			unsubscribed = append(unsubscribed, channel)
+		}
+	}
+	g.mu.Unlock()
+
+	for _, channel := range emptied {
+		g.syncRedis(channel)
+	}
+
+	client.sendEvent(gatewayEvent{Event: "unsubscribed", Channels: unsubscribed})
+}
+
+// addSubscription adds the client to channel, subscribing to it in Redis for its first client
+func (g *Gateway) addSubscription(client *gatewayClient, channel string) error {
+	g.mu.Lock()
+	if _, ok := client.channels[channel]; ok {
+		g.mu.Unlock()
+		return nil
+	}
+	if len(client.channels) >= gatewayMaxChannels {
+		g.mu.Unlock()
+		return fmt.Errorf("at most %d channels per connection", gatewayMaxChannels)
+	}
+
+	clients, ok := g.channels[channel]
+	if !ok {
+		clients = make(map[*gatewayClient]struct{})
+		g.channels[channel] = clients
+	}
+	clients[client] = struct{}{}
+	client.channels[channel] = struct{}{}
+	g.mu.Unlock()
+
+	if err := g.syncRedis(channel); err != nil {
+		g.mu.Lock()
+		var emptied bool
+		if _, ok := client.channels[channel]; ok {
+			emptied = g.removeSubscription(client, channel)
+		}
+		g.mu.Unlock()
+
+		if emptied {
+			g.syncRedis(channel)
+		}
+		return fmt.Errorf("failed to subscribe to %s", channel)
+	}
+	return nil
+}
+
+// removeSubscription removes the client from channel and reports whether that was its
+// last client. The caller must hold g.mu and call syncRedis for the emptied channel
+// after releasing it.
+func (g *Gateway) removeSubscription(client *gatewayClient, channel string) bool {
+	delete(client.channels, channel)
+
+	clients := g.channels[channel]
+	delete(clients, client)
+	if len(clients) > 0 {
+		return false
+	}
+
+	delete(g.channels, channel)
+	return true
+}
+
+// syncRedis subscribes to channel in Redis while it has clients and unsubscribes from it
+// once it has none. It must be called without holding g.mu.
+func (g *Gateway) syncRedis(channel string) error {
+	if g.pubsub == nil {
+		return nil
+	}
+
+	g.redisMu.Lock()
+	defer g.redisMu.Unlock()
+
+	g.mu.RLock()
+	_, wanted := g.channels[channel]
+	g.mu.RUnlock()
+	_, subscribed := g.subscribed[channel]
+
+	switch {
+	case wanted && !subscribed:
+		if err := g.pubsub.Subscribe(context.Background(), channel); err != nil {
+			g.logger.Error("Failed to subscribe to Redis channel", zap.String("channel", channel), zap.Error(err))
+			return err
+		}
+		g.subscribed[channel] = struct{}{}
+	case !wanted && subscribed:
+		delete(g.subscribed, channel)
+		if err := g.pubsub.Unsubscribe(context.Background(), channel); err != nil {
+			g.logger.Warn("Failed to unsubscribe from Redis channel", zap.String("channel", channel), zap.Error(err))
+		}
+	}
+	return nil
+}
+
+// removeClient removes a disconnected client from all its channels
+func (g *Gateway) removeClient(client *gatewayClient) {
+	var emptied []string
+
+	g.mu.Lock()
+	for channel := range client.channels {
+		if g.removeSubscription(client, channel) {
+			emptied = append(emptied, channel)
+		}
+	}
+	delete(g.clients, client)
+	g.mu.Unlock()
+
+	for _, channel := range emptied {
+		g.syncRedis(channel)
+	}
+}
+
+// closeClients disconnects every client
+func (g *Gateway) closeClients() {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	for client := range g.clients {
+		client.conn.Close()
+	}
+}
+
+// sendLatest sends the value of channel cached in Redis, if any. Trades are not cached.
+func (g *Gateway) sendLatest(client *gatewayClient, channel string) {
+	ctx, cancel := context.WithTimeout(context.Background(), gatewayWriteWait)
+	defer cancel()
+
+	var liveData binanceProto.LiveData
+	if err := g.redis.GetProtobuf(ctx, publisher.LatestKey(channel), &liveData); err != nil {
+		if !errors.Is(err, redis.ErrKeyNotFound) {
+			g.logger.Warn("Failed to read cached live data", zap.String("channel", channel), zap.Error(err))
+		}
+		return
+	}
+
+	frame, err := encodeChannelData(channel, &liveData, client.protobuf)
+	if err != nil {
+		g.logger.Warn("Failed to encode live data", zap.String("channel", channel), zap.Error(err))
+		return
+	}
+	client.enqueue(frame)
+}
+
+// checkOrigin accepts non-browser clients, the API's own origin and the configured origins
+func (g *Gateway) checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	if slices.Contains(g.config.AllowedOrigins, "*") || slices.Contains(g.config.AllowedOrigins, origin) {
+		return true
+	}
+
+	u, err := url.Parse(origin)
+	return err == nil && u.Host == r.Host
+}
+
+// enqueue queues frame without blocking, marking the client slow when its buffer is full
+func (c *gatewayClient) enqueue(frame gatewayFrame) {
+	select {
+	case c.send <- frame:
+	default:
+		c.slowOnce.Do(func() { close(c.slow) })
+	}
+}
+
+// sendEvent queues a JSON reply
+func (c *gatewayClient) sendEvent(event gatewayEvent) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	c.enqueue(gatewayFrame{messageType: websocket.TextMessage, data: data})
+}
@@ -0,0 +1,182 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/binance-live/internal/config"
+	"github.com/binance-live/internal/models"
+	"github.com/binance-live/internal/publisher"
+	binanceProto "github.com/binance-live/proto"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// newTestClient creates a gateway client without a connection, for driving subscriptions directly
+func newTestClient() *gatewayClient {
+	return &gatewayClient{
+		send:     make(chan gatewayFrame, 16),
+		channels: make(map[string]struct{}),
+		slow:     make(chan struct{}),
+		closed:   make(chan struct{}),
+	}
+}
+
+// nextFrame returns the next frame queued for client, skipping subscription events
+func nextFrame(t *testing.T, client *gatewayClient) *binanceProto.ChannelData {
+	t.Helper()
+
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case frame := <-client.send:
+			var event gatewayEvent
+			if json.Unmarshal(frame.data, &event) == nil && event.Event != "" {
+				continue
+			}
+			var msg binanceProto.ChannelData
+			if err := protojson.Unmarshal(frame.data, &msg); err != nil {
+				t.Fatalf("failed to decode frame %s: %v", frame.data, err)
+			}
+			return &msg
+		case <-timeout:
+			t.Fatal("no frame was relayed")
+			return nil
+		}
+	}
+}
+
+func TestGatewayRedisSubscriptions(t *testing.T) {
+	m, redisClient := testRedis(t)
+
+	cfg := &config.WebSocketConfig{Source: GatewaySourceRedis, ClientBuffer: 16}
+	g, err := NewGateway(cfg, redisClient, nil, zap.NewNop())
+	if err != nil {
+		t.Fatalf("failed to create gateway: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go g.Run(ctx)
+
+	channel := publisher.TickerChannel("BTCUSDT")
+	// go-redis does not wait for the replies to SUBSCRIBE and UNSUBSCRIBE
+	numSub := func(want int) int {
+		deadline := time.Now().Add(2 * time.Second)
+		n := m.PubSubNumSub(channel)[channel]
+		for n != want && time.Now().Before(deadline) {
+			time.Sleep(10 * time.Millisecond)
+			n = m.PubSubNumSub(channel)[channel]
+		}
+		return n
+	}
+
+	first, second := newTestClient(), newTestClient()
+	g.subscribe(first, []string{channel})
+	g.subscribe(second, []string{channel})
+	if n := numSub(1); n != 1 {
+		t.Fatalf("got %d Redis subscribers with two clients, want 1", n)
+	}
+
+	data, err := proto.Marshal(&binanceProto.LiveData{Type: binanceProto.DataType_DATA_TYPE_TICKER, Symbol: "BTCUSDT"})
+	if err != nil {
+		t.Fatalf("failed to marshal ticker: %v", err)
+	}
+	m.Publish(channel, string(data))
+
+	for _, client := range []*gatewayClient{first, second} {
+		if msg := nextFrame(t, client); msg.Channel != channel || msg.GetData().Symbol != "BTCUSDT" {
+			t.Errorf("got %v, want the BTCUSDT ticker on %s", msg, channel)
+		}
+	}
+
+	g.unsubscribe(first, []string{channel})
+	if n := numSub(1); n != 1 {
+		t.Errorf("got %d Redis subscribers with one client left, want 1", n)
+	}
+
+	g.removeClient(second)
+	if n := numSub(0); n != 0 {
+		t.Errorf("got %d Redis subscribers without clients, want 0", n)
+	}
+
+	// A new first client subscribes again
+	g.subscribe(first, []string{channel})
+	if n := numSub(1); n != 1 {
+		t.Errorf("got %d Redis subscribers after subscribing again, want 1", n)
+	}
+}
+
+// nopPublisher accepts every message
+type nopPublisher struct {
+	publisher.Publisher
+}
+
+func (nopPublisher) PublishKline(ctx context.Context, kline *models.Kline) error    { return nil }
+func (nopPublisher) PublishBar(ctx context.Context, bar *models.Bar) error          { return nil }
+func (nopPublisher) PublishTicker(ctx context.Context, ticker *models.Ticker) error { return nil }
+
+func TestGatewayHubClosedKlineSequence(t *testing.T) {
+	_, redisClient := testRedis(t)
+	hub := publisher.NewHub(nopPublisher{})
+
+	cfg := &config.WebSocketConfig{Source: GatewaySourceInProcess, ClientBuffer: 16}
+	g, err := NewGateway(cfg, redisClient, hub, zap.NewNop())
+	if err != nil {
+		t.Fatalf("failed to create gateway: %v", err)
+	}
+
+	client := newTestClient()
+	klineChannel := publisher.KlineChannel("BTCUSDT", "1m")
+	closedChannel := publisher.ClosedKlineChannel("BTCUSDT", "1m")
+	g.subscribe(client, []string{klineChannel, closedChannel})
+	<-client.send // The subscribed event
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go g.Run(ctx)
+
+	// The gateway subscribes to the hub when it starts running
+	klines := []*models.Kline{
+		{Symbol: "BTCUSDT", Interval: "1m", OpenTime: 0},
+		{Symbol: "BTCUSDT", Interval: "1m", OpenTime: 0, IsClosed: true},
+		{Symbol: "BTCUSDT", Interval: "1m", OpenTime: 60000},
+		{Symbol: "BTCUSDT", Interval: "1m", OpenTime: 60000, IsClosed: true},
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		hub.PublishKline(context.Background(), klines[0])
+		select {
+		case <-client.send:
+		case <-time.After(10 * time.Millisecond):
+			if time.Now().After(deadline) {
+				t.Fatal("gateway did not subscribe to the hub")
+			}
+			continue
+		}
+		break
+	}
+	for _, kline := range klines[1:] {
+		hub.PublishKline(context.Background(), kline)
+	}
+
+	// Each channel has its own sequence, as with the Redis source
+	sequences := map[string][]uint64{}
+	for len(sequences[closedChannel]) < 2 {
+		msg := nextFrame(t, client)
+		sequences[msg.Channel] = append(sequences[msg.Channel], msg.GetData().GetMetadata().GetSequence())
+	}
+	if got := sequences[closedChannel]; got[0] != 1 || got[1] != 2 {
+		t.Errorf("got closed kline sequences %v, want [1 2]", got)
+	}
+	got := sequences[klineChannel]
+	for i := 1; i < len(got); i++ {
+		if got[i] != got[i-1]+1 {
+			t.Errorf("got kline sequences %v, want consecutive ones", got)
+			break
+		}
+	}
+}
@@ -0,0 +1,235 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/binance-live/internal/binance"
+	"github.com/binance-live/internal/config"
+	"github.com/binance-live/internal/publisher"
+	binanceProto "github.com/binance-live/proto"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GRPCServer serves the MarketDataService: history through the HTTP API server's
+// repositories and live data from the publisher hub
+type GRPCServer struct {
+	binanceProto.UnimplementedMarketDataServiceServer
+
+	config   *config.GRPCConfig
+	api      *Server
+	hub      *publisher.Hub
+	stopping chan struct{}
+	logger   *zap.Logger
+}
+
+// NewGRPCServer creates a new gRPC server. Page limits follow the API server's config.
+func NewGRPCServer(cfg *config.GRPCConfig, apiServer *Server, hub *publisher.Hub, logger *zap.Logger) *GRPCServer {
+	return &GRPCServer{
+		config:   cfg,
+		api:      apiServer,
+		hub:      hub,
+		stopping: make(chan struct{}),
+		logger:   logger,
+	}
+}
+
+// Run serves gRPC until ctx is cancelled. Subscribe streams are ended first so unary
+// calls in flight can finish.
+func (s *GRPCServer) Run(ctx context.Context) error {
+	listener, err := net.Listen("tcp", s.config.GetAddr())
+	if err != nil {
+		return fmt.Errorf("failed to listen for gRPC: %w", err)
+	}
+
+	server := grpc.NewServer()
+	binanceProto.RegisterMarketDataServiceServer(server, s)
+
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- server.Serve(listener)
+	}()
+
+	s.logger.Info("gRPC API listening", zap.String("addr", listener.Addr().String()))
+
+	select {
+	case err := <-errChan:
+		return fmt.Errorf("gRPC API stopped: %w", err)
+	case <-ctx.Done():
+	}
+
+	close(s.stopping)
+
+	stopped := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(shutdownTimeout):
+		server.Stop()
+	}
+
+	return nil
+}
+
+// GetKlines returns a page of klines, oldest first
+func (s *GRPCServer) GetKlines(ctx context.Context, req *binanceProto.GetKlinesRequest) (*binanceProto.LiveDataList, error) {
+	symbol, err := grpcSymbol(req.Symbol)
+	if err != nil {
+		return nil, err
+	}
+	interval, err := binance.ParseInterval(req.Interval)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	start, end, err := grpcTimeRange(req.StartTime, req.EndTime)
+	if err != nil {
+		return nil, err
+	}
+	limit, err := s.limit(req.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Cursor != "" {
+		start, err = parseKlineCursor(req.Cursor)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+
+	list, err := s.api.klinePage(ctx, symbol, interval.String(), start, end, limit)
+	if err != nil {
+		return nil, s.internalError("GetKlines", err)
+	}
+	return list, nil
+}
+
+// GetTrades returns a page of aggregated trades in trade order
+func (s *GRPCServer) GetTrades(ctx context.Context, req *binanceProto.GetTradesRequest) (*binanceProto.LiveDataList, error) {
+	symbol, err := grpcSymbol(req.Symbol)
+	if err != nil {
+		return nil, err
+	}
+	start, end, err := grpcTimeRange(req.StartTime, req.EndTime)
+	if err != nil {
+		return nil, err
+	}
+	limit, err := s.limit(req.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	afterTime, afterTradeID := start, int64(-1)
+	if req.Cursor != "" {
+		afterTime, afterTradeID, err = parseTradeCursor(req.Cursor)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+
+	list, err := s.api.tradePage(ctx, symbol, afterTime, afterTradeID, end, limit)
+	if err != nil {
+		return nil, s.internalError("GetTrades", err)
+	}
+	return list, nil
+}
+
+// GetLatestTicker returns the newest stored ticker of a symbol
+func (s *GRPCServer) GetLatestTicker(ctx context.Context, req *binanceProto.GetLatestTickerRequest) (*binanceProto.LiveData, error) {
+	symbol, err := grpcSymbol(req.Symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	ticker, err := s.api.tickerRepo.GetLatestTicker(ctx, symbol)
+	if err != nil {
+		return nil, s.internalError("GetLatestTicker", err)
+	}
+	if ticker == nil {
+		return nil, status.Errorf(codes.NotFound, "no ticker stored for %s", symbol)
+	}
+
+	return publisher.TickerLiveData(ticker), nil
+}
+
+// Subscribe streams the live data matching the request until the client goes away or
+// the server stops. A client that falls behind by more than the subscriber buffer is
+// disconnected with RESOURCE_EXHAUSTED.
+func (s *GRPCServer) Subscribe(req *binanceProto.SubscribeRequest, stream grpc.ServerStreamingServer[binanceProto.LiveData]) error {
+	filter := publisher.SubscriptionFilter{
+		Types:     req.Types,
+		Intervals: req.Intervals,
+	}
+	for _, symbol := range req.Symbols {
+		filter.Symbols = append(filter.Symbols, strings.ToUpper(strings.TrimSpace(symbol)))
+	}
+
+	sub := s.hub.Subscribe(filter, s.config.SubscriberBuffer)
+	defer sub.Close()
+
+	for {
+		select {
+		case msg := <-sub.C():
+			if err := stream.Send(msg.Data); err != nil {
+				return err
+			}
+		case <-sub.Done():
+			if err := sub.Err(); err != nil {
+				return status.Error(codes.ResourceExhausted, err.Error())
+			}
+			return nil
+		case <-stream.Context().Done():
+			return nil
+		case <-s.stopping:
+			return status.Error(codes.Unavailable, "server shutting down")
+		}
+	}
+}
+
+// limit returns the page size of a request, the API default when 0
+func (s *GRPCServer) limit(limit int32) (int, error) {
+	if limit == 0 {
+		return s.api.config.DefaultLimit, nil
+	}
+	if limit < 0 || int(limit) > s.api.config.MaxLimit {
+		return 0, status.Errorf(codes.InvalidArgument, "limit must be between 1 and %d", s.api.config.MaxLimit)
+	}
+	return int(limit), nil
+}
+
+// internalError logs err and returns an INTERNAL status that does not expose it
+func (s *GRPCServer) internalError(method string, err error) error {
+	s.logger.Error("gRPC request failed", zap.String("method", method), zap.Error(err))
+	return status.Error(codes.Internal, "internal error")
+}
+
+// grpcSymbol returns the upper-cased symbol of a request
+func grpcSymbol(symbol string) (string, error) {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+	if symbol == "" {
+		return "", status.Error(codes.InvalidArgument, "symbol is required")
+	}
+	return symbol, nil
+}
+
+// grpcTimeRange returns the [start, end) range of a request; an end of 0 is unbounded
+func grpcTimeRange(start, end int64) (int64, int64, error) {
+	if end == 0 {
+		end = math.MaxInt64
+	}
+	if end <= start {
+		return 0, 0, status.Error(codes.InvalidArgument, "end_time must be after start_time")
+	}
+	return start, end, nil
+}
@@ -0,0 +1,138 @@
+package api
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/binance-live/internal/config"
+	"github.com/binance-live/internal/models"
+	"github.com/binance-live/internal/publisher"
+	binanceProto "github.com/binance-live/proto"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// testGRPCClient serves a gRPC server relaying hub over an in-memory connection and
+// returns a client of it
+func testGRPCClient(t *testing.T, hub *publisher.Hub, subscriberBuffer int) binanceProto.MarketDataServiceClient {
+	t.Helper()
+
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	binanceProto.RegisterMarketDataServiceServer(server, NewGRPCServer(&config.GRPCConfig{SubscriberBuffer: subscriberBuffer}, nil, hub, zap.NewNop()))
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("failed to connect to gRPC server: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return binanceProto.NewMarketDataServiceClient(conn)
+}
+
+// subscribe opens a Subscribe stream and waits until the hub delivers to it, publishing
+// klines of symbol and interval opening at -1, which the filter must match
+func subscribe(t *testing.T, ctx context.Context, client binanceProto.MarketDataServiceClient, hub *publisher.Hub, req *binanceProto.SubscribeRequest, symbol, interval string) grpc.ServerStreamingClient[binanceProto.LiveData] {
+	t.Helper()
+
+	stream, err := client.Subscribe(ctx, req)
+	if err != nil {
+		t.Fatalf("failed to subscribe: %v", err)
+	}
+
+	// The server registers the subscription once it handles the call
+	received := make(chan error, 1)
+	go func() {
+		_, err := stream.Recv()
+		received <- err
+	}()
+	for {
+		hub.PublishKline(context.Background(), &models.Kline{Symbol: symbol, Interval: interval, OpenTime: -1})
+		select {
+		case err := <-received:
+			if err != nil {
+				t.Fatalf("failed to receive: %v", err)
+			}
+			return stream
+		case <-time.After(10 * time.Millisecond):
+		case <-ctx.Done():
+			t.Fatal("subscription did not start")
+		}
+	}
+}
+
+func TestGRPCSubscribeFilter(t *testing.T) {
+	hub := publisher.NewHub(nopPublisher{})
+	client := testGRPCClient(t, hub, 64)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream := subscribe(t, ctx, client, hub, &binanceProto.SubscribeRequest{
+		Symbols:   []string{" btcusdt "},
+		Types:     []binanceProto.DataType{binanceProto.DataType_DATA_TYPE_KLINE},
+		Intervals: []string{"1m", "tick:100"},
+	}, "BTCUSDT", "1m")
+
+	hub.PublishKline(ctx, &models.Kline{Symbol: "BTCUSDT", Interval: "5m", OpenTime: 1})
+	hub.PublishKline(ctx, &models.Kline{Symbol: "ETHUSDT", Interval: "1m", OpenTime: 2})
+	hub.PublishTicker(ctx, &models.Ticker{Symbol: "BTCUSDT"})
+	hub.PublishBar(ctx, &models.Bar{Symbol: "BTCUSDT", Spec: "volume:10", OpenTime: 3})
+	hub.PublishBar(ctx, &models.Bar{Symbol: "BTCUSDT", Spec: "tick:100", OpenTime: 4})
+	hub.PublishKline(ctx, &models.Kline{Symbol: "BTCUSDT", Interval: "1m", OpenTime: 5})
+
+	var got []string
+	for len(got) < 2 {
+		liveData, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("failed to receive: %v", err)
+		}
+		kline := liveData.GetKline()
+		if kline.GetOpenTime() == -1 {
+			continue // Sent while waiting for the subscription
+		}
+		got = append(got, liveData.Symbol+" "+kline.GetInterval())
+	}
+
+	if got[0] != "BTCUSDT tick:100" || got[1] != "BTCUSDT 1m" {
+		t.Errorf("got %v, want the BTCUSDT tick:100 bar and 1m kline", got)
+	}
+}
+
+func TestGRPCSubscribeSlowSubscriber(t *testing.T) {
+	hub := publisher.NewHub(nopPublisher{})
+	client := testGRPCClient(t, hub, 1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream := subscribe(t, ctx, client, hub, &binanceProto.SubscribeRequest{}, "BTCUSDT", "1m")
+
+	// The client reads nothing: once the connection's flow control window is full the
+	// server cannot send, and the one message buffer overflows
+	for i := range 100000 {
+		hub.PublishTicker(ctx, &models.Ticker{Symbol: "BTCUSDT", Price: float64(i)})
+	}
+
+	for {
+		_, err := stream.Recv()
+		if err == nil {
+			continue
+		}
+		if status.Code(err) != codes.ResourceExhausted {
+			t.Fatalf("got error %v, want RESOURCE_EXHAUSTED", err)
+		}
+		break
+	}
+}
@@ -0,0 +1,333 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/binance-live/internal/models"
+	"github.com/binance-live/internal/publisher"
+	"github.com/binance-live/internal/redis"
+	"github.com/binance-live/internal/repository"
+	binanceProto "github.com/binance-live/proto"
+)
+
+// handleKlines serves GET /v1/klines?symbol&interval&start&end&limit&cursor, the klines
+// with open times in [start, end) oldest first. cursor is the next_cursor of the
+// previous page and replaces start.
+func (s *Server) handleKlines(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	symbol, err := requireSymbol(query)
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	interval, err := requireInterval(query)
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	start, end, err := parseTimeRange(query)
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	limit, err := s.parseLimit(query)
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	if cursor := query.Get("cursor"); cursor != "" {
+		start, err = parseKlineCursor(cursor)
+		if err != nil {
+			s.writeError(w, r, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	list, err := s.klinePage(r.Context(), symbol, interval, start, end, limit)
+	if err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	s.writeMessage(w, r, list)
+}
+
+// klinePage reads at most limit klines with open times in [start, end), with the
+// cursor of the next page if there is one
+func (s *Server) klinePage(ctx context.Context, symbol, interval string, start, end int64, limit int) (*binanceProto.LiveDataList, error) {
+	// Read one extra kline to know whether another page follows
+	klines, err := s.klineRepo.GetKlinesPage(ctx, symbol, interval, start, end, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	list := &binanceProto.LiveDataList{}
+	if len(klines) > limit {
+		klines = klines[:limit]
+		list.NextCursor = strconv.FormatInt(klines[limit-1].OpenTime+1, 10)
+	}
+	list.Items = klineItems(klines)
+
+	return list, nil
+}
+
+// klineItems converts stored klines, which are closed once their close time has passed
+func klineItems(klines []models.Kline) []*binanceProto.LiveData {
+	now := time.Now().UnixMilli()
+	items := make([]*binanceProto.LiveData, 0, len(klines))
+	for i := range klines {
+		klines[i].IsClosed = klines[i].CloseTime < now
+		items = append(items, publisher.KlineLiveData(&klines[i]))
+	}
+	return items
+}
+
+// parseKlineCursor parses a kline cursor, the open time the next page starts at
+func parseKlineCursor(cursor string) (int64, error) {
+	start, err := strconv.ParseInt(cursor, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid cursor %q", cursor)
+	}
+	return start, nil
+}
+
+// handleLatestKlines serves GET /v1/klines/latest?symbol&interval&limit, the newest
+// klines oldest first
+func (s *Server) handleLatestKlines(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	symbol, err := requireSymbol(query)
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	interval, err := requireInterval(query)
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	limit, err := s.parseLimit(query)
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	klines, err := s.klineRepo.GetLatestKlines(r.Context(), symbol, interval, limit)
+	if err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	s.writeMessage(w, r, &binanceProto.LiveDataList{Items: klineItems(klines)})
+}
+
+// handleLatestTickers serves GET /v1/tickers/latest?symbol, the newest stored ticker of
+// symbol, or of every symbol when none is given
+func (s *Server) handleLatestTickers(w http.ResponseWriter, r *http.Request) {
+	var tickers []models.Ticker
+	if symbol := strings.ToUpper(strings.TrimSpace(r.URL.Query().Get("symbol"))); symbol != "" {
+		ticker, err := s.tickerRepo.GetLatestTicker(r.Context(), symbol)
+		if err != nil {
+			s.writeError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		if ticker == nil {
+			s.writeError(w, r, http.StatusNotFound, fmt.Errorf("no ticker stored for %s", symbol))
+			return
+		}
+		tickers = append(tickers, *ticker)
+	} else {
+		var err error
+		tickers, err = s.tickerRepo.GetAllLatestTickers(r.Context())
+		if err != nil {
+			s.writeError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	list := &binanceProto.LiveDataList{}
+	for i := range tickers {
+		list.Items = append(list.Items, publisher.TickerLiveData(&tickers[i]))
+	}
+
+	s.writeMessage(w, r, list)
+}
+
+// handleTrades serves GET /v1/trades?symbol&start&end&limit&cursor, the aggregated
+// trades with timestamps in [start, end) in trade order. cursor is the next_cursor of
+// the previous page and replaces start.
+func (s *Server) handleTrades(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	symbol, err := requireSymbol(query)
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	start, end, err := parseTimeRange(query)
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+	limit, err := s.parseLimit(query)
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	// The cursor is the timestamp and trade ID of the last trade returned
+	afterTime, afterTradeID := start, int64(-1)
+	if cursor := query.Get("cursor"); cursor != "" {
+		afterTime, afterTradeID, err = parseTradeCursor(cursor)
+		if err != nil {
+			s.writeError(w, r, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	list, err := s.tradePage(r.Context(), symbol, afterTime, afterTradeID, end, limit)
+	if err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	s.writeMessage(w, r, list)
+}
+
+// tradePage reads at most limit trades after (afterTime, afterTradeID) and before end,
+// with the cursor of the next page if there is one
+func (s *Server) tradePage(ctx context.Context, symbol string, afterTime, afterTradeID, end int64, limit int) (*binanceProto.LiveDataList, error) {
+	trades, err := s.tradeRepo.GetTradesPage(ctx, symbol, afterTime, afterTradeID, end, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	list := &binanceProto.LiveDataList{}
+	if len(trades) > limit {
+		trades = trades[:limit]
+		last := trades[limit-1]
+		list.NextCursor = fmt.Sprintf("%d:%d", last.Timestamp, last.TradeID)
+	}
+	for i := range trades {
+		list.Items = append(list.Items, publisher.TradeLiveData(&trades[i]))
+	}
+
+	return list, nil
+}
+
+// parseTradeCursor parses a <timestamp>:<trade ID> trade cursor
+func parseTradeCursor(cursor string) (int64, int64, error) {
+	timestamp, tradeID, ok := strings.Cut(cursor, ":")
+	if ok {
+		t, errT := strconv.ParseInt(timestamp, 10, 64)
+		id, errID := strconv.ParseInt(tradeID, 10, 64)
+		if errT == nil && errID == nil {
+			return t, id, nil
+		}
+	}
+	return 0, 0, fmt.Errorf("invalid cursor %q", cursor)
+}
+
+// handleLatestDepth serves GET /v1/depth/latest?symbol, the last depth update cached in
+// Redis. Depth is published but not stored, so there is no depth history.
+func (s *Server) handleLatestDepth(w http.ResponseWriter, r *http.Request) {
+	symbol, err := requireSymbol(r.URL.Query())
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	var liveData binanceProto.LiveData
+	if err := s.redis.GetProtobuf(r.Context(), publisher.LatestKey(publisher.DepthChannel(symbol)), &liveData); err != nil {
+		if errors.Is(err, redis.ErrKeyNotFound) {
+			s.writeError(w, r, http.StatusNotFound, fmt.Errorf("no recent depth for %s", symbol))
+			return
+		}
+		s.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	s.writeMessage(w, r, &liveData)
+}
+
+// handleSymbols serves GET /v1/symbols?all, the active symbols or, with all=true, every symbol
+func (s *Server) handleSymbols(w http.ResponseWriter, r *http.Request) {
+	all, err := parseBool(r.URL.Query(), "all")
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	var symbols []models.Symbol
+	if all {
+		symbols, err = s.symbolRepo.GetAllSymbols(r.Context())
+	} else {
+		symbols, err = s.symbolRepo.GetActiveSymbols(r.Context())
+	}
+	if err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	list := &binanceProto.SymbolInfoList{}
+	for _, symbol := range symbols {
+		list.Symbols = append(list.Symbols, &binanceProto.SymbolInfo{
+			Symbol:     symbol.Symbol,
+			BaseAsset:  symbol.BaseAsset,
+			QuoteAsset: symbol.QuoteAsset,
+			Status:     symbol.Status,
+			IsActive:   symbol.IsActive,
+		})
+	}
+
+	s.writeMessage(w, r, list)
+}
+
+// handleSyncStatus serves GET /v1/sync-status?market&symbol&type&interval&all, the sync
+// progress of the matching streams of active symbols, or of every symbol with all=true
+func (s *Server) handleSyncStatus(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	all, err := parseBool(query, "all")
+	if err != nil {
+		s.writeError(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	statuses, err := s.syncStatusRepo.List(r.Context(), repository.SyncStatusFilter{
+		Market:     query.Get("market"),
+		Symbol:     strings.ToUpper(query.Get("symbol")),
+		DataType:   query.Get("type"),
+		Interval:   query.Get("interval"),
+		ActiveOnly: !all,
+	})
+	if err != nil {
+		s.writeError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	list := &binanceProto.SyncStatusList{}
+	for _, status := range statuses {
+		list.Statuses = append(list.Statuses, &binanceProto.SyncStatus{
+			Market:       status.Market,
+			Symbol:       status.Symbol,
+			DataType:     status.DataType,
+			Interval:     status.Interval,
+			LastSyncTime: status.LastSyncTime,
+			LastDataTime: status.LastDataTime,
+			Status:       status.Status,
+			ErrorMessage: status.ErrorMessage,
+			UpdatedAt:    status.UpdatedAt,
+		})
+	}
+
+	s.writeMessage(w, r, list)
+}
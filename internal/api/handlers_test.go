@@ -0,0 +1,319 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/binance-live/internal/config"
+	"github.com/binance-live/internal/models"
+	"github.com/binance-live/internal/publisher"
+	"github.com/binance-live/internal/redis"
+	binanceProto "github.com/binance-live/proto"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// fakeKlineStore serves klines held in open time order like repository.KlineRepository
+type fakeKlineStore struct {
+	klines []models.Kline
+}
+
+func (f *fakeKlineStore) GetKlinesPage(ctx context.Context, symbol, interval string, startTime, endTime int64, limit int) ([]models.Kline, error) {
+	var page []models.Kline
+	for _, kline := range f.klines {
+		if kline.Symbol == symbol && kline.Interval == interval &&
+			kline.OpenTime >= startTime && kline.OpenTime < endTime && len(page) < limit {
+			page = append(page, kline)
+		}
+	}
+	return page, nil
+}
+
+func (f *fakeKlineStore) GetLatestKlines(ctx context.Context, symbol, interval string, limit int) ([]models.Kline, error) {
+	page, _ := f.GetKlinesPage(ctx, symbol, interval, 0, 1<<62, len(f.klines))
+	return page[max(len(page)-limit, 0):], nil
+}
+
+// fakeTradeStore serves trades held in (timestamp, trade ID) order like repository.TradeRepository
+type fakeTradeStore struct {
+	trades []models.Trade
+}
+
+func (f *fakeTradeStore) GetTradesPage(ctx context.Context, symbol string, afterTime, afterTradeID, endTime int64, limit int) ([]models.Trade, error) {
+	var page []models.Trade
+	for _, trade := range f.trades {
+		after := trade.Timestamp > afterTime || (trade.Timestamp == afterTime && trade.TradeID > afterTradeID)
+		if trade.Symbol == symbol && after && trade.Timestamp < endTime && len(page) < limit {
+			page = append(page, trade)
+		}
+	}
+	return page, nil
+}
+
+// newTestServer creates an API server over the fake stores, with pages of 3 to 100 rows
+func newTestServer(klines *fakeKlineStore, trades *fakeTradeStore, redisClient *redis.Client) *Server {
+	return &Server{
+		config:    &config.APIConfig{DefaultLimit: 3, MaxLimit: 100},
+		klineRepo: klines,
+		tradeRepo: trades,
+		redis:     redisClient,
+		logger:    zap.NewNop(),
+	}
+}
+
+// get serves a GET request and returns the response
+func get(t *testing.T, handler http.Handler, target string, header http.Header) *httptest.ResponseRecorder {
+	t.Helper()
+
+	r := httptest.NewRequest(http.MethodGet, target, nil)
+	for name, values := range header {
+		r.Header[name] = values
+	}
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	return w
+}
+
+// decodeList decodes a JSON or protobuf live data list response
+func decodeList(t *testing.T, w *httptest.ResponseRecorder) *binanceProto.LiveDataList {
+	t.Helper()
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("got status %d: %s", w.Code, w.Body)
+	}
+
+	list := &binanceProto.LiveDataList{}
+	var err error
+	switch contentType := w.Header().Get("Content-Type"); contentType {
+	case contentTypeJSON:
+		err = protojson.Unmarshal(w.Body.Bytes(), list)
+	case contentTypeProtobuf:
+		err = proto.Unmarshal(w.Body.Bytes(), list)
+	default:
+		t.Fatalf("unexpected content type %q", contentType)
+	}
+	if err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	return list
+}
+
+func TestHandlersBadRequest(t *testing.T) {
+	handler := newTestServer(&fakeKlineStore{}, &fakeTradeStore{}, nil).Handler()
+
+	tests := []struct {
+		name   string
+		target string
+		want   string
+	}{
+		{name: "klines without symbol", target: "/v1/klines?interval=1m", want: "symbol is required"},
+		{name: "klines without interval", target: "/v1/klines?symbol=BTCUSDT", want: "interval is required"},
+		{name: "klines with bad interval", target: "/v1/klines?symbol=BTCUSDT&interval=7m", want: "7m"},
+		{name: "klines with bad limit", target: "/v1/klines?symbol=BTCUSDT&interval=1m&limit=abc", want: "limit must be between 1 and 100"},
+		{name: "klines with zero limit", target: "/v1/klines?symbol=BTCUSDT&interval=1m&limit=0", want: "limit must be between 1 and 100"},
+		{name: "klines over max limit", target: "/v1/klines?symbol=BTCUSDT&interval=1m&limit=101", want: "limit must be between 1 and 100"},
+		{name: "klines with end before start", target: "/v1/klines?symbol=BTCUSDT&interval=1m&start=2000&end=1000", want: "end must be after start"},
+		{name: "klines with end at start", target: "/v1/klines?symbol=BTCUSDT&interval=1m&start=2000&end=2000", want: "end must be after start"},
+		{name: "klines with bad start", target: "/v1/klines?symbol=BTCUSDT&interval=1m&start=yesterday", want: "invalid start"},
+		{name: "klines with bad cursor", target: "/v1/klines?symbol=BTCUSDT&interval=1m&cursor=abc", want: "invalid cursor"},
+		{name: "latest klines without interval", target: "/v1/klines/latest?symbol=BTCUSDT", want: "interval is required"},
+		{name: "trades without symbol", target: "/v1/trades", want: "symbol is required"},
+		{name: "trades with bad limit", target: "/v1/trades?symbol=BTCUSDT&limit=-1", want: "limit must be between 1 and 100"},
+		{name: "trades with end before start", target: "/v1/trades?symbol=BTCUSDT&start=2024-01-02T00:00:00Z&end=2024-01-01T00:00:00Z", want: "end must be after start"},
+		{name: "trades with cursor without trade ID", target: "/v1/trades?symbol=BTCUSDT&cursor=1000", want: "invalid cursor"},
+		{name: "trades with bad cursor", target: "/v1/trades?symbol=BTCUSDT&cursor=1000:x", want: "invalid cursor"},
+		{name: "depth without symbol", target: "/v1/depth/latest", want: "symbol is required"},
+		{name: "symbols with bad all", target: "/v1/symbols?all=maybe", want: "invalid all"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := get(t, handler, tt.target, nil)
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("got status %d, want 400", w.Code)
+			}
+			if contentType := w.Header().Get("Content-Type"); contentType != contentTypeJSON {
+				t.Errorf("got content type %q, want %q", contentType, contentTypeJSON)
+			}
+
+			var body map[string]string
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("failed to decode error body: %v", err)
+			}
+			if !strings.Contains(body["error"], tt.want) {
+				t.Errorf("got error %q, want it to mention %q", body["error"], tt.want)
+			}
+		})
+	}
+}
+
+func TestHandleKlinesCursorRoundTrip(t *testing.T) {
+	store := &fakeKlineStore{}
+	for i := range 8 {
+		store.klines = append(store.klines, models.Kline{
+			Symbol:    "BTCUSDT",
+			Interval:  "1m",
+			OpenTime:  int64(i) * 60000,
+			CloseTime: int64(i)*60000 + 59999,
+		})
+	}
+	handler := newTestServer(store, &fakeTradeStore{}, nil).Handler()
+
+	for _, header := range []http.Header{nil, {"Accept": {contentTypeProtobuf}}} {
+		var openTimes []int64
+		pages := 0
+		query := url.Values{"symbol": {"btcusdt"}, "interval": {"1m"}, "end": {"420000"}}
+		for {
+			list := decodeList(t, get(t, handler, "/v1/klines?"+query.Encode(), header))
+			pages++
+			for _, item := range list.Items {
+				openTimes = append(openTimes, item.GetKline().OpenTime)
+			}
+			if list.NextCursor == "" {
+				break
+			}
+			if pages > 10 {
+				t.Fatal("paging did not end")
+			}
+			query.Set("cursor", list.NextCursor)
+		}
+
+		// Seven klines open before end, in pages of the default limit of 3
+		want := []int64{0, 60000, 120000, 180000, 240000, 300000, 360000}
+		if !slices.Equal(openTimes, want) {
+			t.Errorf("got open times %v, want %v", openTimes, want)
+		}
+		if pages != 3 {
+			t.Errorf("got %d pages, want 3", pages)
+		}
+	}
+}
+
+func TestHandleTradesCursorRoundTrip(t *testing.T) {
+	// Trades sharing a timestamp straddle the page boundaries
+	store := &fakeTradeStore{}
+	timestamps := []int64{1000, 1000, 1000, 1000, 2000, 2000, 3000, 3000, 3000}
+	for i, timestamp := range timestamps {
+		store.trades = append(store.trades, models.Trade{
+			Symbol:    "BTCUSDT",
+			TradeID:   int64(100 + i),
+			Timestamp: timestamp,
+		})
+	}
+	handler := newTestServer(&fakeKlineStore{}, store, nil).Handler()
+
+	for _, limit := range []int{1, 2, 3, 4, 100} {
+		t.Run(fmt.Sprintf("limit %d", limit), func(t *testing.T) {
+			var tradeIDs []int64
+			query := url.Values{"symbol": {"BTCUSDT"}, "start": {"1000"}, "limit": {strconv.Itoa(limit)}}
+			for pages := 1; ; pages++ {
+				list := decodeList(t, get(t, handler, "/v1/trades?"+query.Encode(), nil))
+				if len(list.Items) > limit {
+					t.Fatalf("got %d trades, want at most %d", len(list.Items), limit)
+				}
+				for _, item := range list.Items {
+					tradeIDs = append(tradeIDs, item.GetTrade().TradeId)
+				}
+				if list.NextCursor == "" {
+					break
+				}
+				if pages > len(timestamps) {
+					t.Fatal("paging did not end")
+				}
+				query.Set("cursor", list.NextCursor)
+			}
+
+			want := []int64{100, 101, 102, 103, 104, 105, 106, 107, 108}
+			if !slices.Equal(tradeIDs, want) {
+				t.Errorf("got trade IDs %v, want each trade once in order %v", tradeIDs, want)
+			}
+		})
+	}
+}
+
+func TestHandleTradesCursorFormat(t *testing.T) {
+	store := &fakeTradeStore{trades: []models.Trade{
+		{Symbol: "BTCUSDT", TradeID: 7, Timestamp: 1000},
+		{Symbol: "BTCUSDT", TradeID: 8, Timestamp: 1000},
+	}}
+	handler := newTestServer(&fakeKlineStore{}, store, nil).Handler()
+
+	list := decodeList(t, get(t, handler, "/v1/trades?symbol=BTCUSDT&limit=1", nil))
+	if list.NextCursor != "1000:7" {
+		t.Errorf("got next cursor %q, want 1000:7", list.NextCursor)
+	}
+
+	list = decodeList(t, get(t, handler, "/v1/trades?symbol=BTCUSDT&limit=1&cursor=1000:7", nil))
+	if len(list.Items) != 1 || list.Items[0].GetTrade().TradeId != 8 {
+		t.Errorf("got %v after the cursor, want trade 8", list.Items)
+	}
+	if list.NextCursor != "" {
+		t.Errorf("got next cursor %q on the last page, want none", list.NextCursor)
+	}
+}
+
+// testRedis starts an in-memory Redis server and connects a client to it
+func testRedis(t *testing.T) (*miniredis.Miniredis, *redis.Client) {
+	t.Helper()
+
+	m := miniredis.RunT(t)
+	port, err := strconv.Atoi(m.Port())
+	if err != nil {
+		t.Fatalf("invalid miniredis port: %v", err)
+	}
+	redisClient, err := redis.New(&config.RedisConfig{Host: m.Host(), Port: port, PoolSize: 2, LiveDataTTL: 60}, zap.NewNop())
+	if err != nil {
+		t.Fatalf("failed to connect to miniredis: %v", err)
+	}
+	t.Cleanup(func() { redisClient.Close() })
+
+	return m, redisClient
+}
+
+func TestHandleLatestDepth(t *testing.T) {
+	m, redisClient := testRedis(t)
+
+	handler := newTestServer(&fakeKlineStore{}, &fakeTradeStore{}, redisClient).Handler()
+
+	w := get(t, handler, "/v1/depth/latest?symbol=BTCUSDT", nil)
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("got status %d without cached depth, want 404", w.Code)
+	}
+	var body map[string]string
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil || !strings.Contains(body["error"], "BTCUSDT") {
+		t.Errorf("got error body %s, want it to name the symbol", w.Body)
+	}
+
+	depth := &binanceProto.LiveData{
+		Type:   binanceProto.DataType_DATA_TYPE_DEPTH,
+		Symbol: "BTCUSDT",
+		Data:   &binanceProto.LiveData_Depth{Depth: &binanceProto.DepthData{LastUpdateId: 42}},
+	}
+	data, err := proto.Marshal(depth)
+	if err != nil {
+		t.Fatalf("failed to marshal depth: %v", err)
+	}
+	m.Set(publisher.LatestKey(publisher.DepthChannel("BTCUSDT")), string(data))
+
+	w = get(t, handler, "/v1/depth/latest?symbol=btcusdt&format=protobuf", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("got status %d with cached depth, want 200: %s", w.Code, w.Body)
+	}
+	var liveData binanceProto.LiveData
+	if err := proto.Unmarshal(w.Body.Bytes(), &liveData); err != nil {
+		t.Fatalf("failed to decode depth: %v", err)
+	}
+	if liveData.GetDepth().GetLastUpdateId() != 42 {
+		t.Errorf("got last update ID %d, want 42", liveData.GetDepth().GetLastUpdateId())
+	}
+}
@@ -0,0 +1,118 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/binance-live/internal/config"
+	"github.com/binance-live/internal/models"
+	"github.com/binance-live/internal/redis"
+	"github.com/binance-live/internal/repository"
+	"go.uber.org/zap"
+)
+
+// shutdownTimeout bounds how long Run waits for in-flight requests on shutdown
+const shutdownTimeout = 10 * time.Second
+
+// klineStore is the kline reads of the API, implemented by repository.KlineRepository
+type klineStore interface {
+	GetKlinesPage(ctx context.Context, symbol, interval string, startTime, endTime int64, limit int) ([]models.Kline, error)
+	GetLatestKlines(ctx context.Context, symbol, interval string, limit int) ([]models.Kline, error)
+}
+
+// tradeStore is the trade reads of the API, implemented by repository.TradeRepository
+type tradeStore interface {
+	GetTradesPage(ctx context.Context, symbol string, afterTime, afterTradeID, endTime int64, limit int) ([]models.Trade, error)
+}
+
+// Server serves historical and latest market data over HTTP from the repositories,
+// and the latest depth from the Redis cache since depth is not stored
+type Server struct {
+	config         *config.APIConfig
+	klineRepo      klineStore
+	tickerRepo     *repository.TickerRepository
+	tradeRepo      tradeStore
+	symbolRepo     *repository.SymbolRepository
+	syncStatusRepo *repository.SyncStatusRepository
+	redis          *redis.Client
+	gateway        *Gateway // nil: no WebSocket gateway
+	logger         *zap.Logger
+}
+
+// NewServer creates a new HTTP API server
+func NewServer(
+	cfg *config.APIConfig,
+	klineRepo *repository.KlineRepository,
+	tickerRepo *repository.TickerRepository,
+	tradeRepo *repository.TradeRepository,
+	symbolRepo *repository.SymbolRepository,
+	syncStatusRepo *repository.SyncStatusRepository,
+	redisClient *redis.Client,
+	logger *zap.Logger,
+) *Server {
+	return &Server{
+		config:         cfg,
+		klineRepo:      klineRepo,
+		tickerRepo:     tickerRepo,
+		tradeRepo:      tradeRepo,
+		symbolRepo:     symbolRepo,
+		syncStatusRepo: syncStatusRepo,
+		redis:          redisClient,
+		logger:         logger,
+	}
+}
+
+// UseGateway serves gateway at /v1/ws. Call before Handler or Run.
+func (s *Server) UseGateway(gateway *Gateway) {
+	s.gateway = gateway
+}
+
+// Handler returns the routes of the API
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/klines", s.handleKlines)
+	mux.HandleFunc("GET /v1/klines/latest", s.handleLatestKlines)
+	mux.HandleFunc("GET /v1/tickers/latest", s.handleLatestTickers)
+	mux.HandleFunc("GET /v1/trades", s.handleTrades)
+	mux.HandleFunc("GET /v1/depth/latest", s.handleLatestDepth)
+	mux.HandleFunc("GET /v1/symbols", s.handleSymbols)
+	mux.HandleFunc("GET /v1/sync-status", s.handleSyncStatus)
+	if s.gateway != nil {
+		mux.HandleFunc("GET /v1/ws", s.gateway.handleWebSocket)
+	}
+	return mux
+}
+
+// Run serves the API until ctx is cancelled, then waits for in-flight requests
+func (s *Server) Run(ctx context.Context) error {
+	server := &http.Server{
+		Addr:              s.config.GetAddr(),
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- server.ListenAndServe()
+	}()
+
+	s.logger.Info("HTTP API listening", zap.String("addr", server.Addr))
+
+	select {
+	case err := <-errChan:
+		return fmt.Errorf("HTTP API stopped: %w", err)
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), shutdownTimeout)
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("failed to shut down HTTP API: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,506 @@
+package binance
+
+import (
+	"archive/zip"
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// DefaultArchiveURL is the base URL of the Binance public data archive
+const DefaultArchiveURL = "https://data.binance.vision"
+
+// ErrArchiveNotFound is returned when an archive file has not been published
+var ErrArchiveNotFound = errors.New("archive file not found")
+
+// ArchiveKind identifies the data set stored in a public data archive file
+type ArchiveKind string
+
+// Supported archive data sets
+const (
+	ArchiveKindKlines    ArchiveKind = "klines"
+	ArchiveKindAggTrades ArchiveKind = "aggTrades"
+)
+
+// ArchiveFile describes a public data archive file such as BTCUSDT-1m-2023-01.zip
+// or BTCUSDT-aggTrades-2023-01-15.zip
+type ArchiveFile struct {
+	Name     string      // Base file name
+	Symbol   string      // Trading pair
+	Kind     ArchiveKind // klines or aggTrades
+	Interval Interval    // Only set for klines
+	Period   string      // YYYY-MM for monthly files, YYYY-MM-DD for daily files
+}
+
+// IsDaily reports whether the file holds a single day of data
+func (f *ArchiveFile) IsDaily() bool {
+
+	return len(f.Period) == len("2006-01-02")
+}
+
+// ParseArchiveFileName parses the name of a public data archive zip file
+func ParseArchiveFileName(name string) (*ArchiveFile, error) {
+
+	base := filepath.Base(name)
+	stem := strings.TrimSuffix(base, ".zip")
+	if stem == base {
+
+		return nil, fmt.Errorf("not a zip archive: %s", base)
+	}
+
+	// SYMBOL-KIND-YYYY-MM[-DD]
+	parts := strings.Split(stem, "-")
+	if len(parts) != 4 && len(parts) != 5 {
+
+		return nil, fmt.Errorf("unrecognized archive file name: %s", base)
+	}
+
+	file := &ArchiveFile{
+		Name:   base,
+		Symbol: parts[0],
+		Period: strings.Join(parts[2:], "-"),
+	}
+
+	if parts[1] == string(ArchiveKindAggTrades) {
+
+		file.Kind = ArchiveKindAggTrades
+	} else {
+
+		interval, err := ParseInterval(strings.Replace(parts[1], archiveMonthInterval, string(Interval1M), 1))
+		if err != nil {
+
+			return nil, fmt.Errorf("unrecognized archive file name: %s: %w", base, err)
+		}
+		file.Kind = ArchiveKindKlines
+		file.Interval = interval
+	}
+
+	return file, nil
+}
+
+// archiveMonthInterval is how the archive names the 1M interval in paths and file names,
+// e.g. BTCUSDT-1mo-2023-01.zip
+const archiveMonthInterval = "1mo"
+
+// archiveInterval returns the archive name of interval
+func archiveInterval(interval Interval) string {
+
+	if interval == Interval1M {
+
+		return archiveMonthInterval
+	}
+
+	return string(interval)
+}
+
+// ArchiveFileURL builds the download URL of a spot archive file.
+// period is YYYY-MM for monthly files or YYYY-MM-DD for daily files.
+func ArchiveFileURL(baseURL, symbol string, kind ArchiveKind, interval Interval, period string) string {
+
+	frequency := "monthly"
+	if len(period) == len("2006-01-02") {
+
+		frequency = "daily"
+	}
+
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	if kind == ArchiveKindAggTrades {
+
+		return fmt.Sprintf("%s/data/spot/%s/aggTrades/%s/%s-aggTrades-%s.zip",
+			baseURL, frequency, symbol, symbol, period)
+	}
+
+	name := archiveInterval(interval)
+	return fmt.Sprintf("%s/data/spot/%s/klines/%s/%s/%s-%s-%s.zip",
+		baseURL, frequency, symbol, name, symbol, name, period)
+}
+
+// DownloadArchive downloads an archive file and its .CHECKSUM into dir, verifying the
+// checksum. It returns the local path of the zip and the checksum file contents. The zip
+// is written under a .part name and only renamed once complete and verified, so a failed
+// download leaves nothing behind for a rerun to import.
+func DownloadArchive(ctx context.Context, client *http.Client, fileURL, dir string) (string, []byte, error) {
+
+	checksum, err := fetchArchiveChecksum(ctx, client, fileURL+".CHECKSUM")
+	if err != nil {
+
+		return "", nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fileURL, nil)
+	if err != nil {
+
+		return "", nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+
+		return "", nil, fmt.Errorf("download failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+
+		return "", nil, fmt.Errorf("%w: %s", ErrArchiveNotFound, fileURL)
+	}
+	if resp.StatusCode != http.StatusOK {
+
+		return "", nil, fmt.Errorf("download failed: status %d for %s", resp.StatusCode, fileURL)
+	}
+
+	path := filepath.Join(dir, filepath.Base(fileURL))
+	partPath := path + ".part"
+	if err := writeArchive(partPath, resp.Body, checksum); err != nil {
+
+		os.Remove(partPath)
+		return "", nil, err
+	}
+
+	if err := os.Rename(partPath, path); err != nil {
+
+		os.Remove(partPath)
+		return "", nil, fmt.Errorf("failed to move %s into place: %w", path, err)
+	}
+
+	return path, checksum, nil
+}
+
+// writeArchive writes a downloaded archive to path and verifies it against checksum
+func writeArchive(path string, body io.Reader, checksum []byte) error {
+
+	f, err := os.Create(path)
+	if err != nil {
+
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+
+	if _, err := io.Copy(f, body); err != nil {
+
+		f.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	if err := f.Close(); err != nil {
+
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	return VerifyArchiveChecksum(path, checksum)
+}
+
+// fetchArchiveChecksum downloads a .CHECKSUM file
+func fetchArchiveChecksum(ctx context.Context, client *http.Client, checksumURL string) ([]byte, error) {
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, checksumURL, nil)
+	if err != nil {
+
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+
+		return nil, fmt.Errorf("checksum download failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+
+		return nil, fmt.Errorf("%w: %s", ErrArchiveNotFound, checksumURL)
+	}
+	if resp.StatusCode != http.StatusOK {
+
+		return nil, fmt.Errorf("checksum download failed: status %d for %s", resp.StatusCode, checksumURL)
+	}
+
+	return io.ReadAll(resp.Body)
+}
+
+// VerifyArchiveChecksum compares the SHA-256 of the file at path with the
+// contents of its .CHECKSUM file ("<hex digest>  <file name>")
+func VerifyArchiveChecksum(path string, checksum []byte) error {
+
+	fields := strings.Fields(string(checksum))
+	if len(fields) == 0 {
+
+		return fmt.Errorf("empty checksum for %s", filepath.Base(path))
+	}
+	expected := strings.ToLower(fields[0])
+
+	f, err := os.Open(path)
+	if err != nil {
+
+		return fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer f.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+
+		return fmt.Errorf("failed to hash archive: %w", err)
+	}
+
+	actual := hex.EncodeToString(hash.Sum(nil))
+	if actual != expected {
+
+		return fmt.Errorf("checksum mismatch for %s: expected %s, got %s", filepath.Base(path), expected, actual)
+	}
+
+	return nil
+}
+
+// ReadArchiveKlines streams the klines in a kline archive to fn in batches of up to batchSize
+func ReadArchiveKlines(path string, batchSize int, fn func([]KlineData) error) error {
+
+	batch := make([]KlineData, 0, batchSize)
+
+	err := readArchiveCSV(path, func(record []string) error {
+
+		kline, err := parseArchiveKline(record)
+		if err != nil {
+
+			return err
+		}
+
+		batch = append(batch, *kline)
+		if len(batch) >= batchSize {
+
+			if err := fn(batch); err != nil {
+
+				return err
+			}
+			batch = batch[:0]
+		}
+
+		return nil
+	})
+	if err != nil {
+
+		return err
+	}
+
+	if len(batch) > 0 {
+
+		return fn(batch)
+	}
+
+	return nil
+}
+
+// ReadArchiveAggTrades streams the trades in an aggTrades archive to fn in batches of up to batchSize
+func ReadArchiveAggTrades(path string, batchSize int, fn func([]AggTradeResponse) error) error {
+
+	batch := make([]AggTradeResponse, 0, batchSize)
+
+	err := readArchiveCSV(path, func(record []string) error {
+
+		trade, err := parseArchiveAggTrade(record)
+		if err != nil {
+
+			return err
+		}
+
+		batch = append(batch, *trade)
+		if len(batch) >= batchSize {
+
+			if err := fn(batch); err != nil {
+
+				return err
+			}
+			batch = batch[:0]
+		}
+
+		return nil
+	})
+	if err != nil {
+
+		return err
+	}
+
+	if len(batch) > 0 {
+
+		return fn(batch)
+	}
+
+	return nil
+}
+
+// readArchiveCSV calls fn for every data row of the CSV files inside a zip archive.
+// Header rows, present in some archives, are skipped.
+func readArchiveCSV(path string, fn func(record []string) error) error {
+
+	archive, err := zip.OpenReader(path)
+	if err != nil {
+
+		return fmt.Errorf("failed to open archive: %w", err)
+	}
+	defer archive.Close()
+
+	for _, entry := range archive.File {
+
+		if !strings.HasSuffix(entry.Name, ".csv") {
+
+			continue
+		}
+
+		rc, err := entry.Open()
+		if err != nil {
+
+			return fmt.Errorf("failed to open %s: %w", entry.Name, err)
+		}
+
+		reader := csv.NewReader(bufio.NewReader(rc))
+		reader.FieldsPerRecord = -1
+		reader.ReuseRecord = true
+
+		line := 0
+		for {
+
+			record, err := reader.Read()
+			if errors.Is(err, io.EOF) {
+
+				break
+			}
+			if err != nil {
+
+				rc.Close()
+				return fmt.Errorf("failed to read %s: %w", entry.Name, err)
+			}
+			line++
+
+			// Skip header rows
+			if line == 1 && len(record) > 0 {
+
+				if _, err := strconv.ParseInt(record[0], 10, 64); err != nil {
+
+					continue
+				}
+			}
+
+			if err := fn(record); err != nil {
+
+				rc.Close()
+				return fmt.Errorf("%s line %d: %w", entry.Name, line, err)
+			}
+		}
+
+		rc.Close()
+	}
+
+	return nil
+}
+
+// parseArchiveKline parses a kline CSV row:
+// open_time, open, high, low, close, volume, close_time, quote_volume, count,
+// taker_buy_volume, taker_buy_quote_volume, ignore
+func parseArchiveKline(record []string) (*KlineData, error) {
+
+	if len(record) < 11 {
+
+		return nil, fmt.Errorf("expected at least 11 kline columns, got %d", len(record))
+	}
+
+	openTime, err := parseArchiveTime(record[0])
+	if err != nil {
+
+		return nil, err
+	}
+
+	closeTime, err := parseArchiveTime(record[6])
+	if err != nil {
+
+		return nil, err
+	}
+
+	trades, err := strconv.Atoi(record[8])
+	if err != nil {
+
+		return nil, fmt.Errorf("invalid trade count %q: %w", record[8], err)
+	}
+
+	return &KlineData{
+		OpenTime:                 openTime,
+		Open:                     record[1],
+		High:                     record[2],
+		Low:                      record[3],
+		Close:                    record[4],
+		Volume:                   record[5],
+		CloseTime:                closeTime,
+		QuoteAssetVolume:         record[7],
+		NumberOfTrades:           trades,
+		TakerBuyBaseAssetVolume:  record[9],
+		TakerBuyQuoteAssetVolume: record[10],
+	}, nil
+}
+
+// parseArchiveAggTrade parses an aggTrades CSV row:
+// agg_trade_id, price, quantity, first_trade_id, last_trade_id, transact_time,
+// is_buyer_maker, is_best_match
+func parseArchiveAggTrade(record []string) (*AggTradeResponse, error) {
+
+	if len(record) < 7 {
+
+		return nil, fmt.Errorf("expected at least 7 aggTrade columns, got %d", len(record))
+	}
+
+	aggTradeID, err := strconv.ParseInt(record[0], 10, 64)
+	if err != nil {
+
+		return nil, fmt.Errorf("invalid aggregate trade id %q: %w", record[0], err)
+	}
+
+	firstTradeID, _ := strconv.ParseInt(record[3], 10, 64)
+	lastTradeID, _ := strconv.ParseInt(record[4], 10, 64)
+
+	timestamp, err := parseArchiveTime(record[5])
+	if err != nil {
+
+		return nil, err
+	}
+
+	trade := &AggTradeResponse{
+		AggTradeID:   aggTradeID,
+		Price:        record[1],
+		Quantity:     record[2],
+		FirstTradeID: firstTradeID,
+		LastTradeID:  lastTradeID,
+		Timestamp:    timestamp,
+		IsBuyerMaker: strings.EqualFold(record[6], "true"),
+	}
+
+	if len(record) > 7 {
+
+		trade.IsBestMatch = strings.EqualFold(record[7], "true")
+	}
+
+	return trade, nil
+}
+
+// parseArchiveTime parses an archive timestamp into milliseconds.
+// Spot archives switched to microseconds from 2025, so larger values are scaled down.
+func parseArchiveTime(value string) (int64, error) {
+
+	ts, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+
+		return 0, fmt.Errorf("invalid timestamp %q: %w", value, err)
+	}
+
+	if ts > 1e14 {
+
+		ts /= 1000
+	}
+
+	return ts, nil
+}
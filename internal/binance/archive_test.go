@@ -0,0 +1,155 @@
+package binance
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestArchiveFileURL(t *testing.T) {
+	tests := []struct {
+		kind     ArchiveKind
+		interval Interval
+		period   string
+		want     string
+	}{
+		{ArchiveKindKlines, Interval1m, "2023-01", "/data/spot/monthly/klines/BTCUSDT/1m/BTCUSDT-1m-2023-01.zip"},
+		{ArchiveKindKlines, Interval1h, "2023-01-15", "/data/spot/daily/klines/BTCUSDT/1h/BTCUSDT-1h-2023-01-15.zip"},
+		{ArchiveKindKlines, Interval1M, "2023-01", "/data/spot/monthly/klines/BTCUSDT/1mo/BTCUSDT-1mo-2023-01.zip"},
+		{ArchiveKindAggTrades, "", "2023-01-15", "/data/spot/daily/aggTrades/BTCUSDT/BTCUSDT-aggTrades-2023-01-15.zip"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := ArchiveFileURL(DefaultArchiveURL+"/", "BTCUSDT", tt.kind, tt.interval, tt.period); got != DefaultArchiveURL+tt.want {
+				t.Errorf("got %s, want %s", got, DefaultArchiveURL+tt.want)
+			}
+		})
+	}
+}
+
+func TestParseArchiveFileName(t *testing.T) {
+	tests := []struct {
+		name     string
+		kind     ArchiveKind
+		interval Interval
+		period   string
+	}{
+		{"BTCUSDT-1m-2023-01.zip", ArchiveKindKlines, Interval1m, "2023-01"},
+		{"BTCUSDT-1mo-2023-01.zip", ArchiveKindKlines, Interval1M, "2023-01"},
+		{"/tmp/BTCUSDT-aggTrades-2023-01-15.zip", ArchiveKindAggTrades, "", "2023-01-15"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file, err := ParseArchiveFileName(tt.name)
+			if err != nil {
+				t.Fatalf("failed to parse file name: %v", err)
+			}
+			if file.Symbol != "BTCUSDT" || file.Kind != tt.kind || file.Interval != tt.interval || file.Period != tt.period {
+				t.Errorf("got %+v, want %s %s %s", file, tt.kind, tt.interval, tt.period)
+			}
+		})
+	}
+
+	for _, name := range []string{"BTCUSDT-1m-2023-01.csv", "BTCUSDT-7m-2023-01.zip", "BTCUSDT-2023.zip"} {
+		if _, err := ParseArchiveFileName(name); err == nil {
+			t.Errorf("parsed %s, want an error", name)
+		}
+	}
+}
+
+// archiveServer serves data as BTCUSDT-1mo-2023-01.zip with the given checksum
+// digest; truncate cuts the body short of its announced length
+func archiveServer(t *testing.T, data []byte, digest string, truncate bool) string {
+	t.Helper()
+
+	const name = "BTCUSDT-1mo-2023-01.zip"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, name+".CHECKSUM"):
+			w.Write([]byte(digest + "  " + name + "\n"))
+		case strings.HasSuffix(r.URL.Path, name):
+			if truncate {
+				w.Header().Set("Content-Length", "1000")
+			}
+			w.Write(data)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return ArchiveFileURL(server.URL, "BTCUSDT", ArchiveKindKlines, Interval1M, "2023-01")
+}
+
+// sha256Hex returns the hex SHA-256 digest of data
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// dirEntries returns the names of the files in dir
+func dirEntries(t *testing.T, dir string) []string {
+	t.Helper()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("failed to read directory: %v", err)
+	}
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	return names
+}
+
+func TestDownloadArchive(t *testing.T) {
+	data := []byte("archive contents")
+
+	tests := []struct {
+		name     string
+		digest   string
+		truncate bool
+		wantErr  bool
+	}{
+		{"complete", sha256Hex(data), false, false},
+		{"checksum mismatch", sha256Hex([]byte("other contents")), false, true},
+		{"truncated", sha256Hex(data), true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fileURL := archiveServer(t, data, tt.digest, tt.truncate)
+			dir := t.TempDir()
+
+			path, _, err := DownloadArchive(context.Background(), http.DefaultClient, fileURL, dir)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("downloaded the archive, want an error")
+				}
+				// Nothing is left for a rerun to import
+				if names := dirEntries(t, dir); len(names) != 0 {
+					t.Errorf("got files %v after a failed download, want none", names)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("failed to download archive: %v", err)
+			}
+			if path != filepath.Join(dir, "BTCUSDT-1mo-2023-01.zip") {
+				t.Errorf("got path %s", path)
+			}
+			if names := dirEntries(t, dir); len(names) != 1 {
+				t.Errorf("got files %v, want only the archive", names)
+			}
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package binance
+
+import (
+	"fmt"
+	"time"
+)
+
+// Interval represents a Binance kline interval such as "1s", "1h" or "1M"
+type Interval string
+
+// Supported Binance kline intervals
+const (
+	Interval1s  Interval = "1s"
+	Interval1m  Interval = "1m"
+	Interval3m  Interval = "3m"
+	Interval5m  Interval = "5m"
+	Interval15m Interval = "15m"
+	Interval30m Interval = "30m"
+	Interval1h  Interval = "1h"
+	Interval2h  Interval = "2h"
+	Interval4h  Interval = "4h"
+	Interval6h  Interval = "6h"
+	Interval8h  Interval = "8h"
+	Interval12h Interval = "12h"
+	Interval1d  Interval = "1d"
+	Interval3d  Interval = "3d"
+	Interval1w  Interval = "1w"
+	Interval1M  Interval = "1M"
+)
+
+// intervalDurations holds the fixed length of every interval except 1M,
+// which follows calendar months
+var intervalDurations = map[Interval]time.Duration{
+	Interval1s:  time.Second,
+	Interval1m:  time.Minute,
+	Interval3m:  3 * time.Minute,
+	Interval5m:  5 * time.Minute,
+	Interval15m: 15 * time.Minute,
+	Interval30m: 30 * time.Minute,
+	Interval1h:  time.Hour,
+	Interval2h:  2 * time.Hour,
+	Interval4h:  4 * time.Hour,
+	Interval6h:  6 * time.Hour,
+	Interval8h:  8 * time.Hour,
+	Interval12h: 12 * time.Hour,
+	Interval1d:  24 * time.Hour,
+	Interval3d:  3 * 24 * time.Hour,
+	Interval1w:  7 * 24 * time.Hour,
+}
+
+// weekOffset aligns weekly candles to Monday 00:00 UTC (the Unix epoch is a Thursday)
+const weekOffset = 4 * 24 * time.Hour
+
+// ParseInterval parses and validates a Binance kline interval
+func ParseInterval(s string) (Interval, error) {
+
+	interval := Interval(s)
+	if !interval.IsValid() {
+
+		return "", fmt.Errorf("unsupported kline interval: %q", s)
+	}
+
+	return interval, nil
+}
+
+// ParseIntervals parses and validates a list of Binance kline intervals
+func ParseIntervals(values []string) ([]Interval, error) {
+
+	intervals := make([]Interval, 0, len(values))
+	for _, value := range values {
+
+		interval, err := ParseInterval(value)
+		if err != nil {
+
+			return nil, err
+		}
+
+		intervals = append(intervals, interval)
+	}
+
+	return intervals, nil
+}
+
+// String returns the Binance representation of the interval
+func (i Interval) String() string {
+
+	return string(i)
+}
+
+// IsValid reports whether the interval is supported by Binance
+func (i Interval) IsValid() bool {
+
+	if i == Interval1M {
+
+		return true
+	}
+
+	_, ok := intervalDurations[i]
+	return ok
+}
+
+// Duration returns the nominal length of one candle.
+// For 1M this is 30 days; use Add, Next or Truncate for exact month boundaries.
+func (i Interval) Duration() time.Duration {
+
+	if i == Interval1M {
+
+		return 30 * 24 * time.Hour
+	}
+
+	return intervalDurations[i]
+}
+
+// Truncate returns the open time of the candle containing t. Candles are counted from
+// the Unix epoch like on Binance, not from year 1 like time.Time.Truncate.
+func (i Interval) Truncate(t time.Time) time.Time {
+
+	t = t.UTC()
+
+	if i == Interval1M {
+
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	width, offset := intervalDurations[i].Milliseconds(), i.Offset().Milliseconds()
+	return time.UnixMilli(floorDiv(t.UnixMilli()-offset, width)*width + offset).UTC()
+}
+
+// Offset returns how far the candle open times are shifted from multiples of the
+// candle length since the Unix epoch: 4 days for 1w, which opens on Mondays, 0 otherwise
+func (i Interval) Offset() time.Duration {
+
+	if i == Interval1w {
+
+		return weekOffset
+	}
+
+	return 0
+}
+
+// Add returns t moved forward by n candles (backwards when n is negative)
+func (i Interval) Add(t time.Time, n int) time.Time {
+
+	if i == Interval1M {
+
+		return t.UTC().AddDate(0, n, 0)
+	}
+
+	return t.Add(time.Duration(n) * intervalDurations[i])
+}
+
+// Next returns the open time of the candle following the one containing t
+func (i Interval) Next(t time.Time) time.Time {
+
+	return i.Add(i.Truncate(t), 1)
+}
+
+// CloseTime returns the Binance close time of the candle opening at openTime
+func (i Interval) CloseTime(openTime time.Time) time.Time {
+
+	return i.Add(openTime, 1).Add(-time.Millisecond)
+}
+
+// Count returns the number of candle open times within [start, end)
+func (i Interval) Count(start, end time.Time) int64 {
+
+	first := i.Truncate(start)
+	if first.Before(start) {
+
+		first = i.Add(first, 1)
+	}
+
+	if !first.Before(end) {
+
+		return 0
+	}
+
+	if i == Interval1M {
+
+		var count int64
+		for t := first; t.Before(end); t = i.Add(t, 1) {
+
+			count++
+		}
+		return count
+	}
+
+	d := intervalDurations[i]
+	return int64((end.Sub(first)-1)/d) + 1
+}
+
+// floorDiv divides a by b rounding towards negative infinity, so times before the
+// epoch fall into the candle before it
+func floorDiv(a, b int64) int64 {
+
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+
+		q--
+	}
+
+	return q
+}
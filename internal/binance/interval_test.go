@@ -0,0 +1,114 @@
+package binance
+
+import (
+	"testing"
+	"time"
+)
+
+// utc parses an RFC 3339 time
+func utc(t *testing.T, value string) time.Time {
+	t.Helper()
+
+	parsed, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		t.Fatalf("failed to parse time %q: %v", value, err)
+	}
+	return parsed
+}
+
+func TestIntervalTruncate(t *testing.T) {
+	tests := []struct {
+		interval Interval
+		t        string
+		want     string
+	}{
+		{Interval1s, "2024-01-10T12:00:00.999Z", "2024-01-10T12:00:00Z"},
+		{Interval1s, "1969-12-31T23:59:59.5Z", "1969-12-31T23:59:59Z"},
+		{Interval1m, "2024-01-10T12:34:56Z", "2024-01-10T12:34:00Z"},
+		{Interval4h, "2024-01-10T03:59:59.999Z", "2024-01-10T00:00:00Z"},
+		{Interval1d, "1969-12-31T12:00:00Z", "1969-12-31T00:00:00Z"},
+		// 3d candles are counted from the epoch
+		{Interval3d, "2024-01-10T12:00:00Z", "2024-01-09T00:00:00Z"},
+		{Interval3d, "2024-01-09T00:00:00Z", "2024-01-09T00:00:00Z"},
+		{Interval3d, "2024-01-11T23:59:59.999Z", "2024-01-09T00:00:00Z"},
+		{Interval3d, "1970-01-01T00:00:00Z", "1970-01-01T00:00:00Z"},
+		{Interval3d, "1969-12-31T12:00:00Z", "1969-12-29T00:00:00Z"},
+		// 1w candles open on Mondays
+		{Interval1w, "2024-01-10T12:00:00Z", "2024-01-08T00:00:00Z"},
+		{Interval1w, "2024-01-08T00:00:00Z", "2024-01-08T00:00:00Z"},
+		{Interval1w, "2024-01-14T23:59:59.999Z", "2024-01-08T00:00:00Z"},
+		{Interval1w, "2024-01-07T23:59:59.999Z", "2024-01-01T00:00:00Z"},
+		{Interval1w, "1970-01-01T00:00:00Z", "1969-12-29T00:00:00Z"},
+		{Interval1w, "1969-12-28T12:00:00Z", "1969-12-22T00:00:00Z"},
+		{Interval1M, "2024-02-29T23:59:59.999Z", "2024-02-01T00:00:00Z"},
+		{Interval1M, "1969-12-15T00:00:00Z", "1969-12-01T00:00:00Z"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.interval)+" "+tt.t, func(t *testing.T) {
+			got := tt.interval.Truncate(utc(t, tt.t))
+			if want := utc(t, tt.want); !got.Equal(want) {
+				t.Errorf("got %s, want %s", got.Format(time.RFC3339Nano), tt.want)
+			}
+			if got.Location() != time.UTC {
+				t.Errorf("got location %s, want UTC", got.Location())
+			}
+		})
+	}
+}
+
+func TestIntervalNextAndCloseTime(t *testing.T) {
+	tests := []struct {
+		interval  Interval
+		t         string
+		next      string
+		closeTime string
+	}{
+		{Interval1s, "1969-12-31T23:59:59.5Z", "1970-01-01T00:00:00Z", "1969-12-31T23:59:59.999Z"},
+		{Interval3d, "2024-01-10T12:00:00Z", "2024-01-12T00:00:00Z", "2024-01-11T23:59:59.999Z"},
+		{Interval1w, "2024-01-10T12:00:00Z", "2024-01-15T00:00:00Z", "2024-01-14T23:59:59.999Z"},
+		{Interval1w, "1969-12-31T00:00:00Z", "1970-01-05T00:00:00Z", "1970-01-04T23:59:59.999Z"},
+		{Interval1M, "2024-02-10T00:00:00Z", "2024-03-01T00:00:00Z", "2024-02-29T23:59:59.999Z"},
+		{Interval1M, "1969-12-15T00:00:00Z", "1970-01-01T00:00:00Z", "1969-12-31T23:59:59.999Z"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.interval)+" "+tt.t, func(t *testing.T) {
+			at := utc(t, tt.t)
+			if got := tt.interval.Next(at); !got.Equal(utc(t, tt.next)) {
+				t.Errorf("got next %s, want %s", got.Format(time.RFC3339Nano), tt.next)
+			}
+			if got := tt.interval.CloseTime(tt.interval.Truncate(at)); !got.Equal(utc(t, tt.closeTime)) {
+				t.Errorf("got close time %s, want %s", got.Format(time.RFC3339Nano), tt.closeTime)
+			}
+		})
+	}
+}
+
+func TestIntervalCount(t *testing.T) {
+	tests := []struct {
+		interval Interval
+		start    string
+		end      string
+		want     int64
+	}{
+		{Interval1s, "1969-12-31T23:59:58.5Z", "1970-01-01T00:00:01Z", 2},
+		{Interval1d, "2024-01-01T00:00:00Z", "2024-01-01T00:00:00Z", 0},
+		// Open times 2024-01-09, 2024-01-12 and 2024-01-15
+		{Interval3d, "2024-01-08T00:00:00Z", "2024-01-16T00:00:00Z", 3},
+		// Mondays 2024-01-08 and 2024-01-15
+		{Interval1w, "2024-01-03T00:00:00Z", "2024-01-22T00:00:00Z", 2},
+		{Interval1w, "2024-01-08T00:00:00Z", "2024-01-22T00:00:00Z", 2},
+		{Interval1w, "1969-12-29T00:00:00Z", "1970-01-06T00:00:00Z", 2},
+		{Interval1M, "2023-12-15T00:00:00Z", "2024-03-01T00:00:00Z", 2},
+		{Interval1M, "1969-11-01T00:00:00Z", "1970-02-01T00:00:00Z", 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.interval)+" "+tt.start, func(t *testing.T) {
+			if got := tt.interval.Count(utc(t, tt.start), utc(t, tt.end)); got != tt.want {
+				t.Errorf("got %d candles in [%s, %s), want %d", got, tt.start, tt.end, tt.want)
+			}
+		})
+	}
+}
@@ -15,6 +15,9 @@ import (
 	"golang.org/x/time/rate"
 )
 
+// MaxKlinesLimit is the maximum number of klines Binance returns for a single request
+const MaxKlinesLimit = 1000
+
 // RESTClient handles HTTP requests to Binance REST API
 type RESTClient struct {
 	baseURL    string
@@ -110,11 +113,16 @@ func (c *RESTClient) GetExchangeInfo(ctx context.Context) (*ExchangeInfoResponse
 }
 
 // GetKlines retrieves kline/candlestick data
-func (c *RESTClient) GetKlines(ctx context.Context, symbol, interval string, startTime, endTime *time.Time, limit int) ([]KlineResponse, error) {
+func (c *RESTClient) GetKlines(ctx context.Context, symbol string, interval Interval, startTime, endTime *time.Time, limit int) ([]KlineResponse, error) {
+
+	if !interval.IsValid() {
+
+		return nil, fmt.Errorf("unsupported kline interval: %q", interval)
+	}
 
 	params := url.Values{}
 	params.Set("symbol", symbol)
-	params.Set("interval", interval)
+	params.Set("interval", interval.String())
 
 	if startTime != nil {
 
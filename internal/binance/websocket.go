@@ -269,7 +269,7 @@ func (c *WSClient) closeConnection() error {
 }
 
 // BuildStreamNames builds WebSocket stream names for symbols
-func BuildStreamNames(symbols []string, intervals []string) []string {
+func BuildStreamNames(symbols []string, intervals []Interval) []string {
 
 	var streams []string
 
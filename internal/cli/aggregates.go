@@ -0,0 +1,220 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/binance-live/internal/binance"
+	"github.com/binance-live/internal/database"
+	"github.com/binance-live/internal/repository"
+	"github.com/binance-live/internal/service"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+func NewAggregatesCmd() *cobra.Command {
+	aggregatesCmd := &cobra.Command{
+		Use:   "aggregates",
+		Short: "Continuous aggregates",
+		Long: `Commands for the continuous aggregates that derive binance.derived_intervals from binance.derived_base_interval
+and summarize the stored tickers per minute (ticker_1m)`,
+	}
+
+	aggregatesCmd.AddCommand(NewCreateAggregatesCmd())
+	aggregatesCmd.AddCommand(NewRefreshAggregatesCmd())
+	aggregatesCmd.AddCommand(NewCheckAggregatesCmd())
+
+	return aggregatesCmd
+}
+
+func NewCreateAggregatesCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "create",
+		Short: "Create continuous aggregates for derived intervals and tickers",
+		Long:  `Create the continuous aggregates and refresh policies for every configured derived interval and for ticker_1m`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runCreateAggregates()
+		},
+	}
+}
+
+func NewRefreshAggregatesCmd() *cobra.Command {
+	var from, to string
+
+	cmd := &cobra.Command{
+		Use:   "refresh",
+		Short: "Materialize continuous aggregates over a date range",
+		Long:  `Materialize derived intervals and ticker_1m over a date range, e.g. after backfilling base interval history`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if from == "" {
+				return fmt.Errorf("from is required")
+			}
+			return runRefreshAggregates(from, to)
+		},
+	}
+
+	cmd.Flags().StringVar(&from, "from", "", "Range start, YYYY-MM-DD or RFC3339 (required)")
+	cmd.Flags().StringVar(&to, "to", "", "Range end (exclusive), YYYY-MM-DD or RFC3339 (default: now)")
+	cmd.MarkFlagRequired("from")
+
+	return cmd
+}
+
+func NewCheckAggregatesCmd() *cobra.Command {
+	var (
+		symbol   string
+		interval string
+		hours    int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "check",
+		Short: "Compare derived candles with native Binance candles",
+		Long:  `Compare candles derived by a continuous aggregate with the candles returned by the Binance REST API`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if symbol == "" || interval == "" {
+				return fmt.Errorf("symbol and interval are required")
+			}
+			return runCheckAggregates(symbol, interval, hours)
+		},
+	}
+
+	cmd.Flags().StringVarP(&symbol, "symbol", "s", "", "Symbol to check (required)")
+	cmd.Flags().StringVarP(&interval, "interval", "i", "", "Derived interval to check (required)")
+	cmd.Flags().IntVar(&hours, "hours", 24, "Hours of history to check")
+	cmd.MarkFlagRequired("symbol")
+	cmd.MarkFlagRequired("interval")
+
+	return cmd
+}
+
+func runCreateAggregates() error {
+	cfg, log, ctx, err := getSharedResources()
+	if err != nil {
+		return err
+	}
+	defer log.Sync()
+
+	// Initialize database
+	db, err := database.New(&cfg.Database, log)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer db.Close()
+
+	syncService := newDataSyncService(cfg, log, db)
+
+	count, err := syncService.EnsureDerivedIntervals(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create continuous aggregates: %w", err)
+	}
+
+	if err := repository.NewTickerRepository(db).EnsureTicker1mView(ctx); err != nil {
+		return fmt.Errorf("failed to create continuous aggregates: %w", err)
+	}
+	fmt.Printf("%s ready\n", repository.Ticker1mView)
+	if mode := service.TickerStoreMode(cfg.Tickers.StoreMode); mode != "" && mode != service.TickerStoreAll {
+		fmt.Printf("tickers.store_mode is %s: %s high, low and spread statistics are approximate\n",
+			mode, repository.Ticker1mView)
+	}
+
+	if count == 0 {
+		fmt.Println("No derived intervals configured (binance.derived_intervals)")
+		return nil
+	}
+
+	fmt.Printf("%d derived intervals ready, built from %s\n", count, cfg.Binance.DerivedBaseInterval)
+	return nil
+}
+
+func runRefreshAggregates(fromValue, toValue string) error {
+	cfg, log, ctx, err := getSharedResources()
+	if err != nil {
+		return err
+	}
+	defer log.Sync()
+
+	from, err := parseDateFlag(fromValue)
+	if err != nil {
+		return fmt.Errorf("invalid --from: %w", err)
+	}
+
+	to := time.Now().UTC()
+	if toValue != "" {
+		to, err = parseDateFlag(toValue)
+		if err != nil {
+			return fmt.Errorf("invalid --to: %w", err)
+		}
+	}
+
+	log.Info("Refreshing derived intervals", zap.Time("from", from), zap.Time("to", to))
+
+	// Initialize database
+	db, err := database.New(&cfg.Database, log)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer db.Close()
+
+	syncService := newDataSyncService(cfg, log, db)
+
+	if err := syncService.RefreshDerivedIntervals(ctx, from, to); err != nil {
+		return fmt.Errorf("failed to refresh continuous aggregates: %w", err)
+	}
+
+	if err := repository.NewTickerRepository(db).RefreshTicker1mView(ctx, from.UnixMilli(), to.UnixMilli()); err != nil {
+		return fmt.Errorf("failed to refresh continuous aggregates: %w", err)
+	}
+
+	return nil
+}
+
+func runCheckAggregates(symbol, intervalName string, hours int) error {
+	cfg, log, ctx, err := getSharedResources()
+	if err != nil {
+		return err
+	}
+	defer log.Sync()
+
+	symbol = strings.ToUpper(symbol)
+
+	interval, err := binance.ParseInterval(intervalName)
+	if err != nil {
+		return err
+	}
+
+	// Initialize database
+	db, err := database.New(&cfg.Database, log)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer db.Close()
+
+	syncService := newDataSyncService(cfg, log, db)
+
+	end := time.Now()
+	start := end.Add(-time.Duration(hours) * time.Hour)
+
+	report, err := syncService.CheckDerivedKlines(ctx, symbol, interval, start, end)
+	if err != nil {
+		return fmt.Errorf("consistency check failed: %w", err)
+	}
+
+	fmt.Printf("%s %s (from %s): %d compared, %d matched, %d missing derived, %d missing native\n",
+		report.Symbol, report.Interval, report.BaseInterval,
+		report.Compared, report.Matched, report.MissingDerived, report.MissingNative)
+
+	if len(report.Mismatches) == 0 {
+		return nil
+	}
+
+	fmt.Printf("\n%-17s %-24s %-20s %-20s\n", "OPEN TIME", "FIELD", "DERIVED", "NATIVE")
+	fmt.Println(strings.Repeat("-", 84))
+	for _, m := range report.Mismatches {
+		fmt.Printf("%-17s %-24s %-20.8f %-20.8f\n",
+			time.UnixMilli(m.OpenTime).UTC().Format("2006-01-02 15:04"), m.Field, m.Derived, m.Native)
+	}
+
+	return fmt.Errorf("%d derived candles differ from Binance", report.Compared-report.Matched)
+}
@@ -0,0 +1,42 @@
+package cli
+
+import (
+	"testing"
+	"time"
+
+	"github.com/binance-live/internal/binance"
+)
+
+func TestDefaultRangeEnd(t *testing.T) {
+	tests := []struct {
+		interval binance.Interval
+		want     string // On Wednesday 2024-01-10
+	}{
+		{binance.Interval1m, "2024-01-10T00:00:00Z"},
+		{binance.Interval1d, "2024-01-10T00:00:00Z"},
+		{binance.Interval3d, "2024-01-09T00:00:00Z"},
+		{binance.Interval1w, "2024-01-08T00:00:00Z"}, // Monday
+		{binance.Interval1M, "2024-01-01T00:00:00Z"},
+	}
+
+	morning := time.Date(2024, 1, 10, 0, 0, 1, 0, time.UTC)
+	evening := time.Date(2024, 1, 10, 23, 59, 59, 0, time.UTC)
+	for _, tt := range tests {
+		t.Run(string(tt.interval), func(t *testing.T) {
+			want, err := time.Parse(time.RFC3339, tt.want)
+			if err != nil {
+				t.Fatalf("failed to parse time: %v", err)
+			}
+
+			// Reruns during the day end the range at the same candle boundary
+			for _, now := range []time.Time{morning, evening} {
+				if got := defaultRangeEnd(tt.interval, now); !got.Equal(want) {
+					t.Errorf("got %s at %s, want %s", got.Format(time.RFC3339), now.Format(time.RFC3339), tt.want)
+				}
+			}
+			if got := defaultRangeEnd(tt.interval, want); !tt.interval.Truncate(got).Equal(got) {
+				t.Errorf("got %s, not a %s candle open time", got.Format(time.RFC3339), tt.interval)
+			}
+		})
+	}
+}
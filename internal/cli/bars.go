@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/binance-live/internal/database"
+	"github.com/spf13/cobra"
+)
+
+func NewBarsCmd() *cobra.Command {
+	barsCmd := &cobra.Command{
+		Use:   "bars",
+		Short: "Bars built from the aggTrade stream",
+		Long:  `Commands for the bars the server builds locally from aggregated trades`,
+	}
+
+	barsCmd.AddCommand(NewCheckBarsCmd())
+
+	return barsCmd
+}
+
+func NewCheckBarsCmd() *cobra.Command {
+	var (
+		symbol  string
+		minutes int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "check",
+		Short: "Rebuild 1m bars from aggTrades and compare them with Binance 1m klines",
+		Long: `Replay recent aggregated trades from the Binance REST API through the bar builder
+and compare the resulting 1m bars with the 1m klines Binance returns for the same minutes`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if symbol == "" {
+				return fmt.Errorf("symbol is required")
+			}
+			return runCheckBars(symbol, minutes)
+		},
+	}
+
+	cmd.Flags().StringVarP(&symbol, "symbol", "s", "", "Symbol to check (required)")
+	cmd.Flags().IntVar(&minutes, "minutes", 60, "Minutes of trades to replay")
+	cmd.MarkFlagRequired("symbol")
+
+	return cmd
+}
+
+func runCheckBars(symbol string, minutes int) error {
+	cfg, log, ctx, err := getSharedResources()
+	if err != nil {
+		return err
+	}
+	defer log.Sync()
+
+	symbol = strings.ToUpper(symbol)
+
+	// Initialize database
+	db, err := database.New(&cfg.Database, log)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer db.Close()
+
+	syncService := newDataSyncService(cfg, log, db)
+
+	end := time.Now()
+	start := end.Add(-time.Duration(minutes) * time.Minute)
+
+	report, err := syncService.CheckTradeBars(ctx, symbol, start, end)
+	if err != nil {
+		return fmt.Errorf("bar check failed: %w", err)
+	}
+
+	fmt.Printf("%s 1m from %d aggTrades: %d compared, %d matched, %d missing bars, %d missing klines\n",
+		report.Symbol, report.Trades,
+		report.Compared, report.Matched, report.MissingBars, report.MissingKlines)
+
+	if len(report.Mismatches) == 0 {
+		return nil
+	}
+
+	fmt.Printf("\n%-17s %-24s %-20s %-20s\n", "OPEN TIME", "FIELD", "REBUILT", "BINANCE")
+	fmt.Println(strings.Repeat("-", 84))
+	for _, m := range report.Mismatches {
+		fmt.Printf("%-17s %-24s %-20.8f %-20.8f\n",
+			time.UnixMilli(m.OpenTime).UTC().Format("2006-01-02 15:04"), m.Field, m.Derived, m.Native)
+	}
+
+	return fmt.Errorf("%d rebuilt bars differ from Binance", report.Compared-report.Matched)
+}
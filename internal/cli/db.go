@@ -0,0 +1,20 @@
+package cli
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewDBCmd() *cobra.Command {
+	dbCmd := &cobra.Command{
+		Use:   "db",
+		Short: "Database management commands",
+		Long:  `Commands for managing TimescaleDB objects used by the collector`,
+	}
+
+	dbCmd.AddCommand(NewMigrateCmd())
+	dbCmd.AddCommand(NewAggregatesCmd())
+	dbCmd.AddCommand(NewMaintainCmd())
+	dbCmd.AddCommand(NewBenchmarkCmd())
+
+	return dbCmd
+}
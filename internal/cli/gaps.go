@@ -0,0 +1,222 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/binance-live/internal/binance"
+	"github.com/binance-live/internal/database"
+	"github.com/binance-live/internal/models"
+	"github.com/binance-live/internal/repository"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+func NewSyncGapsCmd() *cobra.Command {
+	gapsCmd := &cobra.Command{
+		Use:   "gaps",
+		Short: "Kline gap detection and repair",
+		Long:  `Commands for finding missing klines inside the stored history and refetching them`,
+	}
+
+	gapsCmd.AddCommand(NewScanGapsCmd())
+	gapsCmd.AddCommand(NewRepairGapsCmd())
+	gapsCmd.AddCommand(NewListGapsCmd())
+
+	return gapsCmd
+}
+
+func NewScanGapsCmd() *cobra.Command {
+	var (
+		symbol    string
+		intervals []string
+		maxHours  int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "scan",
+		Short: "Scan stored klines for gaps",
+		Long:  `Scan stored klines for missing candles and record them in kline_gaps. Scans all active symbols and configured intervals unless a symbol is given`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runScanGaps(symbol, intervals, maxHours)
+		},
+	}
+
+	cmd.Flags().StringVarP(&symbol, "symbol", "s", "", "Symbol to scan (default: all active symbols)")
+	cmd.Flags().StringSliceVarP(&intervals, "intervals", "i", []string{"1m", "15m", "1h", "4h", "1d"}, "Kline intervals to scan when a symbol is given")
+	cmd.Flags().IntVarP(&maxHours, "max-hours", "m", 0, "Hours of history to scan (default: sync.max_sync_hours)")
+
+	return cmd
+}
+
+func NewRepairGapsCmd() *cobra.Command {
+	var limit int
+
+	cmd := &cobra.Command{
+		Use:   "repair",
+		Short: "Refetch recorded gaps",
+		Long:  `Refetch open kline gaps from the Binance REST API`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRepairGaps(limit)
+		},
+	}
+
+	cmd.Flags().IntVarP(&limit, "limit", "l", 100, "Maximum number of gaps to repair")
+
+	return cmd
+}
+
+func NewListGapsCmd() *cobra.Command {
+	var symbol string
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List recorded gaps for a symbol",
+		Long:  `List all recorded kline gaps for a symbol`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if symbol == "" {
+				return fmt.Errorf("symbol is required")
+			}
+			return runListGaps(symbol)
+		},
+	}
+
+	cmd.Flags().StringVarP(&symbol, "symbol", "s", "", "Symbol to list (required)")
+	cmd.MarkFlagRequired("symbol")
+
+	return cmd
+}
+
+func runScanGaps(symbol string, intervals []string, maxHours int) error {
+	cfg, log, ctx, err := getSharedResources()
+	if err != nil {
+		return err
+	}
+	defer log.Sync()
+
+	if maxHours > 0 {
+		cfg.Sync.MaxSyncHours = maxHours
+	}
+
+	// Initialize database
+	db, err := database.New(&cfg.Database, log)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer db.Close()
+
+	syncService := newDataSyncService(cfg, log, db)
+
+	if symbol == "" {
+		found, err := syncService.ScanAllKlineGaps(ctx)
+		if err != nil {
+			return fmt.Errorf("gap scan failed: %w", err)
+		}
+
+		fmt.Printf("Found %d gaps\n", found)
+		return nil
+	}
+
+	symbol = strings.ToUpper(symbol)
+	parsedIntervals, err := binance.ParseIntervals(intervals)
+	if err != nil {
+		return err
+	}
+
+	end := time.Now()
+	start := end.Add(-time.Duration(cfg.Sync.MaxSyncHours) * time.Hour)
+
+	total := 0
+	for _, interval := range parsedIntervals {
+		found, err := syncService.ScanKlineGaps(ctx, symbol, interval, start, end)
+		if err != nil {
+			return fmt.Errorf("gap scan failed for %s %s: %w", symbol, interval, err)
+		}
+		total += found
+	}
+
+	log.Info("Gap scan completed",
+		zap.String("symbol", symbol),
+		zap.Int("gaps_found", total),
+	)
+	fmt.Printf("Found %d gaps for %s\n", total, symbol)
+
+	return nil
+}
+
+func runRepairGaps(limit int) error {
+	cfg, log, ctx, err := getSharedResources()
+	if err != nil {
+		return err
+	}
+	defer log.Sync()
+
+	// Initialize database
+	db, err := database.New(&cfg.Database, log)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer db.Close()
+
+	syncService := newDataSyncService(cfg, log, db)
+
+	repaired, err := syncService.RepairKlineGaps(ctx, limit)
+	if err != nil {
+		return fmt.Errorf("gap repair failed: %w", err)
+	}
+
+	fmt.Printf("Repaired %d gaps\n", repaired)
+	return nil
+}
+
+func runListGaps(symbol string) error {
+	cfg, log, ctx, err := getSharedResources()
+	if err != nil {
+		return err
+	}
+	defer log.Sync()
+
+	// Initialize database
+	db, err := database.New(&cfg.Database, log)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer db.Close()
+
+	symbol = strings.ToUpper(symbol)
+	gaps, err := repository.NewKlineGapRepository(db).GetGapsBySymbol(ctx, symbol)
+	if err != nil {
+		return err
+	}
+
+	if len(gaps) == 0 {
+		fmt.Printf("No gaps recorded for %s\n", symbol)
+		return nil
+	}
+
+	printGapTable(gaps)
+	return nil
+}
+
+func printGapTable(gaps []models.KlineGap) {
+	fmt.Printf("%-10s %-17s %-17s %-8s %-9s %-8s %-20s\n",
+		"INTERVAL", "FROM", "TO", "MISSING", "STATUS", "ATTEMPTS", "ERROR")
+	fmt.Println(strings.Repeat("-", 95))
+
+	for _, gap := range gaps {
+		errorMsg := ""
+		if gap.ErrorMessage != nil {
+			errorMsg = *gap.ErrorMessage
+			if len(errorMsg) > 20 {
+				errorMsg = errorMsg[:17] + "..."
+			}
+		}
+
+		fmt.Printf("%-10s %-17s %-17s %-8d %-9s %-8d %-20s\n",
+			gap.Interval,
+			time.UnixMilli(gap.GapStart).UTC().Format("2006-01-02 15:04"),
+			time.UnixMilli(gap.GapEnd).UTC().Format("2006-01-02 15:04"),
+			gap.MissingCount, gap.Status, gap.Attempts, errorMsg)
+	}
+}
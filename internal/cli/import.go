@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/binance-live/internal/binance"
+	"github.com/binance-live/internal/database"
+	"github.com/binance-live/internal/service"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+func NewSyncImportCmd() *cobra.Command {
+	var (
+		source   string
+		symbol   string
+		kind     string
+		interval string
+		from     string
+		to       string
+		noTail   bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "import",
+		Short: "Bulk import klines or aggTrades from Binance public data archives",
+		Long: `Import zipped CSV archives published on data.binance.vision, from a local directory or a URL.
+Every file is verified against its .CHECKSUM before it is loaded. After a kline import the
+remaining tail up to now is fetched through the REST API unless --no-tail is set.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runSyncImport(source, symbol, kind, interval, from, to, noTail)
+		},
+	}
+
+	cmd.Flags().StringVar(&source, "source", binance.DefaultArchiveURL, "Local directory of archives or base URL to download from")
+	cmd.Flags().StringVarP(&symbol, "symbol", "s", "", "Symbol to import (required for URL sources)")
+	cmd.Flags().StringVarP(&kind, "kind", "k", string(binance.ArchiveKindKlines), "Archive kind: klines or aggTrades")
+	cmd.Flags().StringVarP(&interval, "interval", "i", "", "Kline interval (required for kline URL sources)")
+	cmd.Flags().StringVar(&from, "from", "", "First month to import, YYYY-MM or YYYY-MM-DD")
+	cmd.Flags().StringVar(&to, "to", "", "Import months before this one, YYYY-MM or YYYY-MM-DD (default: now)")
+	cmd.Flags().BoolVar(&noTail, "no-tail", false, "Do not fetch klines newer than the archives through REST")
+
+	return cmd
+}
+
+func runSyncImport(source, symbol, kindName, intervalName, fromValue, toValue string, noTail bool) error {
+	cfg, log, ctx, err := getSharedResources()
+	if err != nil {
+		return err
+	}
+	defer log.Sync()
+
+	opts := service.ArchiveImportOptions{
+		Source:   source,
+		Symbol:   strings.ToUpper(symbol),
+		Kind:     binance.ArchiveKind(kindName),
+		SyncTail: !noTail,
+	}
+
+	if intervalName != "" {
+		opts.Interval, err = binance.ParseInterval(intervalName)
+		if err != nil {
+			return err
+		}
+	}
+
+	if fromValue != "" {
+		opts.From, err = parseMonthFlag(fromValue)
+		if err != nil {
+			return fmt.Errorf("invalid --from: %w", err)
+		}
+	}
+
+	if toValue != "" {
+		opts.To, err = parseMonthFlag(toValue)
+		if err != nil {
+			return fmt.Errorf("invalid --to: %w", err)
+		}
+	}
+
+	log.Info("Starting archive import",
+		zap.String("source", opts.Source),
+		zap.String("symbol", opts.Symbol),
+		zap.String("kind", kindName),
+		zap.String("interval", intervalName),
+	)
+
+	// Initialize database
+	db, err := database.New(&cfg.Database, log)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer db.Close()
+
+	syncService := newDataSyncService(cfg, log, db)
+
+	result, err := syncService.ImportArchives(ctx, opts, func(file *binance.ArchiveFile, rows, inserted int64) {
+		fmt.Printf("%s: %d rows, %d new\n", file.Name, rows, inserted)
+	})
+	if err != nil {
+		return fmt.Errorf("archive import failed: %w", err)
+	}
+
+	fmt.Printf("Imported %d files (%d not published): %d rows, %d new\n",
+		result.Files, result.Skipped, result.Rows, result.Inserted)
+
+	return nil
+}
+
+// parseMonthFlag parses a month flag in YYYY-MM format, falling back to parseDateFlag
+func parseMonthFlag(value string) (time.Time, error) {
+	if t, err := time.Parse("2006-01", value); err == nil {
+		return t.UTC(), nil
+	}
+
+	return parseDateFlag(value)
+}
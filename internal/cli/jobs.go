@@ -0,0 +1,148 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/binance-live/internal/database"
+	"github.com/binance-live/internal/repository"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+func NewSyncJobsCmd() *cobra.Command {
+	jobsCmd := &cobra.Command{
+		Use:   "jobs",
+		Short: "Sync job queue",
+		Long:  `Commands for inspecting and working the persistent sync job queue`,
+	}
+
+	jobsCmd.AddCommand(NewListJobsCmd())
+	jobsCmd.AddCommand(NewWorkJobsCmd())
+
+	return jobsCmd
+}
+
+func NewListJobsCmd() *cobra.Command {
+	var (
+		state string
+		limit int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List sync jobs",
+		Long:  `List the most recently updated sync jobs, optionally filtered by state (pending, running, completed, failed)`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runListJobs(state, limit)
+		},
+	}
+
+	cmd.Flags().StringVar(&state, "state", "", "Only show jobs in this state")
+	cmd.Flags().IntVarP(&limit, "limit", "l", 50, "Maximum number of jobs to show")
+
+	return cmd
+}
+
+func NewWorkJobsCmd() *cobra.Command {
+	var workers int
+
+	cmd := &cobra.Command{
+		Use:   "work",
+		Short: "Process queued sync jobs",
+		Long:  `Claim and run queued sync jobs until the queue is empty. Several processes can work the same queue`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runWorkJobs(workers)
+		},
+	}
+
+	cmd.Flags().IntVarP(&workers, "workers", "w", 0, "Number of concurrent workers (default: sync.workers)")
+
+	return cmd
+}
+
+func runListJobs(state string, limit int) error {
+	cfg, log, ctx, err := getSharedResources()
+	if err != nil {
+		return err
+	}
+	defer log.Sync()
+
+	// Initialize database
+	db, err := database.New(&cfg.Database, log)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer db.Close()
+
+	jobs, err := repository.NewSyncJobRepository(db).List(ctx, state, limit)
+	if err != nil {
+		return err
+	}
+
+	if len(jobs) == 0 {
+		fmt.Println("No sync jobs found")
+		return nil
+	}
+
+	fmt.Printf("%-8s %-12s %-10s %-10s %-8s %-10s %-17s %-30s\n",
+		"ID", "SYMBOL", "INTERVAL", "STATE", "ATTEMPTS", "KLINES", "UPDATED", "ERROR")
+	fmt.Println(strings.Repeat("-", 110))
+
+	for _, job := range jobs {
+		errorMsg := ""
+		if job.LastError != nil {
+			errorMsg = *job.LastError
+			if len(errorMsg) > 30 {
+				errorMsg = errorMsg[:27] + "..."
+			}
+		}
+
+		fmt.Printf("%-8d %-12s %-10s %-10s %-8s %-10d %-17s %-30s\n",
+			job.ID, job.Symbol, job.Interval, job.State,
+			fmt.Sprintf("%d/%d", job.Attempts, job.MaxAttempts),
+			job.KlinesFetched,
+			time.UnixMilli(job.UpdatedAt).UTC().Format("2006-01-02 15:04"),
+			errorMsg)
+	}
+
+	return nil
+}
+
+func runWorkJobs(workers int) error {
+	cfg, log, ctx, err := getSharedResources()
+	if err != nil {
+		return err
+	}
+	defer log.Sync()
+
+	if workers <= 0 {
+		workers = cfg.Sync.Workers
+	}
+
+	log.Info("Starting sync job workers", zap.Int("workers", workers))
+
+	// Initialize database
+	db, err := database.New(&cfg.Database, log)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer db.Close()
+
+	syncService := newDataSyncService(cfg, log, db)
+
+	result, err := syncService.RunSyncJobs(ctx, workers)
+	if err != nil {
+		return fmt.Errorf("sync job workers stopped: %w", err)
+	}
+
+	fmt.Printf("Sync jobs: %d completed, %d retried, %d failed, %d taken over by other workers\n",
+		result.Completed, result.Retried, result.Failed, result.Lost)
+
+	if result.Failed > 0 {
+		return fmt.Errorf("%d sync jobs failed", result.Failed)
+	}
+
+	return nil
+}
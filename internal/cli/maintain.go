@@ -0,0 +1,128 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/binance-live/internal/database"
+	"github.com/binance-live/internal/repository"
+	"github.com/binance-live/internal/service"
+	"github.com/spf13/cobra"
+)
+
+func NewMaintainCmd() *cobra.Command {
+	var inspect bool
+
+	cmd := &cobra.Command{
+		Use:   "maintain",
+		Short: "Apply retention and compression policies to the hypertables",
+		Long: `Delete rows older than the configured retention (per table, and per interval for klines),
+enable TimescaleDB compression segmented by symbol (and interval for klines) and set the
+compression policies. With --inspect nothing is changed. Run it periodically, e.g. from cron.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runMaintain(inspect)
+		},
+	}
+
+	cmd.Flags().BoolVar(&inspect, "inspect", false, "Only show retention rules and compression state")
+
+	return cmd
+}
+
+func runMaintain(inspect bool) error {
+	cfg, log, ctx, err := getSharedResources()
+	if err != nil {
+		return err
+	}
+	defer log.Sync()
+
+	// Initialize database
+	db, err := database.New(&cfg.Database, log)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer db.Close()
+
+	maintenanceService := service.NewMaintenanceService(
+		repository.NewMaintenanceRepository(db),
+		&cfg.Retention,
+		&cfg.Binance,
+		log,
+	)
+
+	if !inspect {
+		results, err := maintenanceService.ApplyRetention(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to apply retention: %w", err)
+		}
+
+		for _, r := range results {
+			name := r.Rule.Table
+			if r.Rule.Interval != "" {
+				name += " " + r.Rule.Interval
+			}
+			fmt.Printf("%s: deleted %d rows before %s\n", name, r.Deleted, r.Cutoff.UTC().Format("2006-01-02 15:04"))
+		}
+
+		if err := maintenanceService.ApplyCompression(ctx); err != nil {
+			return fmt.Errorf("failed to apply compression: %w", err)
+		}
+	}
+
+	fmt.Printf("\n%-16s %-10s %-10s\n", "TABLE", "INTERVAL", "RETENTION")
+	fmt.Println(strings.Repeat("-", 38))
+	for _, rule := range maintenanceService.RetentionRules() {
+		retention := "forever"
+		if rule.Days > 0 {
+			retention = fmt.Sprintf("%dd", rule.Days)
+		}
+		fmt.Printf("%-16s %-10s %-10s\n", rule.Table, rule.Interval, retention)
+	}
+
+	stats, err := maintenanceService.Inspect(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to inspect hypertables: %w", err)
+	}
+
+	fmt.Printf("\n%-16s %-10s %-12s %-10s %-12s %-12s %-8s\n",
+		"TABLE", "SIZE", "COMPRESSION", "AFTER", "CHUNKS", "COMPRESSED", "RATIO")
+	fmt.Println(strings.Repeat("-", 86))
+	for _, s := range stats {
+		if !s.Exists {
+			fmt.Printf("%-16s %s\n", s.Name, "not created")
+			continue
+		}
+
+		compression, after, ratio := "off", "-", "-"
+		if s.CompressionEnabled {
+			compression = "on"
+		}
+		if s.CompressAfter != nil {
+			after = fmt.Sprintf("%dd", *s.CompressAfter/86400000)
+		}
+		if s.AfterBytes > 0 {
+			ratio = fmt.Sprintf("%.1fx", float64(s.BeforeBytes)/float64(s.AfterBytes))
+		}
+
+		fmt.Printf("%-16s %-10s %-12s %-10s %-12d %-12d %-8s\n",
+			s.Name, formatBytes(s.TotalBytes), compression, after, s.TotalChunks, s.CompressedChunks, ratio)
+	}
+
+	return nil
+}
+
+// formatBytes formats a size in bytes with a binary unit
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
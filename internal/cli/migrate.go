@@ -0,0 +1,166 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/binance-live/internal/database"
+	"github.com/binance-live/sql/migrations"
+	"github.com/spf13/cobra"
+)
+
+func NewMigrateCmd() *cobra.Command {
+	migrateCmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Apply or revert versioned schema migrations",
+		Long: `Apply the schema migrations embedded from sql/migrations. Applied versions are recorded
+in schema_migrations and an advisory lock keeps concurrent runs from applying a migration twice.`,
+	}
+
+	migrateCmd.AddCommand(NewMigrateUpCmd())
+	migrateCmd.AddCommand(NewMigrateDownCmd())
+	migrateCmd.AddCommand(NewMigrateStatusCmd())
+
+	return migrateCmd
+}
+
+func NewMigrateUpCmd() *cobra.Command {
+	var steps int
+
+	cmd := &cobra.Command{
+		Use:   "up",
+		Short: "Apply pending migrations",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runMigrateUp(steps)
+		},
+	}
+
+	cmd.Flags().IntVarP(&steps, "steps", "n", 0, "Maximum number of migrations to apply (0: all)")
+
+	return cmd
+}
+
+func NewMigrateDownCmd() *cobra.Command {
+	var steps int
+
+	cmd := &cobra.Command{
+		Use:   "down",
+		Short: "Revert the newest applied migrations",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runMigrateDown(steps)
+		},
+	}
+
+	cmd.Flags().IntVarP(&steps, "steps", "n", 1, "Number of migrations to revert")
+
+	return cmd
+}
+
+func NewMigrateStatusCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "Show applied and pending migrations",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runMigrateStatus()
+		},
+	}
+}
+
+func runMigrateUp(steps int) error {
+	cfg, log, ctx, err := getSharedResources()
+	if err != nil {
+		return err
+	}
+	defer log.Sync()
+
+	// Initialize database
+	db, err := database.New(&cfg.Database, log)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer db.Close()
+
+	migrator, err := database.NewMigrator(db, migrations.FS)
+	if err != nil {
+		return err
+	}
+
+	applied, err := migrator.Up(ctx, steps)
+	if err != nil {
+		return fmt.Errorf("failed to apply migrations: %w", err)
+	}
+
+	fmt.Printf("Applied %d migrations\n", applied)
+	return nil
+}
+
+func runMigrateDown(steps int) error {
+	cfg, log, ctx, err := getSharedResources()
+	if err != nil {
+		return err
+	}
+	defer log.Sync()
+
+	// Initialize database
+	db, err := database.New(&cfg.Database, log)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer db.Close()
+
+	migrator, err := database.NewMigrator(db, migrations.FS)
+	if err != nil {
+		return err
+	}
+
+	reverted, err := migrator.Down(ctx, steps)
+	if err != nil {
+		return fmt.Errorf("failed to revert migrations: %w", err)
+	}
+
+	fmt.Printf("Reverted %d migrations\n", reverted)
+	return nil
+}
+
+func runMigrateStatus() error {
+	cfg, log, ctx, err := getSharedResources()
+	if err != nil {
+		return err
+	}
+	defer log.Sync()
+
+	// Initialize database
+	db, err := database.New(&cfg.Database, log)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer db.Close()
+
+	migrator, err := database.NewMigrator(db, migrations.FS)
+	if err != nil {
+		return err
+	}
+
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get migration status: %w", err)
+	}
+
+	fmt.Printf("%-8s %-32s %-10s %-20s\n", "VERSION", "NAME", "STATE", "APPLIED AT")
+	fmt.Println(strings.Repeat("-", 72))
+	for _, s := range statuses {
+		state, appliedAt := "pending", "-"
+		if s.AppliedAt > 0 {
+			state = "applied"
+			appliedAt = time.UnixMilli(s.AppliedAt).UTC().Format("2006-01-02 15:04:05")
+		}
+		if s.Missing {
+			state = "missing"
+		}
+
+		fmt.Printf("%-8d %-32s %-10s %-20s\n", s.Version, s.Name, state, appliedAt)
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/binance-live/internal/redis"
+	"github.com/binance-live/internal/service"
+	"github.com/spf13/cobra"
+)
+
+func NewRedisCmd() *cobra.Command {
+	redisCmd := &cobra.Command{
+		Use:   "redis",
+		Short: "Redis management commands",
+		Long:  `Commands for inspecting the Redis instance live data is published to`,
+	}
+
+	redisCmd.AddCommand(NewRedisBenchmarkCmd())
+
+	return redisCmd
+}
+
+func NewRedisBenchmarkCmd() *cobra.Command {
+	var opts service.PublishBenchmarkOptions
+
+	cmd := &cobra.Command{
+		Use:   "benchmark",
+		Short: "Compare live data publishing strategies on the configured Redis",
+		Long: `Publish the same synthetic klines (PUBLISH plus SET of the latest kline) with separate
+commands, one pipeline per message and batched pipelines as configured by
+publisher.flush_interval_ms and publisher.batch_size, then report the throughput of each.
+It publishes on the BENCH*USDT channels and deletes their cached keys afterwards; run it
+against a local Redis, not production.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRedisBenchmark(opts)
+		},
+	}
+
+	cmd.Flags().IntVar(&opts.Symbols, "symbols", 10, "Symbols to spread the klines over")
+	cmd.Flags().IntVar(&opts.Messages, "messages", 20000, "Klines published per strategy")
+	cmd.Flags().IntVar(&opts.BatchSize, "batch", 500, "Messages per pipeline when batched")
+	cmd.Flags().IntVar(&opts.FlushInterval, "flush-interval-ms", 5, "Milliseconds between flushes when batched")
+
+	return cmd
+}
+
+func runRedisBenchmark(opts service.PublishBenchmarkOptions) error {
+	cfg, log, ctx, err := getSharedResources()
+	if err != nil {
+		return err
+	}
+	defer log.Sync()
+
+	// Initialize Redis
+	redisClient, err := redis.New(&cfg.Redis, log)
+	if err != nil {
+		return fmt.Errorf("failed to connect to Redis: %w", err)
+	}
+	defer redisClient.Close()
+
+	results, err := service.BenchmarkPublishing(ctx, redisClient, opts, log)
+	if err != nil {
+		return fmt.Errorf("benchmark failed: %w", err)
+	}
+
+	fmt.Printf("\n%-10s %-9s %-12s %-7s %-12s %-8s\n",
+		"STRATEGY", "MESSAGES", "ROUND TRIPS", "ERRORS", "MSG/S", "SPEEDUP")
+	fmt.Println(strings.Repeat("-", 63))
+	baseline := perSecond(results[0].Messages, results[0].Duration)
+	for _, r := range results {
+		rate := perSecond(r.Messages, r.Duration)
+		speedup := 0.0
+		if baseline > 0 {
+			speedup = rate / baseline
+		}
+
+		fmt.Printf("%-10s %-9d %-12d %-7d %-12.0f %-8s\n",
+			r.Strategy, r.Messages, r.RoundTrips, r.Errors, rate, fmt.Sprintf("%.1fx", speedup))
+	}
+
+	return nil
+}
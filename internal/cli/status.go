@@ -19,28 +19,100 @@ func NewStatusCmd() *cobra.Command {
 	}
 
 	statusCmd.AddCommand(NewSyncStatusCmd())
+	statusCmd.AddCommand(NewResetSyncStatusCmd())
+	statusCmd.AddCommand(NewDeleteSyncStatusCmd())
 	statusCmd.AddCommand(NewHealthCheckCmd())
 
 	return statusCmd
 }
 
 func NewSyncStatusCmd() *cobra.Command {
-	var symbol string
+	var (
+		filter repository.SyncStatusFilter
+		all    bool
+	)
 
 	cmd := &cobra.Command{
 		Use:   "sync",
 		Short: "Show synchronization status",
-		Long:  `Show synchronization status for all symbols or a specific symbol`,
+		Long:  `Show the synchronization status of every stream, or of the streams matching the filters`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runSyncStatus(symbol)
+			filter.ActiveOnly = !all
+			return runSyncStatus(filter)
 		},
 	}
 
-	cmd.Flags().StringVarP(&symbol, "symbol", "s", "", "Show status for specific symbol")
+	addSyncStatusFilterFlags(cmd, &filter)
+	cmd.Flags().BoolVar(&all, "all", false, "Include streams of inactive symbols")
 
 	return cmd
 }
 
+func NewResetSyncStatusCmd() *cobra.Command {
+	var (
+		filter repository.SyncStatusFilter
+		all    bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "reset",
+		Short: "Reset synchronization progress",
+		Long: `Clear the progress and errors of the matching streams, so the next sync starts again from
+sync.max_sync_hours ago. Stored data is kept.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := requireSyncStatusFilter(filter, all); err != nil {
+				return err
+			}
+			return runResetSyncStatus(filter)
+		},
+	}
+
+	addSyncStatusFilterFlags(cmd, &filter)
+	cmd.Flags().BoolVar(&all, "all", false, "Reset every stream")
+
+	return cmd
+}
+
+func NewDeleteSyncStatusCmd() *cobra.Command {
+	var (
+		filter repository.SyncStatusFilter
+		all    bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "delete",
+		Short: "Delete synchronization status records",
+		Long:  `Delete the sync status records of the matching streams, e.g. of delisted symbols. Stored data is kept.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := requireSyncStatusFilter(filter, all); err != nil {
+				return err
+			}
+			return runDeleteSyncStatus(filter)
+		},
+	}
+
+	addSyncStatusFilterFlags(cmd, &filter)
+	cmd.Flags().BoolVar(&all, "all", false, "Delete every stream")
+
+	return cmd
+}
+
+// addSyncStatusFilterFlags registers the stream key filters shared by the status commands
+func addSyncStatusFilterFlags(cmd *cobra.Command, filter *repository.SyncStatusFilter) {
+	cmd.Flags().StringVar(&filter.Market, "market", "", "Market, e.g. spot")
+	cmd.Flags().StringVarP(&filter.Symbol, "symbol", "s", "", "Symbol")
+	cmd.Flags().StringVarP(&filter.DataType, "type", "t", "", "Data type: kline, ticker, trade or depth")
+	cmd.Flags().StringVarP(&filter.Interval, "interval", "i", "", "Kline interval")
+}
+
+// requireSyncStatusFilter refuses to change every stream unless --all is given
+func requireSyncStatusFilter(filter repository.SyncStatusFilter, all bool) error {
+	if all || filter.Market != "" || filter.Symbol != "" || filter.DataType != "" || filter.Interval != "" {
+		return nil
+	}
+	return fmt.Errorf("select streams with --market, --symbol, --type or --interval, or pass --all")
+}
+
 func NewHealthCheckCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "health",
@@ -54,7 +126,7 @@ func NewHealthCheckCmd() *cobra.Command {
 	return cmd
 }
 
-func runSyncStatus(symbol string) error {
+func runSyncStatus(filter repository.SyncStatusFilter) error {
 	cfg, log, ctx, err := getSharedResources()
 	if err != nil {
 		return err
@@ -71,47 +143,72 @@ func runSyncStatus(symbol string) error {
 	// Initialize repository
 	syncStatusRepo := repository.NewSyncStatusRepository(db)
 
-	if symbol != "" {
-		// Show status for specific symbol
-		symbol = strings.ToUpper(symbol)
-		// Note: GetSyncStatusesBySymbol method needs to be added to repository
-		// For now, get all statuses and filter
-		allStatuses, err := syncStatusRepo.GetAllSyncStatuses(ctx)
-		if err != nil {
-			return fmt.Errorf("failed to get sync status for %s: %w", symbol, err)
-		}
-
-		// Filter statuses for the specific symbol
-		var statuses []models.SyncStatus
-		for _, status := range allStatuses {
-			if status.Symbol == symbol {
-				statuses = append(statuses, status)
-			}
-		}
+	filter.Symbol = strings.ToUpper(filter.Symbol)
+	statuses, err := syncStatusRepo.List(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("failed to get sync statuses: %w", err)
+	}
 
-		if len(statuses) == 0 {
-			fmt.Printf("No sync status found for symbol %s\n", symbol)
-			return nil
-		}
+	if len(statuses) == 0 {
+		fmt.Println("No sync status records found")
+		return nil
+	}
 
-		fmt.Printf("Sync status for %s:\n\n", symbol)
-		printSyncStatusTable(statuses)
+	if filter.Symbol != "" {
+		fmt.Printf("Sync status for %s:\n\n", filter.Symbol)
 	} else {
-		// Show status for all active symbols
-		statuses, err := syncStatusRepo.GetAllSyncStatuses(ctx)
-		if err != nil {
-			return fmt.Errorf("failed to get sync statuses: %w", err)
-		}
+		fmt.Printf("Sync status (%d records):\n\n", len(statuses))
+	}
+	printSyncStatusTable(statuses)
 
-		if len(statuses) == 0 {
-			fmt.Println("No sync status records found")
-			return nil
-		}
+	return nil
+}
+
+func runResetSyncStatus(filter repository.SyncStatusFilter) error {
+	cfg, log, ctx, err := getSharedResources()
+	if err != nil {
+		return err
+	}
+	defer log.Sync()
+
+	// Initialize database
+	db, err := database.New(&cfg.Database, log)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer db.Close()
+
+	filter.Symbol = strings.ToUpper(filter.Symbol)
+	reset, err := repository.NewSyncStatusRepository(db).Reset(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Reset %d streams\n", reset)
+	return nil
+}
 
-		fmt.Printf("Sync status for all active symbols (%d records):\n\n", len(statuses))
-		printSyncStatusTable(statuses)
+func runDeleteSyncStatus(filter repository.SyncStatusFilter) error {
+	cfg, log, ctx, err := getSharedResources()
+	if err != nil {
+		return err
 	}
+	defer log.Sync()
 
+	// Initialize database
+	db, err := database.New(&cfg.Database, log)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer db.Close()
+
+	filter.Symbol = strings.ToUpper(filter.Symbol)
+	deleted, err := repository.NewSyncStatusRepository(db).Delete(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Deleted %d sync status records\n", deleted)
 	return nil
 }
 
@@ -122,7 +219,7 @@ func runHealthCheck() error {
 	}
 	defer log.Sync()
 
-	fmt.Println("Performing health check...\n")
+	fmt.Print("Performing health check...\n\n")
 
 	// Check database connectivity
 	fmt.Print("Database connection: ")
@@ -162,17 +259,12 @@ func runHealthCheck() error {
 
 func printSyncStatusTable(statuses []models.SyncStatus) {
 	// Print header
-	fmt.Printf("%-15s %-10s %-10s %-15s %-15s %-10s %-20s\n",
-		"SYMBOL", "DATA_TYPE", "INTERVAL", "LAST_SYNC", "LAST_DATA", "STATUS", "ERROR")
+	fmt.Printf("%-8s %-15s %-10s %-10s %-15s %-15s %-10s %-20s\n",
+		"MARKET", "SYMBOL", "DATA_TYPE", "INTERVAL", "LAST_SYNC", "LAST_DATA", "STATUS", "ERROR")
 	fmt.Println(strings.Repeat("-", 110))
 
 	// Print actual status data
 	for _, status := range statuses {
-		interval := ""
-		if status.Interval != nil {
-			interval = *status.Interval
-		}
-
 		errorMsg := ""
 		if status.ErrorMessage != nil {
 			errorMsg = *status.ErrorMessage
@@ -184,8 +276,8 @@ func printSyncStatusTable(statuses []models.SyncStatus) {
 		lastSync := formatTimestamp(status.LastSyncTime)
 		lastData := formatTimestamp(status.LastDataTime)
 
-		fmt.Printf("%-15s %-10s %-10s %-15s %-15s %-10s %-20s\n",
-			status.Symbol, status.DataType, interval, lastSync, lastData, status.Status, errorMsg)
+		fmt.Printf("%-8s %-15s %-10s %-10s %-15s %-15s %-10s %-20s\n",
+			status.Market, status.Symbol, status.DataType, status.Interval, lastSync, lastData, status.Status, errorMsg)
 	}
 }
 
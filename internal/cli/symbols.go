@@ -143,9 +143,7 @@ func runListSymbols(activeOnly bool) error {
 			return fmt.Errorf("failed to get active symbols: %w", err)
 		}
 	} else {
-		// Note: You'd need to add a GetAllSymbols method to the repository
-		// For now, we'll just get active symbols
-		symbols, err = symbolRepo.GetActiveSymbols(ctx)
+		symbols, err = symbolRepo.GetAllSymbols(ctx)
 		if err != nil {
 			return fmt.Errorf("failed to get symbols: %w", err)
 		}
@@ -1,11 +1,11 @@
 package cli
 
 import (
-	"context"
 	"fmt"
 	"strings"
 
 	"github.com/binance-live/internal/binance"
+	"github.com/binance-live/internal/config"
 	"github.com/binance-live/internal/database"
 	"github.com/binance-live/internal/repository"
 	"github.com/binance-live/internal/service"
@@ -22,6 +22,10 @@ func NewSyncCmd() *cobra.Command {
 
 	syncCmd.AddCommand(NewSyncAllKlinesCmd())
 	syncCmd.AddCommand(NewSyncSymbolKlineCmd())
+	syncCmd.AddCommand(NewSyncGapsCmd())
+	syncCmd.AddCommand(NewSyncRangeCmd())
+	syncCmd.AddCommand(NewSyncImportCmd())
+	syncCmd.AddCommand(NewSyncJobsCmd())
 
 	return syncCmd
 }
@@ -53,10 +57,11 @@ func NewSyncAllKlinesCmd() *cobra.Command {
 
 func NewSyncSymbolKlineCmd() *cobra.Command {
 	var (
-		symbol    string
-		intervals []string
-		batchSize int
-		maxHours  int
+		symbol        string
+		intervals     []string
+		batchSize     int
+		maxHours      int
+		forceInactive bool
 	)
 
 	cmd := &cobra.Command{
@@ -67,7 +72,7 @@ func NewSyncSymbolKlineCmd() *cobra.Command {
 			if symbol == "" {
 				return fmt.Errorf("symbol is required")
 			}
-			return runSyncSymbolKline(symbol, intervals, batchSize, maxHours)
+			return runSyncSymbolKline(symbol, intervals, batchSize, maxHours, forceInactive)
 		},
 	}
 
@@ -75,6 +80,7 @@ func NewSyncSymbolKlineCmd() *cobra.Command {
 	cmd.Flags().StringSliceVarP(&intervals, "intervals", "i", []string{"1m", "15m", "1h", "4h", "1d"}, "Kline intervals to sync")
 	cmd.Flags().IntVarP(&batchSize, "batch-size", "b", 200, "Batch size for fetching klines")
 	cmd.Flags().IntVarP(&maxHours, "max-hours", "m", 24, "Maximum hours to sync backwards")
+	cmd.Flags().BoolVar(&forceInactive, "force-inactive", false, "Sync the symbol even if it is not active")
 	cmd.MarkFlagRequired("symbol")
 
 	return cmd
@@ -104,6 +110,7 @@ func runSyncAllKlines(intervals []string, workers, batchSize, maxHours int) erro
 	// Initialize repositories
 	symbolRepo := repository.NewSymbolRepository(db)
 	klineRepo := repository.NewKlineRepository(db)
+	klineGapRepo := repository.NewKlineGapRepository(db)
 	syncStatusRepo := repository.NewSyncStatusRepository(db)
 
 	// Initialize Binance client
@@ -125,8 +132,12 @@ func runSyncAllKlines(intervals []string, workers, batchSize, maxHours int) erro
 		binanceClient,
 		symbolRepo,
 		klineRepo,
+		klineGapRepo,
+		nil, // backfill job repo not needed for incremental sync
 		nil, // ticker repo not needed for klines
+		nil, // trade repo not needed for klines
 		syncStatusRepo,
+		repository.NewSyncJobRepository(db),
 		&cfg.Sync,
 		&cfg.Binance,
 		log,
@@ -141,7 +152,7 @@ func runSyncAllKlines(intervals []string, workers, batchSize, maxHours int) erro
 	return nil
 }
 
-func runSyncSymbolKline(symbol string, intervals []string, batchSize, maxHours int) error {
+func runSyncSymbolKline(symbol string, intervalNames []string, batchSize, maxHours int, forceInactive bool) error {
 	cfg, log, ctx, err := getSharedResources()
 	if err != nil {
 		return err
@@ -151,11 +162,17 @@ func runSyncSymbolKline(symbol string, intervals []string, batchSize, maxHours i
 	// Validate symbol format
 	symbol = strings.ToUpper(symbol)
 
+	intervals, err := binance.ParseIntervals(intervalNames)
+	if err != nil {
+		return err
+	}
+
 	log.Info("Starting sync symbol kline",
 		zap.String("symbol", symbol),
-		zap.Strings("intervals", intervals),
+		zap.Strings("intervals", intervalNames),
 		zap.Int("batch_size", batchSize),
 		zap.Int("max_hours", maxHours),
+		zap.Bool("force_inactive", forceInactive),
 	)
 
 	// Initialize database
@@ -165,66 +182,15 @@ func runSyncSymbolKline(symbol string, intervals []string, batchSize, maxHours i
 	}
 	defer db.Close()
 
-	// Initialize repositories
-	symbolRepo := repository.NewSymbolRepository(db)
-	klineRepo := repository.NewKlineRepository(db)
-	syncStatusRepo := repository.NewSyncStatusRepository(db)
+	syncService := newDataSyncService(cfg, log, db)
 
-	// Check if symbol exists and is active
-	symbolData, err := symbolRepo.GetSymbolByName(ctx, symbol)
+	err = syncService.SyncSymbol(ctx, symbol, intervals, service.SyncOptions{
+		BatchSize:     batchSize,
+		MaxSyncHours:  maxHours,
+		ForceInactive: forceInactive,
+	})
 	if err != nil {
-		return fmt.Errorf("failed to get symbol %s: %w", symbol, err)
-	}
-
-	if !symbolData.IsActive {
-		return fmt.Errorf("symbol %s is not active", symbol)
-	}
-
-	// Initialize Binance client
-	binanceClient := binance.NewClient(cfg, log)
-
-	// Test connectivity
-	if err := binanceClient.REST.Ping(ctx); err != nil {
-		return fmt.Errorf("failed to connect to Binance API: %w", err)
-	}
-
-	// Override config values
-	cfg.Sync.Workers = 1 // Use single worker for specific symbol
-	cfg.Sync.BatchSize = batchSize
-	cfg.Sync.MaxSyncHours = maxHours
-	cfg.Binance.KlineIntervals = intervals
-
-	// Initialize sync service
-	syncService := service.NewDataSyncService(
-		binanceClient,
-		symbolRepo,
-		klineRepo,
-		nil, // ticker repo not needed for klines
-		syncStatusRepo,
-		&cfg.Sync,
-		&cfg.Binance,
-		log,
-	)
-
-	// Sync each interval for the symbol
-	for _, interval := range intervals {
-		log.Info("Syncing klines for symbol and interval",
-			zap.String("symbol", symbol),
-			zap.String("interval", interval),
-		)
-
-		// This is a simplified approach - in a real implementation, you might want to
-		// create a method specifically for syncing a single symbol
-		// For now, we'll use the existing sync service but modify the active symbols
-		// to only include our target symbol
-		if err := syncSingleSymbolKline(ctx, syncService, symbol, interval, log); err != nil {
-			log.Error("Failed to sync kline",
-				zap.String("symbol", symbol),
-				zap.String("interval", interval),
-				zap.Error(err),
-			)
-			return err
-		}
+		return fmt.Errorf("synchronization failed: %w", err)
 	}
 
 	log.Info("Sync symbol kline completed successfully",
@@ -233,19 +199,20 @@ func runSyncSymbolKline(symbol string, intervals []string, batchSize, maxHours i
 	return nil
 }
 
-// Helper function to sync a single symbol kline
-func syncSingleSymbolKline(ctx context.Context, syncService *service.DataSyncService, symbol, interval string, log *zap.Logger) error {
-	// Use reflection or create a new method in DataSyncService to sync specific symbol
-	// For now, this is a placeholder - you'd need to modify the DataSyncService
-	// to expose a method for syncing individual symbols
-	log.Info("Syncing specific symbol kline",
-		zap.String("symbol", symbol),
-		zap.String("interval", interval),
+// newDataSyncService wires a DataSyncService with all repositories it needs
+func newDataSyncService(cfg *config.Config, log *zap.Logger, db *database.Database) *service.DataSyncService {
+	return service.NewDataSyncService(
+		binance.NewClient(cfg, log),
+		repository.NewSymbolRepository(db),
+		repository.NewKlineRepository(db),
+		repository.NewKlineGapRepository(db),
+		repository.NewBackfillJobRepository(db),
+		nil, // ticker repo not needed for klines
+		repository.NewTradeRepository(db),
+		repository.NewSyncStatusRepository(db),
+		repository.NewSyncJobRepository(db),
+		&cfg.Sync,
+		&cfg.Binance,
+		log,
 	)
-
-	// This would need to be implemented in the DataSyncService
-	// return syncService.SyncSymbolKline(ctx, symbol, interval)
-
-	// For now, use the full sync but it will only process active symbols
-	return syncService.SyncMissingData(ctx)
 }
@@ -9,12 +9,18 @@ import (
 
 // Config holds all application configuration
 type Config struct {
-	App      AppConfig      `mapstructure:"app"`
-	Binance  BinanceConfig  `mapstructure:"binance"`
-	Database DatabaseConfig `mapstructure:"database"`
-	Redis    RedisConfig    `mapstructure:"redis"`
-	Sync     SyncConfig     `mapstructure:"sync"`
-	Stream   StreamConfig   `mapstructure:"stream"`
+	App       AppConfig       `mapstructure:"app"`
+	Binance   BinanceConfig   `mapstructure:"binance"`
+	Database  DatabaseConfig  `mapstructure:"database"`
+	Redis     RedisConfig     `mapstructure:"redis"`
+	Publisher PublisherConfig `mapstructure:"publisher"`
+	Sync      SyncConfig      `mapstructure:"sync"`
+	Stream    StreamConfig    `mapstructure:"stream"`
+	Tickers   TickersConfig   `mapstructure:"tickers"`
+	Bars      BarsConfig      `mapstructure:"bars"`
+	Retention RetentionConfig `mapstructure:"retention"`
+	API       APIConfig       `mapstructure:"api"`
+	GRPC      GRPCConfig      `mapstructure:"grpc"`
 }
 
 // AppConfig holds application-level configuration
@@ -26,10 +32,12 @@ type AppConfig struct {
 
 // BinanceConfig holds Binance API configuration
 type BinanceConfig struct {
-	APIURL         string   `mapstructure:"api_url"`
-	WSURL          string   `mapstructure:"ws_url"`
-	RestRateLimit  int      `mapstructure:"rest_rate_limit"`
-	KlineIntervals []string `mapstructure:"kline_intervals"`
+	APIURL              string   `mapstructure:"api_url"`
+	WSURL               string   `mapstructure:"ws_url"`
+	RestRateLimit       int      `mapstructure:"rest_rate_limit"`
+	KlineIntervals      []string `mapstructure:"kline_intervals"`
+	DerivedBaseInterval string   `mapstructure:"derived_base_interval"`
+	DerivedIntervals    []string `mapstructure:"derived_intervals"`
 }
 
 // DatabaseConfig holds database configuration
@@ -43,6 +51,7 @@ type DatabaseConfig struct {
 	MaxConnections        int    `mapstructure:"max_connections"`
 	MaxIdleConnections    int    `mapstructure:"max_idle_connections"`
 	ConnectionMaxLifetime int    `mapstructure:"connection_max_lifetime"`
+	MigrateOnStart        bool   `mapstructure:"migrate_on_start"`
 }
 
 // RedisConfig holds Redis configuration
@@ -55,12 +64,52 @@ type RedisConfig struct {
 	LiveDataTTL int    `mapstructure:"live_data_ttl"`
 }
 
+// PublisherConfig holds live data publishing configuration
+type PublisherConfig struct {
+	Backends      []string      `mapstructure:"backends"`          // redis, kafka and/or nats
+	Format        string        `mapstructure:"format"`            // protobuf, json or both
+	Mode          string        `mapstructure:"mode"`              // pubsub, streams or both
+	StreamMaxLen  int64         `mapstructure:"stream_max_len"`    // Approximate entries kept per stream
+	FlushInterval int           `mapstructure:"flush_interval_ms"` // Milliseconds messages are batched for, 0: send each at once
+	BatchSize     int           `mapstructure:"batch_size"`        // Messages per pipeline at most
+	History       HistoryConfig `mapstructure:"history"`
+	Kafka         KafkaConfig   `mapstructure:"kafka"`
+	NATS          NATSConfig    `mapstructure:"nats"`
+}
+
+// HistoryConfig sizes the recent history kept in Redis for consumers warming up
+type HistoryConfig struct {
+	Klines int64 `mapstructure:"klines"` // Closed klines and bars kept per symbol and interval, 0: none
+	Trades int64 `mapstructure:"trades"` // Trades kept per symbol, 0: none
+}
+
+// KafkaConfig holds the Kafka publisher backend configuration
+type KafkaConfig struct {
+	Brokers      []string `mapstructure:"brokers"`
+	Acks         string   `mapstructure:"acks"`             // none, one or all
+	BatchTimeout int      `mapstructure:"batch_timeout_ms"` // Milliseconds a partial batch waits before it is sent
+	MaxAttempts  int      `mapstructure:"max_attempts"`     // Attempts per batch before its messages are dropped
+	Sync         bool     `mapstructure:"sync"`             // Wait for the acks of each message and return its delivery error
+}
+
+// NATSConfig holds the NATS publisher backend configuration
+type NATSConfig struct {
+	URL               string `mapstructure:"url"`
+	JetStream         bool   `mapstructure:"jetstream"`            // Publish to a stream with acks instead of core NATS
+	Stream            string `mapstructure:"stream"`               // JetStream stream created on binance.> if missing, empty: managed elsewhere
+	MaxMsgsPerSubject int64  `mapstructure:"max_msgs_per_subject"` // Messages the created stream keeps per subject
+	MaxPending        int    `mapstructure:"max_pending"`          // Unacknowledged messages before publishing waits
+}
+
 // SyncConfig holds data synchronization configuration
 type SyncConfig struct {
-	Enabled      bool `mapstructure:"enabled"`
-	MaxSyncHours int  `mapstructure:"max_sync_hours"`
-	BatchSize    int  `mapstructure:"batch_size"`
-	Workers      int  `mapstructure:"workers"`
+	Enabled        bool `mapstructure:"enabled"`
+	MaxSyncHours   int  `mapstructure:"max_sync_hours"`
+	BatchSize      int  `mapstructure:"batch_size"`
+	Workers        int  `mapstructure:"workers"`
+	GapMaxAttempts int  `mapstructure:"gap_max_attempts"`
+	JobMaxAttempts int  `mapstructure:"job_max_attempts"`
+	JobStaleAfter  int  `mapstructure:"job_stale_after"`
 }
 
 // StreamConfig holds WebSocket streaming configuration
@@ -70,6 +119,72 @@ type StreamConfig struct {
 	PingInterval         int `mapstructure:"ping_interval"`
 }
 
+// TickersConfig selects which ticker events are stored. Every event is still published.
+type TickersConfig struct {
+	StoreMode       string  `mapstructure:"store_mode"`       // all, sample or change
+	SampleInterval  int     `mapstructure:"sample_interval"`  // Seconds per stored ticker in sample mode
+	ChangeThreshold float64 `mapstructure:"change_threshold"` // Price change in percent that is stored in change mode
+	MaxInterval     int     `mapstructure:"max_interval"`     // Seconds after which change mode stores anyway, 0: never
+}
+
+// BarsConfig holds configuration for bars built locally from the aggTrade stream
+type BarsConfig struct {
+	Enabled bool     `mapstructure:"enabled"`
+	Specs   []string `mapstructure:"specs"`
+	Store   bool     `mapstructure:"store"`
+}
+
+// RetentionConfig holds data retention and compression configuration.
+// Retention periods are in days; 0 keeps data forever.
+type RetentionConfig struct {
+	Klines             KlineRetentionConfig `mapstructure:"klines"`
+	TickersDays        int                  `mapstructure:"tickers_days"`
+	TradesDays         int                  `mapstructure:"trades_days"`
+	DepthSnapshotsDays int                  `mapstructure:"depth_snapshots_days"`
+	BarsDays           int                  `mapstructure:"bars_days"`
+	CompressAfterDays  int                  `mapstructure:"compress_after_days"` // 0 disables compression policies
+}
+
+// KlineRetentionConfig holds per-interval kline retention
+type KlineRetentionConfig struct {
+	DefaultDays int                       `mapstructure:"default_days"`
+	Intervals   []IntervalRetentionConfig `mapstructure:"intervals"`
+}
+
+// IntervalRetentionConfig overrides the kline retention of one interval
+type IntervalRetentionConfig struct {
+	Interval string `mapstructure:"interval"`
+	Days     int    `mapstructure:"days"`
+}
+
+// APIConfig holds HTTP query API configuration
+type APIConfig struct {
+	Enabled      bool            `mapstructure:"enabled"`
+	Host         string          `mapstructure:"host"`
+	Port         int             `mapstructure:"port"`
+	DefaultLimit int             `mapstructure:"default_limit"` // Rows per page when no limit is given
+	MaxLimit     int             `mapstructure:"max_limit"`     // Largest accepted limit
+	WebSocket    WebSocketConfig `mapstructure:"websocket"`
+}
+
+// WebSocketConfig holds WebSocket gateway configuration. The gateway is served by
+// the HTTP API at /v1/ws.
+type WebSocketConfig struct {
+	Enabled        bool     `mapstructure:"enabled"`
+	Source         string   `mapstructure:"source"`          // redis or inprocess
+	ClientBuffer   int      `mapstructure:"client_buffer"`   // Frames queued per client before it is disconnected
+	AllowedOrigins []string `mapstructure:"allowed_origins"` // Browser origins allowed besides the API's own, "*" for any
+}
+
+// GRPCConfig holds gRPC market data service configuration. Page limits are shared
+// with the HTTP API.
+type GRPCConfig struct {
+	Enabled          bool   `mapstructure:"enabled"`
+	Host             string `mapstructure:"host"`
+	Port             int    `mapstructure:"port"`
+	SubscriberBuffer int    `mapstructure:"subscriber_buffer"` // Messages buffered per Subscribe stream
+}
+
 // Load reads configuration from file and environment variables
 func Load(configPath string) (*Config, error) {
 	v := viper.New()
@@ -117,6 +232,7 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("binance.ws_url", "wss://stream.binance.com:9443")
 	v.SetDefault("binance.rest_rate_limit", 1200)
 	v.SetDefault("binance.kline_intervals", []string{"1m", "5m", "1h", "1d"})
+	v.SetDefault("binance.derived_base_interval", "1m")
 
 	v.SetDefault("database.host", "localhost")
 	v.SetDefault("database.port", 5432)
@@ -127,6 +243,7 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("database.max_connections", 25)
 	v.SetDefault("database.max_idle_connections", 5)
 	v.SetDefault("database.connection_max_lifetime", 300)
+	v.SetDefault("database.migrate_on_start", false)
 
 	v.SetDefault("redis.host", "localhost")
 	v.SetDefault("redis.port", 6379)
@@ -135,14 +252,102 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("redis.pool_size", 10)
 	v.SetDefault("redis.live_data_ttl", 60)
 
+	v.SetDefault("publisher.backends", []string{"redis"})
+	v.SetDefault("publisher.format", "protobuf")
+	v.SetDefault("publisher.mode", "pubsub")
+	v.SetDefault("publisher.stream_max_len", 10000)
+	v.SetDefault("publisher.flush_interval_ms", 0)
+	v.SetDefault("publisher.batch_size", 500)
+	v.SetDefault("publisher.history.klines", 500)
+	v.SetDefault("publisher.history.trades", 1000)
+	v.SetDefault("publisher.kafka.brokers", []string{"localhost:9092"})
+	v.SetDefault("publisher.kafka.acks", "all")
+	v.SetDefault("publisher.kafka.batch_timeout_ms", 10)
+	v.SetDefault("publisher.kafka.max_attempts", 10)
+	v.SetDefault("publisher.kafka.sync", false)
+	v.SetDefault("publisher.nats.url", "nats://localhost:4222")
+	v.SetDefault("publisher.nats.jetstream", true)
+	v.SetDefault("publisher.nats.stream", "BINANCE")
+	v.SetDefault("publisher.nats.max_msgs_per_subject", 10000)
+	v.SetDefault("publisher.nats.max_pending", 4096)
+
 	v.SetDefault("sync.enabled", true)
 	v.SetDefault("sync.max_sync_hours", 24)
 	v.SetDefault("sync.batch_size", 1000)
 	v.SetDefault("sync.workers", 5)
+	v.SetDefault("sync.gap_max_attempts", 3)
+	v.SetDefault("sync.job_max_attempts", 3)
+	v.SetDefault("sync.job_stale_after", 600)
 
 	v.SetDefault("stream.reconnect_delay", 5)
 	v.SetDefault("stream.max_reconnect_attempts", 10)
 	v.SetDefault("stream.ping_interval", 30)
+
+	v.SetDefault("tickers.store_mode", "all")
+	v.SetDefault("tickers.sample_interval", 10)
+	v.SetDefault("tickers.change_threshold", 0.05)
+	v.SetDefault("tickers.max_interval", 60)
+
+	v.SetDefault("bars.enabled", false)
+	v.SetDefault("bars.store", false)
+
+	v.SetDefault("retention.klines.default_days", 0)
+	v.SetDefault("retention.tickers_days", 30)
+	v.SetDefault("retention.trades_days", 90)
+	v.SetDefault("retention.depth_snapshots_days", 7)
+	v.SetDefault("retention.bars_days", 0)
+	v.SetDefault("retention.compress_after_days", 7)
+
+	v.SetDefault("api.enabled", false)
+	v.SetDefault("api.host", "0.0.0.0")
+	v.SetDefault("api.port", 8080)
+	v.SetDefault("api.default_limit", 500)
+	v.SetDefault("api.max_limit", 1000)
+	v.SetDefault("api.websocket.enabled", false)
+	v.SetDefault("api.websocket.source", "redis")
+	v.SetDefault("api.websocket.client_buffer", 256)
+
+	v.SetDefault("grpc.enabled", false)
+	v.SetDefault("grpc.host", "0.0.0.0")
+	v.SetDefault("grpc.port", 9090)
+	v.SetDefault("grpc.subscriber_buffer", 1024)
+}
+
+// NativeKlineIntervals returns the intervals fetched from Binance: the configured kline
+// intervals without the derived ones, plus the derived base interval
+func (c *BinanceConfig) NativeKlineIntervals() []string {
+	derived := make(map[string]bool, len(c.DerivedIntervals))
+	for _, interval := range c.DerivedIntervals {
+		derived[interval] = true
+	}
+
+	intervals := make([]string, 0, len(c.KlineIntervals)+1)
+	hasBase := c.DerivedBaseInterval == "" || len(c.DerivedIntervals) == 0
+	for _, interval := range c.KlineIntervals {
+		if derived[interval] {
+			continue
+		}
+		if interval == c.DerivedBaseInterval {
+			hasBase = true
+		}
+		intervals = append(intervals, interval)
+	}
+
+	if !hasBase {
+		intervals = append(intervals, c.DerivedBaseInterval)
+	}
+
+	return intervals
+}
+
+// DaysFor returns the retention in days for klines of interval
+func (c *KlineRetentionConfig) DaysFor(interval string) int {
+	for _, r := range c.Intervals {
+		if r.Interval == interval {
+			return r.Days
+		}
+	}
+	return c.DefaultDays
 }
 
 // GetDSN returns the PostgreSQL connection string
@@ -157,3 +362,13 @@ func (c *DatabaseConfig) GetDSN() string {
 func (c *RedisConfig) GetRedisAddr() string {
 	return fmt.Sprintf("%s:%d", c.Host, c.Port)
 }
+
+// GetAddr returns the HTTP API listen address
+func (c *APIConfig) GetAddr() string {
+	return fmt.Sprintf("%s:%d", c.Host, c.Port)
+}
+
+// GetAddr returns the gRPC listen address
+func (c *GRPCConfig) GetAddr() string {
+	return fmt.Sprintf("%s:%d", c.Host, c.Port)
+}
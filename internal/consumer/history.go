@@ -0,0 +1,49 @@
+package consumer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/binance-live/internal/publisher"
+	"github.com/binance-live/internal/redis"
+	binanceProto "github.com/binance-live/proto"
+	"google.golang.org/protobuf/proto"
+)
+
+// RecentKlines returns up to limit of the newest closed klines of symbol and interval
+// kept in Redis, oldest first, e.g. to warm up indicators without querying the database
+func RecentKlines(ctx context.Context, redisClient *redis.Client, symbol, interval string, limit int64) ([]*binanceProto.LiveData, error) {
+	return recentHistory(ctx, redisClient, publisher.KlineChannel(symbol, interval), limit)
+}
+
+// RecentBars returns up to limit of the newest bars of symbol and bar spec kept in
+// Redis, oldest first
+func RecentBars(ctx context.Context, redisClient *redis.Client, symbol, spec string, limit int64) ([]*binanceProto.LiveData, error) {
+	return recentHistory(ctx, redisClient, publisher.BarChannel(symbol, spec), limit)
+}
+
+// RecentTrades returns up to limit of the newest trades of symbol kept in Redis, oldest first
+func RecentTrades(ctx context.Context, redisClient *redis.Client, symbol string, limit int64) ([]*binanceProto.LiveData, error) {
+	return recentHistory(ctx, redisClient, publisher.TradeChannel(symbol), limit)
+}
+
+// recentHistory decodes up to limit of the newest messages of the history of channel,
+// upgraded to the current schema version
+func recentHistory(ctx context.Context, redisClient *redis.Client, channel string, limit int64) ([]*binanceProto.LiveData, error) {
+	values, err := redisClient.GetHistory(ctx, publisher.HistoryKey(channel), limit)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*binanceProto.LiveData, 0, len(values))
+	for _, value := range values {
+		var liveData binanceProto.LiveData
+		if err := proto.Unmarshal(value, &liveData); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal protobuf data: %w", err)
+		}
+		UpgradeLiveData(&liveData)
+		result = append(result, &liveData)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,115 @@
+package consumer
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/binance-live/internal/config"
+	"github.com/binance-live/internal/publisher"
+	"github.com/binance-live/internal/redis"
+	binanceProto "github.com/binance-live/proto"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+)
+
+// testRedis starts an in-memory Redis server and connects a client to it
+func testRedis(t *testing.T) (*miniredis.Miniredis, *redis.Client) {
+	t.Helper()
+
+	m := miniredis.RunT(t)
+	port, err := strconv.Atoi(m.Port())
+	if err != nil {
+		t.Fatalf("invalid miniredis port: %v", err)
+	}
+	client, err := redis.New(&config.RedisConfig{Host: m.Host(), Port: port, PoolSize: 2, LiveDataTTL: 60}, zap.NewNop())
+	if err != nil {
+		t.Fatalf("failed to connect to miniredis: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+
+	return m, client
+}
+
+// addHistory adds liveData to the history of channel by score, as the publisher does
+func addHistory(t *testing.T, client *redis.Client, channel string, maxLen, score int64, liveData *binanceProto.LiveData) {
+	t.Helper()
+
+	data, err := proto.Marshal(liveData)
+	if err != nil {
+		t.Fatalf("failed to marshal live data: %v", err)
+	}
+	err = client.Pipelined(context.Background(), func(b *redis.Batch) {
+		b.AddToHistory(publisher.HistoryKey(channel), score, maxLen, data)
+	})
+	if err != nil {
+		t.Fatalf("failed to add to history: %v", err)
+	}
+}
+
+func TestRecentKlines(t *testing.T) {
+	_, client := testRedis(t)
+	channel := publisher.KlineChannel("BTCUSDT", "1m")
+
+	// Out of order, one kline sent twice and more than the 3 kept
+	for _, openTime := range []int64{120000, 0, 60000, 180000, 240000, 180000} {
+		kline := &binanceProto.KlineData{Interval: "1m", OpenTime: openTime, CloseTime: openTime + 59999, ClosePrice: float64(openTime), IsClosed: true}
+		addHistory(t, client, channel, 3, openTime, &binanceProto.LiveData{
+			Type:    binanceProto.DataType_DATA_TYPE_KLINE,
+			Symbol:  "BTCUSDT",
+			Data:    &binanceProto.LiveData_Kline{Kline: kline},
+			Version: publisher.SchemaVersion,
+		})
+	}
+
+	klines, err := RecentKlines(context.Background(), client, "BTCUSDT", "1m", 10)
+	if err != nil {
+		t.Fatalf("failed to read recent klines: %v", err)
+	}
+
+	want := []int64{120000, 180000, 240000}
+	if len(klines) != len(want) {
+		t.Fatalf("got %d klines, want %d", len(klines), len(want))
+	}
+	for i, liveData := range klines {
+		if got := liveData.GetKline().GetOpenTime(); got != want[i] {
+			t.Errorf("got kline %d opening at %d, want %d", i, got, want[i])
+		}
+	}
+
+	// Other intervals have their own history
+	klines, err = RecentKlines(context.Background(), client, "BTCUSDT", "5m", 10)
+	if err != nil || len(klines) != 0 {
+		t.Errorf("got %d 5m klines (%v), want none", len(klines), err)
+	}
+}
+
+func TestRecentTrades(t *testing.T) {
+	_, client := testRedis(t)
+	channel := publisher.TradeChannel("ETHUSDT")
+
+	for _, id := range []int64{7, 5, 6, 8} {
+		addHistory(t, client, channel, 10, id, &binanceProto.LiveData{
+			Type:    binanceProto.DataType_DATA_TYPE_TRADE,
+			Symbol:  "ETHUSDT",
+			Data:    &binanceProto.LiveData_Trade{Trade: &binanceProto.TradeData{TradeId: id}},
+			Version: publisher.SchemaVersion,
+		})
+	}
+
+	trades, err := RecentTrades(context.Background(), client, "ETHUSDT", 3)
+	if err != nil {
+		t.Fatalf("failed to read recent trades: %v", err)
+	}
+
+	want := []int64{6, 7, 8}
+	if len(trades) != len(want) {
+		t.Fatalf("got %d trades, want %d", len(trades), len(want))
+	}
+	for i, liveData := range trades {
+		if got := liveData.GetTrade().GetTradeId(); got != want[i] {
+			t.Errorf("got trade %d with id %d, want %d", i, got, want[i])
+		}
+	}
+}
@@ -21,12 +21,13 @@ func NewProtobufConsumer(logger *zap.Logger) *ProtobufConsumer {
 	}
 }
 
-// ConsumeLiveData consumes a protobuf live data message
+// ConsumeLiveData consumes a protobuf live data message, upgraded to the current schema version
 func (c *ProtobufConsumer) ConsumeLiveData(ctx context.Context, data []byte) (*binanceProto.LiveData, error) {
 	var liveData binanceProto.LiveData
 	if err := proto.Unmarshal(data, &liveData); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal protobuf data: %w", err)
 	}
+	UpgradeLiveData(&liveData)
 
 	return &liveData, nil
 }
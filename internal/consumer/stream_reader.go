@@ -0,0 +1,324 @@
+package consumer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/binance-live/internal/redis"
+	binanceProto "github.com/binance-live/proto"
+	goredis "github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+)
+
+// StreamMessage is a live data entry read from a Redis stream
+type StreamMessage struct {
+	Stream string
+	ID     string
+	Data   *binanceProto.LiveData
+}
+
+// Fields added to the entries copied to a dead letter stream
+const (
+	DeadLetterStreamField = "source_stream" // The stream the entry was read from
+	DeadLetterIDField     = "source_id"     // Its ID there
+)
+
+// StreamReaderConfig configures a consumer group reader
+type StreamReaderConfig struct {
+	Streams          []string      // Stream keys, see publisher.StreamKey
+	Group            string        // Consumer group shared by the instances of a service
+	Consumer         string        // Name of this instance, unique within the group
+	StartID          string        // Where a new group starts: "$" (default) for new entries, "0" for all
+	Count            int64         // Entries per read, 100 by default
+	Block            time.Duration // How long a read waits for new entries, 5s by default
+	MinIdle          time.Duration // Pending entries idle this long are retried or claimed, 1m by default
+	MaxDeliveries    int64         // Deliveries of an entry before it is given up on, 5 by default
+	DeadLetterStream string        // Stream the entries given up on are copied to, empty: they are dropped
+}
+
+// StreamReader reads live data from Redis streams as a member of a consumer group
+type StreamReader struct {
+	redis  *redis.Client
+	config StreamReaderConfig
+	logger *zap.Logger
+}
+
+// NewStreamReader creates a new stream reader
+func NewStreamReader(redisClient *redis.Client, cfg StreamReaderConfig, logger *zap.Logger) (*StreamReader, error) {
+	if len(cfg.Streams) == 0 {
+		return nil, fmt.Errorf("no streams to read")
+	}
+	if cfg.Group == "" || cfg.Consumer == "" {
+		return nil, fmt.Errorf("group and consumer are required")
+	}
+
+	if cfg.StartID == "" {
+		cfg.StartID = "$"
+	}
+	if cfg.Count <= 0 {
+		cfg.Count = 100
+	}
+	if cfg.Block <= 0 {
+		cfg.Block = 5 * time.Second
+	}
+	if cfg.MinIdle <= 0 {
+		cfg.MinIdle = time.Minute
+	}
+	if cfg.MaxDeliveries <= 0 {
+		cfg.MaxDeliveries = 5
+	}
+
+	return &StreamReader{
+		redis:  redisClient,
+		config: cfg,
+		logger: logger,
+	}, nil
+}
+
+// Run creates the group on each stream if needed and passes every entry to handle until
+// ctx is cancelled. An entry is acknowledged once handle returns nil; otherwise it stays
+// pending. On start and every MinIdle, the reader first retries its own pending entries,
+// then claims the entries other consumers left pending for MinIdle, e.g. by crashing.
+// An entry delivered more than MaxDeliveries times is acknowledged without calling
+// handle, after being copied to DeadLetterStream if set.
+func (r *StreamReader) Run(ctx context.Context, handle func(context.Context, *StreamMessage) error) error {
+	for _, stream := range r.config.Streams {
+		if err := r.redis.CreateStreamGroup(ctx, stream, r.config.Group, r.config.StartID); err != nil {
+			return err
+		}
+	}
+
+	var lastRecovery time.Time
+	for ctx.Err() == nil {
+		if time.Since(lastRecovery) >= r.config.MinIdle {
+			if err := r.recoverPending(ctx, handle); err != nil {
+				return r.stopped(ctx, err)
+			}
+			lastRecovery = time.Now()
+		}
+
+		streams, err := r.redis.ReadStreamGroup(
+			ctx,
+			r.config.Group,
+			r.config.Consumer,
+			r.config.Streams,
+			">",
+			r.config.Count,
+			r.config.Block,
+		)
+		if err != nil {
+			return r.stopped(ctx, err)
+		}
+
+		for _, stream := range streams {
+			r.process(ctx, stream.Stream, stream.Messages, false, handle)
+		}
+	}
+
+	return nil
+}
+
+// recoverPending retries this consumer's pending entries once, then claims and
+// processes the entries idle for MinIdle
+func (r *StreamReader) recoverPending(ctx context.Context, handle func(context.Context, *StreamMessage) error) error {
+	for _, stream := range r.config.Streams {
+		// Reading from an explicit ID returns pending entries after it
+		afterID := "0"
+		for {
+			result, err := r.redis.ReadStreamGroup(ctx, r.config.Group, r.config.Consumer, []string{stream}, afterID, r.config.Count, 0)
+			if err != nil {
+				return err
+			}
+			if len(result) == 0 || len(result[0].Messages) == 0 {
+				break
+			}
+
+			messages := result[0].Messages
+			r.process(ctx, stream, messages, true, handle)
+			afterID = messages[len(messages)-1].ID
+		}
+
+		start := "0-0"
+		for {
+			messages, next, err := r.redis.ClaimStreamPending(
+				ctx,
+				stream,
+				r.config.Group,
+				r.config.Consumer,
+				r.config.MinIdle,
+				start,
+				r.config.Count,
+			)
+			if err != nil {
+				return err
+			}
+
+			if len(messages) > 0 {
+				r.logger.Info("Claimed pending stream entries",
+					zap.String("stream", stream),
+					zap.Int("count", len(messages)),
+				)
+				r.process(ctx, stream, messages, true, handle)
+			}
+
+			if next == "0-0" {
+				break
+			}
+			start = next
+		}
+	}
+
+	return nil
+}
+
+// process passes messages to handle and acknowledges the handled ones. Entries that
+// cannot be decoded are acknowledged and skipped so they are not retried forever, and
+// so are redelivered entries past MaxDeliveries.
+func (r *StreamReader) process(
+	ctx context.Context,
+	stream string,
+	messages []goredis.XMessage,
+	redelivered bool,
+	handle func(context.Context, *StreamMessage) error,
+) {
+	var deliveries map[string]int64
+	if redelivered {
+		ids := make([]string, len(messages))
+		for i, message := range messages {
+			ids[i] = message.ID
+		}
+
+		var err error
+		deliveries, err = r.redis.StreamDeliveryCounts(ctx, stream, r.config.Group, r.config.Consumer, ids)
+		if err != nil {
+			// Retry regardless; the count is checked again on the next delivery
+			r.logger.Warn("Failed to read stream entry deliveries", zap.String("stream", stream), zap.Error(err))
+		}
+	}
+
+	acked := make([]string, 0, len(messages))
+	for _, message := range messages {
+		if count := deliveries[message.ID]; count > r.config.MaxDeliveries {
+			if err := r.giveUp(ctx, stream, message, count); err != nil {
+				r.logger.Warn("Failed to give up on stream entry, entry left pending",
+					zap.String("stream", stream),
+					zap.String("id", message.ID),
+					zap.Error(err),
+				)
+			}
+			continue
+		}
+
+		msg, err := decodeStreamMessage(stream, message)
+		if err != nil {
+			r.logger.Warn("Skipping undecodable stream entry",
+				zap.String("stream", stream),
+				zap.String("id", message.ID),
+				zap.Error(err),
+			)
+			acked = append(acked, message.ID)
+			continue
+		}
+
+		if err := handle(ctx, msg); err != nil {
+			r.logger.Warn("Stream entry handler failed, entry left pending",
+				zap.String("stream", stream),
+				zap.String("id", message.ID),
+				zap.Error(err),
+			)
+			continue
+		}
+		acked = append(acked, message.ID)
+	}
+
+	if len(acked) == 0 {
+		return
+	}
+	if err := r.redis.AckStream(ctx, stream, r.config.Group, acked...); err != nil {
+		r.logger.Warn("Failed to ack stream entries", zap.String("stream", stream), zap.Error(err))
+	}
+}
+
+// giveUp acknowledges an entry delivered count times, after copying it with its source
+// to the dead letter stream if there is one
+func (r *StreamReader) giveUp(ctx context.Context, stream string, message goredis.XMessage, count int64) error {
+	err := r.redis.Pipelined(ctx, func(b *redis.Batch) {
+		if r.config.DeadLetterStream != "" {
+			values := make(map[string]interface{}, len(message.Values)+2)
+			for field, value := range message.Values {
+				values[field] = value
+			}
+			values[DeadLetterStreamField] = stream
+			values[DeadLetterIDField] = message.ID
+			b.AddToStream(r.config.DeadLetterStream, 0, values)
+		}
+		b.AckStream(stream, r.config.Group, message.ID)
+	})
+	if err != nil {
+		return err
+	}
+
+	r.logger.Warn("Gave up on stream entry",
+		zap.String("stream", stream),
+		zap.String("id", message.ID),
+		zap.Int64("deliveries", count),
+		zap.String("dead_letter_stream", r.config.DeadLetterStream),
+	)
+	return nil
+}
+
+// stopped returns nil when err is due to ctx being cancelled, err otherwise
+func (r *StreamReader) stopped(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
+
+// ReadStream reads up to count live data entries of a stream after afterID, without a
+// consumer group, waiting up to block for new ones. Resume with the ID of the last entry
+// returned; "0" starts at the oldest entry kept.
+func ReadStream(ctx context.Context, redisClient *redis.Client, stream, afterID string, count int64, block time.Duration) ([]*StreamMessage, error) {
+	messages, err := redisClient.ReadStream(ctx, stream, afterID, count, block)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*StreamMessage, 0, len(messages))
+	for _, message := range messages {
+		msg, err := decodeStreamMessage(stream, message)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode entry %s: %w", message.ID, err)
+		}
+		result = append(result, msg)
+	}
+
+	return result, nil
+}
+
+// decodeStreamMessage decodes the protobuf live data of a stream entry, upgraded to the
+// current schema version
+func decodeStreamMessage(stream string, message goredis.XMessage) (*StreamMessage, error) {
+	if format, ok := message.Values[redis.StreamFormatField].(string); ok && format != "protobuf" {
+		return nil, fmt.Errorf("entry is %s, not protobuf", format)
+	}
+
+	value, ok := message.Values[redis.StreamDataField].(string)
+	if !ok {
+		return nil, fmt.Errorf("entry has no %s field", redis.StreamDataField)
+	}
+
+	var liveData binanceProto.LiveData
+	if err := proto.Unmarshal([]byte(value), &liveData); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal protobuf data: %w", err)
+	}
+	UpgradeLiveData(&liveData)
+
+	return &StreamMessage{
+		Stream: stream,
+		ID:     message.ID,
+		Data:   &liveData,
+	}, nil
+}
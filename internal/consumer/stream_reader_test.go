@@ -0,0 +1,299 @@
+package consumer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/binance-live/internal/publisher"
+	"github.com/binance-live/internal/redis"
+	binanceProto "github.com/binance-live/proto"
+	"go.uber.org/zap"
+)
+
+// addTrades appends trades with ids to stream as the publisher does, returning their entry IDs
+func addTrades(t *testing.T, client *redis.Client, stream string, ids ...int64) []string {
+	t.Helper()
+
+	entryIDs := make([]string, len(ids))
+	for i, id := range ids {
+		entryID, err := client.AddProtobufToStream(context.Background(), stream, 100, &binanceProto.LiveData{
+			Type:    binanceProto.DataType_DATA_TYPE_TRADE,
+			Symbol:  "BTCUSDT",
+			Data:    &binanceProto.LiveData_Trade{Trade: &binanceProto.TradeData{TradeId: id}},
+			Version: publisher.SchemaVersion,
+		})
+		if err != nil {
+			t.Fatalf("failed to add to stream: %v", err)
+		}
+		entryIDs[i] = entryID
+	}
+	return entryIDs
+}
+
+// runReader runs a stream reader with cfg until done returns true for the trades handled
+// so far, then returns them. handle decides whether each trade is handled.
+func runReader(t *testing.T, client *redis.Client, cfg StreamReaderConfig, handle func(tradeID int64) error, done func(handled []int64) bool) []int64 {
+	t.Helper()
+
+	cfg.Block = 10 * time.Millisecond
+	reader, err := NewStreamReader(client, cfg, zap.NewNop())
+	if err != nil {
+		t.Fatalf("failed to create stream reader: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var (
+		mu      sync.Mutex
+		handled []int64
+	)
+	stopped := make(chan error, 1)
+	go func() {
+		stopped <- reader.Run(ctx, func(ctx context.Context, msg *StreamMessage) error {
+			tradeID := msg.Data.GetTrade().GetTradeId()
+			if err := handle(tradeID); err != nil {
+				return err
+			}
+
+			mu.Lock()
+			handled = append(handled, tradeID)
+			mu.Unlock()
+			return nil
+		})
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		mu.Lock()
+		finished := done(handled)
+		mu.Unlock()
+		if finished {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("stream reader timed out")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	cancel()
+
+	if err := <-stopped; err != nil {
+		t.Fatalf("stream reader failed: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	return handled
+}
+
+// pendingIDs returns the entries of stream pending for consumer of group among ids
+func pendingIDs(t *testing.T, client *redis.Client, stream, group, consumer string, ids []string) []string {
+	t.Helper()
+
+	counts, err := client.StreamDeliveryCounts(context.Background(), stream, group, consumer, ids)
+	if err != nil {
+		t.Fatalf("failed to read pending entries: %v", err)
+	}
+
+	var pending []string
+	for _, id := range ids {
+		if _, ok := counts[id]; ok {
+			pending = append(pending, id)
+		}
+	}
+	return pending
+}
+
+func TestStreamReaderAcksHandledEntries(t *testing.T) {
+	_, client := testRedis(t)
+	stream := publisher.StreamKey(publisher.TradeChannel("BTCUSDT"))
+	ids := addTrades(t, client, stream, 1, 2, 3)
+	if err := client.CreateStreamGroup(context.Background(), stream, "indicators", "0"); err != nil {
+		t.Fatalf("failed to create group: %v", err)
+	}
+
+	cfg := StreamReaderConfig{Streams: []string{stream}, Group: "indicators", Consumer: "a", StartID: "0"}
+	handled := runReader(t, client, cfg,
+		func(tradeID int64) error {
+			if tradeID == 2 {
+				return errors.New("not now")
+			}
+			return nil
+		},
+		func(handled []int64) bool {
+			return len(handled) == 2 && len(pendingIDs(t, client, stream, "indicators", "a", ids)) == 1
+		},
+	)
+
+	if fmt.Sprint(handled) != "[1 3]" {
+		t.Errorf("got trades %v handled, want [1 3]", handled)
+	}
+	if pending := pendingIDs(t, client, stream, "indicators", "a", ids); fmt.Sprint(pending) != fmt.Sprint(ids[1:2]) {
+		t.Errorf("got entries %v pending, want the failed one %s", pending, ids[1])
+	}
+
+	// The failed entry is retried by the next run; new entries are read after it
+	ids = append(ids, addTrades(t, client, stream, 4)...)
+	handled = runReader(t, client, cfg,
+		func(tradeID int64) error { return nil },
+		func(handled []int64) bool {
+			return len(handled) == 2 && len(pendingIDs(t, client, stream, "indicators", "a", ids)) == 0
+		},
+	)
+	if fmt.Sprint(handled) != "[2 4]" {
+		t.Errorf("got trades %v handled after a restart, want [2 4]", handled)
+	}
+}
+
+func TestStreamReaderClaimsIdleEntries(t *testing.T) {
+	m, client := testRedis(t)
+	stream := publisher.StreamKey(publisher.TradeChannel("BTCUSDT"))
+	if err := client.CreateStreamGroup(context.Background(), stream, "indicators", "0"); err != nil {
+		t.Fatalf("failed to create group: %v", err)
+	}
+	ids := addTrades(t, client, stream, 1, 2)
+
+	// Consumer a reads the entries, then crashes without acknowledging them
+	if _, err := client.ReadStreamGroup(context.Background(), "indicators", "a", []string{stream}, ">", 10, 0); err != nil {
+		t.Fatalf("failed to read stream group: %v", err)
+	}
+	m.SetTime(time.Now().Add(2 * time.Minute))
+
+	cfg := StreamReaderConfig{Streams: []string{stream}, Group: "indicators", Consumer: "b", MinIdle: time.Minute}
+	handled := runReader(t, client, cfg,
+		func(tradeID int64) error { return nil },
+		func(handled []int64) bool {
+			return len(handled) == 2 && len(pendingIDs(t, client, stream, "indicators", "b", ids)) == 0
+		},
+	)
+
+	if fmt.Sprint(handled) != "[1 2]" {
+		t.Errorf("got trades %v claimed, want [1 2]", handled)
+	}
+	for _, consumer := range []string{"a", "b"} {
+		if pending := pendingIDs(t, client, stream, "indicators", consumer, ids); len(pending) != 0 {
+			t.Errorf("got entries %v pending for %s, want none", pending, consumer)
+		}
+	}
+}
+
+func TestStreamReaderMaxDeliveries(t *testing.T) {
+	_, client := testRedis(t)
+	stream := publisher.StreamKey(publisher.TradeChannel("BTCUSDT"))
+	ids := addTrades(t, client, stream, 1)
+
+	cfg := StreamReaderConfig{
+		Streams:          []string{stream},
+		Group:            "indicators",
+		Consumer:         "a",
+		StartID:          "0",
+		MinIdle:          time.Millisecond,
+		MaxDeliveries:    2,
+		DeadLetterStream: "binance:stream:dead",
+	}
+	var (
+		attempts int
+		dead     []*StreamMessage
+		err      error
+	)
+	runReader(t, client, cfg,
+		func(tradeID int64) error {
+			attempts++
+			return errors.New("always failing")
+		},
+		func([]int64) bool {
+			dead, err = ReadStream(context.Background(), client, cfg.DeadLetterStream, "0", 10, 0)
+			if err != nil {
+				t.Fatalf("failed to read dead letter stream: %v", err)
+			}
+			return len(dead) > 0
+		},
+	)
+
+	if len(dead) != 1 || dead[0].Data.GetTrade().GetTradeId() != 1 {
+		t.Fatalf("got dead letters %v, want trade 1", dead)
+	}
+	if attempts != 2 {
+		t.Errorf("got %d attempts, want 2", attempts)
+	}
+	if pending := pendingIDs(t, client, stream, "indicators", "a", ids); len(pending) != 0 {
+		t.Errorf("got entries %v pending, want none", pending)
+	}
+}
+
+func TestStreamReaderDeadLetterSource(t *testing.T) {
+	_, client := testRedis(t)
+	stream := publisher.StreamKey(publisher.TradeChannel("BTCUSDT"))
+	ids := addTrades(t, client, stream, 1)
+
+	reader, err := NewStreamReader(client, StreamReaderConfig{
+		Streams:          []string{stream},
+		Group:            "indicators",
+		Consumer:         "a",
+		DeadLetterStream: "binance:stream:dead",
+	}, zap.NewNop())
+	if err != nil {
+		t.Fatalf("failed to create stream reader: %v", err)
+	}
+
+	messages, err := client.ReadStream(context.Background(), stream, "0", 1, 0)
+	if err != nil || len(messages) != 1 {
+		t.Fatalf("failed to read stream: %v", err)
+	}
+	if err := reader.giveUp(context.Background(), stream, messages[0], 6); err != nil {
+		t.Fatalf("failed to give up on entry: %v", err)
+	}
+
+	dead, err := client.ReadStream(context.Background(), "binance:stream:dead", "0", 10, 0)
+	if err != nil || len(dead) != 1 {
+		t.Fatalf("got dead letters %v (%v), want one", dead, err)
+	}
+	if dead[0].Values[DeadLetterStreamField] != stream || dead[0].Values[DeadLetterIDField] != ids[0] {
+		t.Errorf("got dead letter fields %v, want the source stream and ID", dead[0].Values)
+	}
+}
+
+func TestReadStream(t *testing.T) {
+	_, client := testRedis(t)
+	stream := publisher.StreamKey(publisher.TradeChannel("BTCUSDT"))
+	ids := addTrades(t, client, stream, 1, 2, 3)
+
+	var got []int64
+	afterID := "0"
+	for {
+		messages, err := ReadStream(context.Background(), client, stream, afterID, 2, 0)
+		if err != nil {
+			t.Fatalf("failed to read stream: %v", err)
+		}
+		if len(messages) == 0 {
+			break
+		}
+		for _, msg := range messages {
+			got = append(got, msg.Data.GetTrade().GetTradeId())
+		}
+		afterID = messages[len(messages)-1].ID
+	}
+
+	if fmt.Sprint(got) != "[1 2 3]" {
+		t.Errorf("got trades %v, want [1 2 3]", got)
+	}
+	if afterID != ids[2] {
+		t.Errorf("got last ID %s, want %s", afterID, ids[2])
+	}
+
+	// JSON entries are not decoded
+	err := client.Pipelined(context.Background(), func(b *redis.Batch) {
+		b.AddToStream(stream, 100, map[string]interface{}{redis.StreamDataField: "{}", redis.StreamFormatField: "json"})
+	})
+	if err != nil {
+		t.Fatalf("failed to add to stream: %v", err)
+	}
+	if _, err := ReadStream(context.Background(), client, stream, afterID, 10, 0); err == nil {
+		t.Error("got no error for a JSON entry, want one")
+	}
+}
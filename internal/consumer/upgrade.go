@@ -0,0 +1,26 @@
+package consumer
+
+import (
+	"github.com/binance-live/internal/publisher"
+	binanceProto "github.com/binance-live/proto"
+)
+
+// UpgradeLiveData converts a message published with an older schema version to the
+// current one, publisher.SchemaVersion, so consumers handle every version alike:
+//
+//	0, 1: kline open and close times converted from seconds to milliseconds
+//
+// Messages without a version were published before versioning and are read as version 1.
+// Current messages are left as they are, so upgrading twice changes nothing.
+func UpgradeLiveData(liveData *binanceProto.LiveData) {
+	if liveData.Version >= publisher.SchemaVersion {
+		return
+	}
+
+	if kline := liveData.GetKline(); kline != nil && liveData.Version < 2 {
+		kline.OpenTime *= 1000
+		kline.CloseTime *= 1000
+	}
+
+	liveData.Version = publisher.SchemaVersion
+}
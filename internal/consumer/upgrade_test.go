@@ -0,0 +1,93 @@
+package consumer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/binance-live/internal/models"
+	"github.com/binance-live/internal/publisher"
+	binanceProto "github.com/binance-live/proto"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+)
+
+// The golden files hold the 2024-01-01T00:00Z BTCUSDT 1m kline as published by each
+// schema version: v0 encoded with the proto/binance.pb.go of the first release, before
+// versioning, and v1 and v2 with the current one. Seconds-based close times lose their
+// milliseconds.
+func TestUpgradeLiveDataGolden(t *testing.T) {
+	tests := []struct {
+		file      string
+		openTime  int64
+		closeTime int64
+		isClosed  bool
+	}{
+		{"kline-v0.bin", 1704067200000, 1704067259000, false},
+		{"kline-v1.bin", 1704067200000, 1704067259000, false},
+		{"kline-v2.bin", 1704067200000, 1704067259999, true},
+	}
+
+	c := NewProtobufConsumer(zap.NewNop())
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join("testdata", "upgrade", tt.file))
+			if err != nil {
+				t.Fatalf("failed to read golden file: %v", err)
+			}
+
+			liveData, err := c.ConsumeLiveData(context.Background(), data)
+			if err != nil {
+				t.Fatalf("failed to consume live data: %v", err)
+			}
+			kline, err := c.ConsumeKlineData(context.Background(), liveData)
+			if err != nil {
+				t.Fatalf("failed to consume kline: %v", err)
+			}
+
+			if liveData.Version != publisher.SchemaVersion {
+				t.Errorf("got version %d, want %d", liveData.Version, publisher.SchemaVersion)
+			}
+			if kline.OpenTime != tt.openTime || kline.CloseTime != tt.closeTime {
+				t.Errorf("got kline [%d, %d], want [%d, %d]", kline.OpenTime, kline.CloseTime, tt.openTime, tt.closeTime)
+			}
+			if kline.IsClosed != tt.isClosed || kline.Interval != "1m" || kline.TradesCount != 1327 {
+				t.Errorf("got %v, want the 1m kline of 1327 trades", kline)
+			}
+			if liveData.Timestamp != tt.openTime {
+				t.Errorf("got timestamp %d, want %d", liveData.Timestamp, tt.openTime)
+			}
+
+			// Upgrading again changes nothing
+			upgraded := proto.Clone(liveData).(*binanceProto.LiveData)
+			UpgradeLiveData(upgraded)
+			if !proto.Equal(upgraded, liveData) {
+				t.Errorf("upgrading twice gave %v, want %v", upgraded, liveData)
+			}
+		})
+	}
+}
+
+func TestUpgradeLiveDataCurrentConversions(t *testing.T) {
+	kline := &models.Kline{Symbol: "BTCUSDT", Interval: "1m", OpenTime: 1704067200000, CloseTime: 1704067259999}
+	liveData := publisher.KlineLiveData(kline)
+
+	// API responses are converted like published messages and carry the version
+	UpgradeLiveData(liveData)
+	if got := liveData.GetKline(); got.OpenTime != kline.OpenTime || got.CloseTime != kline.CloseTime {
+		t.Errorf("got kline [%d, %d], want [%d, %d]", got.OpenTime, got.CloseTime, kline.OpenTime, kline.CloseTime)
+	}
+
+	// Other data types have no times to convert
+	ticker := &binanceProto.LiveData{
+		Type:      binanceProto.DataType_DATA_TYPE_TICKER,
+		Symbol:    "BTCUSDT",
+		Timestamp: 1704067200000,
+		Data:      &binanceProto.LiveData_Ticker{Ticker: &binanceProto.TickerData{Price: 42000}},
+	}
+	UpgradeLiveData(ticker)
+	if ticker.Timestamp != 1704067200000 || ticker.Version != publisher.SchemaVersion {
+		t.Errorf("got timestamp %d and version %d, want 1704067200000 and %d", ticker.Timestamp, ticker.Version, publisher.SchemaVersion)
+	}
+}
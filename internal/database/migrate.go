@@ -0,0 +1,520 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/binance-live/internal/db"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+// migrationLockID is the advisory lock key held while migrations run, so concurrent
+// CLI and server processes apply each migration once
+const migrationLockID int64 = 0x62696e616e6365 // "binance"
+
+// migrationFilePattern matches <version>_<name>.up.sql and <version>_<name>.down.sql
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// noTransactionDirective on the first line of a migration file runs its statements one
+// by one outside a transaction, so procedures in it can commit as they go
+const noTransactionDirective = "-- migrate:no-transaction"
+
+// schemaMigrationsSQL creates the table recording applied migrations
+const schemaMigrationsSQL = `
+CREATE TABLE IF NOT EXISTS schema_migrations (
+    version BIGINT PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    applied_at BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW()) * 1000
+)`
+
+// Migration is one versioned schema change. A file starting with
+// "-- migrate:no-transaction" runs statement by statement in autocommit mode and is
+// recorded in schema_migrations only once all statements succeed, so it must be safe
+// to run again after a failure.
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string // Empty when the migration cannot be reverted
+}
+
+// MigrationStatus reports whether a migration has been applied
+type MigrationStatus struct {
+	Version   int64
+	Name      string
+	AppliedAt int64 // Unix timestamp in milliseconds, 0 when pending
+	Missing   bool  // Applied, but no longer part of the embedded migrations
+}
+
+// Migrator applies versioned migrations and records them in schema_migrations
+type Migrator struct {
+	db         *Database
+	migrations []Migration
+}
+
+// NewMigrator loads the migrations of fsys, ordered by version
+func NewMigrator(db *Database, fsys fs.FS) (*Migrator, error) {
+
+	migrations, err := LoadMigrations(fsys)
+	if err != nil {
+
+		return nil, err
+	}
+
+	return &Migrator{
+		db:         db,
+		migrations: migrations,
+	}, nil
+}
+
+// LoadMigrations reads the migration files at the root of fsys, ordered by version
+func LoadMigrations(fsys fs.FS) ([]Migration, error) {
+
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+
+		return nil, fmt.Errorf("failed to list migrations: %w", err)
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+
+		if entry.IsDir() || path.Ext(entry.Name()) != ".sql" {
+			continue
+		}
+
+		match := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+
+			return nil, fmt.Errorf("invalid migration file name %q", entry.Name())
+		}
+
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+
+			return nil, fmt.Errorf("invalid migration version in %q: %w", entry.Name(), err)
+		}
+
+		body, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+
+			return nil, fmt.Errorf("failed to read migration %s: %w", entry.Name(), err)
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		}
+		if migration.Name != match[2] {
+
+			return nil, fmt.Errorf("migration %d has files with different names", version)
+		}
+
+		if match[3] == "up" {
+			migration.Up = string(body)
+		} else {
+			migration.Down = string(body)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+
+		if migration.Up == "" {
+
+			return nil, fmt.Errorf("migration %d_%s has no up file", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// Up applies pending migrations in version order, at most steps of them when steps > 0.
+// It returns the number of migrations applied.
+func (m *Migrator) Up(ctx context.Context, steps int) (int, error) {
+
+	applied := 0
+	err := m.withLock(ctx, func(conn *pgxpool.Conn) error {
+
+		done, err := appliedMigrations(ctx, conn)
+		if err != nil {
+
+			return err
+		}
+
+		for _, migration := range m.migrations {
+
+			if _, ok := done[migration.Version]; ok {
+				continue
+			}
+			if steps > 0 && applied >= steps {
+				break
+			}
+
+			if err := m.run(ctx, conn, migration, true); err != nil {
+
+				return err
+			}
+			applied++
+		}
+
+		return nil
+	})
+
+	return applied, err
+}
+
+// Down reverts the newest applied migrations, steps of them (at least one).
+// It returns the number of migrations reverted.
+func (m *Migrator) Down(ctx context.Context, steps int) (int, error) {
+
+	if steps <= 0 {
+		steps = 1
+	}
+
+	reverted := 0
+	err := m.withLock(ctx, func(conn *pgxpool.Conn) error {
+
+		done, err := appliedMigrations(ctx, conn)
+		if err != nil {
+
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0 && reverted < steps; i-- {
+
+			migration := m.migrations[i]
+			if _, ok := done[migration.Version]; !ok {
+				continue
+			}
+
+			if migration.Down == "" {
+
+				return fmt.Errorf("migration %d_%s cannot be reverted: no down file", migration.Version, migration.Name)
+			}
+
+			if err := m.run(ctx, conn, migration, false); err != nil {
+
+				return err
+			}
+			reverted++
+		}
+
+		return nil
+	})
+
+	return reverted, err
+}
+
+// Status lists every embedded migration with the time it was applied, followed by
+// applied versions that are no longer embedded
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+
+	var statuses []MigrationStatus
+	err := m.withLock(ctx, func(conn *pgxpool.Conn) error {
+
+		done, err := appliedMigrations(ctx, conn)
+		if err != nil {
+
+			return err
+		}
+
+		for _, migration := range m.migrations {
+
+			statuses = append(statuses, MigrationStatus{
+				Version:   migration.Version,
+				Name:      migration.Name,
+				AppliedAt: done[migration.Version].appliedAt,
+			})
+			delete(done, migration.Version)
+		}
+
+		for version, row := range done {
+
+			statuses = append(statuses, MigrationStatus{
+				Version:   version,
+				Name:      row.name,
+				AppliedAt: row.appliedAt,
+				Missing:   true,
+			})
+		}
+
+		return nil
+	})
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Version < statuses[j].Version
+	})
+
+	return statuses, err
+}
+
+// withLock runs fn on a dedicated connection holding the migration advisory lock,
+// after making sure schema_migrations exists
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *pgxpool.Conn) error) error {
+
+	conn, err := m.db.Pool.Acquire(ctx)
+	if err != nil {
+
+		return fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, `SELECT pg_advisory_lock($1)`, migrationLockID); err != nil {
+
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	defer func() {
+
+		// Unlock even when ctx was cancelled, the connection returns to the pool
+		if _, err := conn.Exec(context.WithoutCancel(ctx), `SELECT pg_advisory_unlock($1)`, migrationLockID); err != nil {
+			m.db.logger.Warn("Failed to release migration lock", zap.Error(err))
+		}
+	}()
+
+	if _, err := conn.Exec(ctx, schemaMigrationsSQL); err != nil {
+
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	return fn(conn)
+}
+
+// run applies or reverts one migration in a transaction together with its
+// schema_migrations row, or statement by statement for no-transaction migrations
+func (m *Migrator) run(ctx context.Context, conn *pgxpool.Conn, migration Migration, up bool) error {
+
+	direction, body := "up", migration.Up
+	if !up {
+		direction, body = "down", migration.Down
+	}
+
+	m.db.logger.Info("Running migration",
+		zap.Int64("version", migration.Version),
+		zap.String("name", migration.Name),
+		zap.String("direction", direction),
+	)
+
+	var err error
+	if isNoTransaction(body) {
+		err = runStatements(ctx, conn, body)
+		if err == nil {
+			err = recordMigration(ctx, conn, migration, up)
+		}
+	} else {
+		err = pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+
+			if _, err := tx.Exec(ctx, body); err != nil {
+
+				return err
+			}
+
+			return recordMigration(ctx, tx, migration, up)
+		})
+	}
+	if err != nil {
+
+		return fmt.Errorf("migration %d_%s %s failed: %w", migration.Version, migration.Name, direction, err)
+	}
+
+	return nil
+}
+
+// recordMigration adds the schema_migrations row of an applied migration, or deletes it
+// once the migration was reverted
+func recordMigration(ctx context.Context, conn db.DBTX, migration Migration, up bool) error {
+
+	if up {
+		_, err := conn.Exec(ctx,
+			`INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`,
+			migration.Version, migration.Name,
+		)
+		return err
+	}
+
+	_, err := conn.Exec(ctx, `DELETE FROM schema_migrations WHERE version = $1`, migration.Version)
+	return err
+}
+
+// isNoTransaction reports whether a migration body starts with the no-transaction directive
+func isNoTransaction(body string) bool {
+
+	firstLine, _, _ := strings.Cut(body, "\n")
+	return strings.TrimSpace(firstLine) == noTransactionDirective
+}
+
+// runStatements executes the statements of body one by one. Sent as one query string they
+// would run in a single implicit transaction, where procedures cannot commit.
+func runStatements(ctx context.Context, conn *pgxpool.Conn, body string) error {
+
+	for _, statement := range splitStatements(body) {
+
+		if _, err := conn.Exec(ctx, statement); err != nil {
+
+			return err
+		}
+	}
+
+	return nil
+}
+
+// splitStatements splits SQL at the semicolons ending its statements, skipping those in
+// quoted strings and identifiers, dollar-quoted bodies and comments. Statements holding
+// only comments are dropped, comments leading a statement stay with it.
+func splitStatements(sql string) []string {
+
+	var (
+		statements []string
+		start      int
+		hasCode    bool
+	)
+
+	for i := 0; i < len(sql); {
+
+		switch {
+		case strings.HasPrefix(sql[i:], "--"):
+			end := strings.IndexByte(sql[i:], '\n')
+			if end < 0 {
+				end = len(sql) - i
+			}
+			i += end
+
+		case strings.HasPrefix(sql[i:], "/*"):
+			// Block comments nest in PostgreSQL
+			depth := 0
+			for i < len(sql) {
+				if strings.HasPrefix(sql[i:], "/*") {
+					depth++
+					i += 2
+				} else if strings.HasPrefix(sql[i:], "*/") {
+					depth--
+					i += 2
+					if depth == 0 {
+						break
+					}
+				} else {
+					i++
+				}
+			}
+
+		case sql[i] == '\'' || sql[i] == '"':
+			// A doubled quote inside is an escaped quote and reads as two quoted runs
+			end := strings.IndexByte(sql[i+1:], sql[i])
+			if end < 0 {
+				end = len(sql) - i - 1
+			}
+			i += end + 2
+			hasCode = true
+
+		case sql[i] == '$':
+			tag := dollarQuoteTag(sql[i:])
+			if tag == "" {
+				i++
+				hasCode = true
+				continue
+			}
+			end := strings.Index(sql[i+len(tag):], tag)
+			if end < 0 {
+				end = len(sql) - i - len(tag)
+			} else {
+				end += len(tag)
+			}
+			i += len(tag) + end
+			hasCode = true
+
+		case sql[i] == ';':
+			if hasCode {
+				statements = append(statements, strings.TrimSpace(sql[start:i]))
+			}
+			i++
+			start, hasCode = i, false
+
+		default:
+			if !isSpace(sql[i]) {
+				hasCode = true
+			}
+			i++
+		}
+	}
+
+	if hasCode {
+		statements = append(statements, strings.TrimSpace(sql[start:]))
+	}
+
+	return statements
+}
+
+// dollarQuoteTag returns the $tag$ opening a dollar-quoted string at the start of s, or ""
+func dollarQuoteTag(s string) string {
+
+	for i := 1; i < len(s); i++ {
+
+		c := s[i]
+		switch {
+		case c == '$':
+			return s[:i+1]
+		case c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || i > 1 && c >= '0' && c <= '9':
+		default:
+			return ""
+		}
+	}
+
+	return ""
+}
+
+// isSpace reports whether c is SQL whitespace
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
+
+// appliedMigration is a schema_migrations row
+type appliedMigration struct {
+	name      string
+	appliedAt int64
+}
+
+// appliedMigrations reads schema_migrations by version
+func appliedMigrations(ctx context.Context, conn *pgxpool.Conn) (map[int64]appliedMigration, error) {
+
+	rows, err := conn.Query(ctx, `SELECT version, name, applied_at FROM schema_migrations`)
+	if err != nil {
+
+		return nil, fmt.Errorf("failed to read schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int64]appliedMigration)
+	for rows.Next() {
+
+		var (
+			version int64
+			row     appliedMigration
+		)
+		if err := rows.Scan(&version, &row.name, &row.appliedAt); err != nil {
+
+			return nil, fmt.Errorf("failed to scan schema_migrations: %w", err)
+		}
+		applied[version] = row
+	}
+
+	if err := rows.Err(); err != nil {
+
+		return nil, fmt.Errorf("failed to read schema_migrations: %w", err)
+	}
+
+	return applied, nil
+}
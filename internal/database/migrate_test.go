@@ -0,0 +1,116 @@
+package database
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/binance-live/sql/migrations"
+)
+
+func TestSplitStatements(t *testing.T) {
+	tests := []struct {
+		name string
+		sql  string
+		want []string
+	}{
+		{
+			name: "statements",
+			sql:  "CREATE TABLE a (id INT);\nDROP TABLE b;\n",
+			want: []string{"CREATE TABLE a (id INT)", "DROP TABLE b"},
+		},
+		{
+			name: "no trailing semicolon",
+			sql:  "SELECT 1;\nSELECT 2",
+			want: []string{"SELECT 1", "SELECT 2"},
+		},
+		{
+			name: "comments",
+			sql:  "-- header; not a statement\nSELECT 1; -- trailing;\n/* block; /* nested; */ still; */\n",
+			want: []string{"-- header; not a statement\nSELECT 1"},
+		},
+		{
+			name: "quoted",
+			sql:  "SELECT 'a;b', 'it''s;', \"odd;name\" FROM t;SELECT 2;",
+			want: []string{"SELECT 'a;b', 'it''s;', \"odd;name\" FROM t", "SELECT 2"},
+		},
+		{
+			name: "dollar quoted",
+			sql:  "DO $$ BEGIN PERFORM 1; END $$;\nCREATE FUNCTION f() RETURNS INT LANGUAGE sql AS $body$ SELECT 1; $body$;",
+			want: []string{
+				"DO $$ BEGIN PERFORM 1; END $$",
+				"CREATE FUNCTION f() RETURNS INT LANGUAGE sql AS $body$ SELECT 1; $body$",
+			},
+		},
+		{
+			name: "positional parameter",
+			sql:  "PREPARE p AS SELECT $1; EXECUTE p(1);",
+			want: []string{"PREPARE p AS SELECT $1", "EXECUTE p(1)"},
+		},
+		{
+			name: "empty",
+			sql:  "-- nothing\n;\n",
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := splitStatements(tt.sql); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitStatements() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsNoTransaction(t *testing.T) {
+	tests := []struct {
+		body string
+		want bool
+	}{
+		{"-- migrate:no-transaction\nCALL p();", true},
+		{"-- migrate:no-transaction  \r\nCALL p();", true},
+		{"-- Create klines\n-- migrate:no-transaction\n", false},
+		{"CREATE TABLE a (id INT);", false},
+	}
+
+	for _, tt := range tests {
+		if got := isNoTransaction(tt.body); got != tt.want {
+			t.Errorf("isNoTransaction(%q) = %v, want %v", tt.body, got, tt.want)
+		}
+	}
+}
+
+func TestEmbeddedNoTransactionMigrations(t *testing.T) {
+	all, err := LoadMigrations(migrations.FS)
+	if err != nil {
+		t.Fatalf("failed to load migrations: %v", err)
+	}
+
+	for _, migration := range all {
+		if migration.Version != 10 {
+			continue
+		}
+
+		for direction, body := range map[string]string{"up": migration.Up, "down": migration.Down} {
+			if !isNoTransaction(body) {
+				t.Fatalf("migration %d %s runs in a transaction", migration.Version, direction)
+			}
+
+			// The procedure, its call and its removal
+			statements := splitStatements(body)
+			if len(statements) != 3 {
+				t.Fatalf("migration %d %s has %d statements, want 3", migration.Version, direction, len(statements))
+			}
+			for i, prefix := range []string{"CREATE OR REPLACE PROCEDURE", "CALL", "DROP PROCEDURE"} {
+				if !strings.Contains(statements[i], prefix) {
+					t.Errorf("migration %d %s statement %d = %.60q, want %s", migration.Version, direction, i, statements[i], prefix)
+				}
+			}
+		}
+
+		return
+	}
+
+	t.Fatal("migration 10 not found")
+}
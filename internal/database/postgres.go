@@ -71,20 +71,6 @@ func (db *Database) Close() {
 	db.logger.Info("Database connection closed")
 }
 
-// RunMigrations executes the database migrations
-func (db *Database) RunMigrations(ctx context.Context, migrationSQL string) error {
-
-	db.logger.Info("Running database migrations")
-
-	_, err := db.Pool.Exec(ctx, migrationSQL)
-	if err != nil {
-		return fmt.Errorf("failed to run migrations: %w", err)
-	}
-
-	db.logger.Info("Database migrations completed successfully")
-	return nil
-}
-
 // HealthCheck checks if the database is accessible
 func (db *Database) HealthCheck(ctx context.Context) error {
 	
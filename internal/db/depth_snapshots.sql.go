@@ -9,14 +9,17 @@ import (
 	"context"
 )
 
-const DeleteOldDepthSnapshots = `-- name: DeleteOldDepthSnapshots :exec
+const DeleteOldDepthSnapshots = `-- name: DeleteOldDepthSnapshots :execrows
 DELETE FROM depth_snapshots 
 WHERE timestamp < $1
 `
 
-func (q *Queries) DeleteOldDepthSnapshots(ctx context.Context, timestamp int64) error {
-	_, err := q.db.Exec(ctx, DeleteOldDepthSnapshots, timestamp)
-	return err
+func (q *Queries) DeleteOldDepthSnapshots(ctx context.Context, timestamp int64) (int64, error) {
+	result, err := q.db.Exec(ctx, DeleteOldDepthSnapshots, timestamp)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
 }
 
 const GetDepthSnapshotsByTimeRange = `-- name: GetDepthSnapshotsByTimeRange :many
@@ -9,6 +9,32 @@ import (
 	"context"
 )
 
+const CountKlinesByTimeRange = `-- name: CountKlinesByTimeRange :one
+SELECT COUNT(*)
+FROM klines
+WHERE symbol = $1 AND interval = $2
+  AND open_time >= $3 AND open_time < $4
+`
+
+type CountKlinesByTimeRangeParams struct {
+	Symbol     string `db:"symbol" json:"symbol"`
+	Interval   string `db:"interval" json:"interval"`
+	OpenTime   int64  `db:"open_time" json:"open_time"`
+	OpenTime_2 int64  `db:"open_time_2" json:"open_time_2"`
+}
+
+func (q *Queries) CountKlinesByTimeRange(ctx context.Context, arg CountKlinesByTimeRangeParams) (int64, error) {
+	row := q.db.QueryRow(ctx, CountKlinesByTimeRange,
+		arg.Symbol,
+		arg.Interval,
+		arg.OpenTime,
+		arg.OpenTime_2,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const DeleteOldKlines = `-- name: DeleteOldKlines :exec
 DELETE FROM klines 
 WHERE open_time < $1
@@ -19,6 +45,81 @@ func (q *Queries) DeleteOldKlines(ctx context.Context, openTime int64) error {
 	return err
 }
 
+const DeleteOldKlinesByInterval = `-- name: DeleteOldKlinesByInterval :execrows
+DELETE FROM klines
+WHERE interval = $1 AND open_time < $2
+`
+
+type DeleteOldKlinesByIntervalParams struct {
+	Interval string `db:"interval" json:"interval"`
+	OpenTime int64  `db:"open_time" json:"open_time"`
+}
+
+func (q *Queries) DeleteOldKlinesByInterval(ctx context.Context, arg DeleteOldKlinesByIntervalParams) (int64, error) {
+	result, err := q.db.Exec(ctx, DeleteOldKlinesByInterval, arg.Interval, arg.OpenTime)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const GetKlinesBySymbols = `-- name: GetKlinesBySymbols :many
+SELECT symbol, interval, open_time, close_time, open_price, high_price,
+       low_price, close_price, volume, quote_volume, trades_count,
+       taker_buy_volume, taker_buy_quote_volume, created_at
+FROM klines
+WHERE symbol = ANY($1::text[]) AND interval = $2
+  AND open_time >= $3 AND open_time < $4
+ORDER BY symbol ASC, open_time ASC
+`
+
+type GetKlinesBySymbolsParams struct {
+	Symbols   []string `db:"symbols" json:"symbols"`
+	Interval  string   `db:"interval" json:"interval"`
+	StartTime int64    `db:"start_time" json:"start_time"`
+	EndTime   int64    `db:"end_time" json:"end_time"`
+}
+
+func (q *Queries) GetKlinesBySymbols(ctx context.Context, arg GetKlinesBySymbolsParams) ([]Kline, error) {
+	rows, err := q.db.Query(ctx, GetKlinesBySymbols,
+		arg.Symbols,
+		arg.Interval,
+		arg.StartTime,
+		arg.EndTime,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Kline{}
+	for rows.Next() {
+		var i Kline
+		if err := rows.Scan(
+			&i.Symbol,
+			&i.Interval,
+			&i.OpenTime,
+			&i.CloseTime,
+			&i.OpenPrice,
+			&i.HighPrice,
+			&i.LowPrice,
+			&i.ClosePrice,
+			&i.Volume,
+			&i.QuoteVolume,
+			&i.TradesCount,
+			&i.TakerBuyVolume,
+			&i.TakerBuyQuoteVolume,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const GetKlinesByTimeRange = `-- name: GetKlinesByTimeRange :many
 SELECT symbol, interval, open_time, close_time, open_price, high_price,
        low_price, close_price, volume, quote_volume, trades_count,
@@ -76,6 +177,66 @@ func (q *Queries) GetKlinesByTimeRange(ctx context.Context, arg GetKlinesByTimeR
 	return items, nil
 }
 
+const GetKlinesPage = `-- name: GetKlinesPage :many
+SELECT symbol, interval, open_time, close_time, open_price, high_price,
+       low_price, close_price, volume, quote_volume, trades_count,
+       taker_buy_volume, taker_buy_quote_volume, created_at
+FROM klines
+WHERE symbol = $1 AND interval = $2
+  AND open_time >= $3 AND open_time < $4
+ORDER BY open_time ASC
+LIMIT $5
+`
+
+type GetKlinesPageParams struct {
+	Symbol     string `db:"symbol" json:"symbol"`
+	Interval   string `db:"interval" json:"interval"`
+	OpenTime   int64  `db:"open_time" json:"open_time"`
+	OpenTime_2 int64  `db:"open_time_2" json:"open_time_2"`
+	Limit      int32  `db:"limit" json:"limit"`
+}
+
+func (q *Queries) GetKlinesPage(ctx context.Context, arg GetKlinesPageParams) ([]Kline, error) {
+	rows, err := q.db.Query(ctx, GetKlinesPage,
+		arg.Symbol,
+		arg.Interval,
+		arg.OpenTime,
+		arg.OpenTime_2,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Kline{}
+	for rows.Next() {
+		var i Kline
+		if err := rows.Scan(
+			&i.Symbol,
+			&i.Interval,
+			&i.OpenTime,
+			&i.CloseTime,
+			&i.OpenPrice,
+			&i.HighPrice,
+			&i.LowPrice,
+			&i.ClosePrice,
+			&i.Volume,
+			&i.QuoteVolume,
+			&i.TradesCount,
+			&i.TakerBuyVolume,
+			&i.TakerBuyQuoteVolume,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const GetLastKline = `-- name: GetLastKline :one
 SELECT symbol, interval, open_time, close_time, open_price, high_price,
        low_price, close_price, volume, quote_volume, trades_count,
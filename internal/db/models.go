@@ -8,6 +8,40 @@ import (
 	"database/sql"
 )
 
+type BackfillJob struct {
+	ID             int64          `db:"id" json:"id"`
+	Symbol         string         `db:"symbol" json:"symbol"`
+	Interval       string         `db:"interval" json:"interval"`
+	RangeStart     int64          `db:"range_start" json:"range_start"`
+	RangeEnd       int64          `db:"range_end" json:"range_end"`
+	CursorTime     int64          `db:"cursor_time" json:"cursor_time"`
+	KlinesFetched  int64          `db:"klines_fetched" json:"klines_fetched"`
+	WindowsSkipped int32          `db:"windows_skipped" json:"windows_skipped"`
+	Status         string         `db:"status" json:"status"`
+	ErrorMessage   sql.NullString `db:"error_message" json:"error_message"`
+	CreatedAt      int64          `db:"created_at" json:"created_at"`
+	UpdatedAt      int64          `db:"updated_at" json:"updated_at"`
+}
+
+type Bar struct {
+	Symbol              string  `db:"symbol" json:"symbol"`
+	Spec                string  `db:"spec" json:"spec"`
+	OpenTime            int64   `db:"open_time" json:"open_time"`
+	CloseTime           int64   `db:"close_time" json:"close_time"`
+	OpenPrice           float64 `db:"open_price" json:"open_price"`
+	HighPrice           float64 `db:"high_price" json:"high_price"`
+	LowPrice            float64 `db:"low_price" json:"low_price"`
+	ClosePrice          float64 `db:"close_price" json:"close_price"`
+	Volume              float64 `db:"volume" json:"volume"`
+	QuoteVolume         float64 `db:"quote_volume" json:"quote_volume"`
+	TradesCount         int32   `db:"trades_count" json:"trades_count"`
+	TakerBuyVolume      float64 `db:"taker_buy_volume" json:"taker_buy_volume"`
+	TakerBuyQuoteVolume float64 `db:"taker_buy_quote_volume" json:"taker_buy_quote_volume"`
+	FirstTradeID        int64   `db:"first_trade_id" json:"first_trade_id"`
+	LastTradeID         int64   `db:"last_trade_id" json:"last_trade_id"`
+	CreatedAt           int64   `db:"created_at" json:"created_at"`
+}
+
 type DepthSnapshot struct {
 	ID           int64  `db:"id" json:"id"`
 	Symbol       string `db:"symbol" json:"symbol"`
@@ -35,6 +69,20 @@ type Kline struct {
 	CreatedAt           int64   `db:"created_at" json:"created_at"`
 }
 
+type KlineGap struct {
+	ID           int64          `db:"id" json:"id"`
+	Symbol       string         `db:"symbol" json:"symbol"`
+	Interval     string         `db:"interval" json:"interval"`
+	GapStart     int64          `db:"gap_start" json:"gap_start"`
+	GapEnd       int64          `db:"gap_end" json:"gap_end"`
+	MissingCount int32          `db:"missing_count" json:"missing_count"`
+	Status       string         `db:"status" json:"status"`
+	Attempts     int32          `db:"attempts" json:"attempts"`
+	ErrorMessage sql.NullString `db:"error_message" json:"error_message"`
+	DetectedAt   int64          `db:"detected_at" json:"detected_at"`
+	UpdatedAt    int64          `db:"updated_at" json:"updated_at"`
+}
+
 type Symbol struct {
 	ID         int32  `db:"id" json:"id"`
 	Symbol     string `db:"symbol" json:"symbol"`
@@ -46,15 +94,34 @@ type Symbol struct {
 	UpdatedAt  int64  `db:"updated_at" json:"updated_at"`
 }
 
+type SyncJob struct {
+	ID            int64          `db:"id" json:"id"`
+	Symbol        string         `db:"symbol" json:"symbol"`
+	DataType      string         `db:"data_type" json:"data_type"`
+	Interval      string         `db:"interval" json:"interval"`
+	State         string         `db:"state" json:"state"`
+	Attempts      int32          `db:"attempts" json:"attempts"`
+	MaxAttempts   int32          `db:"max_attempts" json:"max_attempts"`
+	LastError     sql.NullString `db:"last_error" json:"last_error"`
+	KlinesFetched int64          `db:"klines_fetched" json:"klines_fetched"`
+	LastDataTime  int64          `db:"last_data_time" json:"last_data_time"`
+	RunAfter      int64          `db:"run_after" json:"run_after"`
+	LockedBy      sql.NullString `db:"locked_by" json:"locked_by"`
+	LockedAt      sql.NullInt64  `db:"locked_at" json:"locked_at"`
+	CreatedAt     int64          `db:"created_at" json:"created_at"`
+	UpdatedAt     int64          `db:"updated_at" json:"updated_at"`
+}
+
 type SyncStatus struct {
 	Symbol       string         `db:"symbol" json:"symbol"`
 	DataType     string         `db:"data_type" json:"data_type"`
-	Interval     sql.NullString `db:"interval" json:"interval"`
+	Interval     string         `db:"interval" json:"interval"`
 	LastSyncTime int64          `db:"last_sync_time" json:"last_sync_time"`
 	LastDataTime int64          `db:"last_data_time" json:"last_data_time"`
 	Status       string         `db:"status" json:"status"`
 	ErrorMessage sql.NullString `db:"error_message" json:"error_message"`
 	UpdatedAt    int64          `db:"updated_at" json:"updated_at"`
+	Market       string         `db:"market" json:"market"`
 }
 
 type Ticker struct {
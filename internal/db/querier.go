@@ -6,37 +6,67 @@ package db
 
 import (
 	"context"
+	"database/sql"
 )
 
 type Querier interface {
-	DeleteOldDepthSnapshots(ctx context.Context, timestamp int64) error
+	ClaimSyncJob(ctx context.Context, lockedBy sql.NullString) (SyncJob, error)
+	ClearSyncStatusError(ctx context.Context, arg ClearSyncStatusErrorParams) error
+	CompleteSyncJob(ctx context.Context, arg CompleteSyncJobParams) (int64, error)
+	CountKlinesByTimeRange(ctx context.Context, arg CountKlinesByTimeRangeParams) (int64, error)
+	CountPendingSyncJobs(ctx context.Context) (int64, error)
+	DeleteOldBars(ctx context.Context, openTime int64) (int64, error)
+	DeleteOldDepthSnapshots(ctx context.Context, timestamp int64) (int64, error)
 	DeleteOldKlines(ctx context.Context, openTime int64) error
-	DeleteOldTickers(ctx context.Context, timestamp int64) error
-	DeleteOldTrades(ctx context.Context, timestamp int64) error
+	DeleteOldKlinesByInterval(ctx context.Context, arg DeleteOldKlinesByIntervalParams) (int64, error)
+	DeleteOldTickers(ctx context.Context, timestamp int64) (int64, error)
+	DeleteOldTrades(ctx context.Context, timestamp int64) (int64, error)
 	DeleteSymbol(ctx context.Context, symbol string) error
-	DeleteSyncStatus(ctx context.Context, arg DeleteSyncStatusParams) error
+	DeleteSyncStatuses(ctx context.Context, arg DeleteSyncStatusesParams) (int64, error)
+	EnqueueSyncJob(ctx context.Context, arg EnqueueSyncJobParams) (int64, error)
+	FailSyncJob(ctx context.Context, arg FailSyncJobParams) (string, error)
+	FindKlineGaps(ctx context.Context, arg FindKlineGapsParams) ([]FindKlineGapsRow, error)
+	FinishBackfillJob(ctx context.Context, arg FinishBackfillJobParams) error
 	GetActiveSymbols(ctx context.Context) ([]Symbol, error)
 	GetAllLatestTickers(ctx context.Context) ([]Ticker, error)
 	GetAllSymbols(ctx context.Context) ([]Symbol, error)
-	GetAllSyncStatuses(ctx context.Context) ([]SyncStatus, error)
+	GetBarsByTimeRange(ctx context.Context, arg GetBarsByTimeRangeParams) ([]Bar, error)
 	GetDepthSnapshotsByTimeRange(ctx context.Context, arg GetDepthSnapshotsByTimeRangeParams) ([]DepthSnapshot, error)
+	GetKlineGapsBySymbol(ctx context.Context, symbol string) ([]KlineGap, error)
+	GetKlinesBySymbols(ctx context.Context, arg GetKlinesBySymbolsParams) ([]Kline, error)
 	GetKlinesByTimeRange(ctx context.Context, arg GetKlinesByTimeRangeParams) ([]Kline, error)
+	GetKlinesPage(ctx context.Context, arg GetKlinesPageParams) ([]Kline, error)
 	GetLastKline(ctx context.Context, arg GetLastKlineParams) (Kline, error)
 	GetLatestDepthSnapshot(ctx context.Context, symbol string) (DepthSnapshot, error)
 	GetLatestKlines(ctx context.Context, arg GetLatestKlinesParams) ([]Kline, error)
 	GetLatestTicker(ctx context.Context, symbol string) (Ticker, error)
 	GetLatestTrades(ctx context.Context, arg GetLatestTradesParams) ([]Trade, error)
+	GetOpenKlineGaps(ctx context.Context, arg GetOpenKlineGapsParams) ([]KlineGap, error)
 	GetSymbolByName(ctx context.Context, symbol string) (Symbol, error)
 	GetSyncStatus(ctx context.Context, arg GetSyncStatusParams) (SyncStatus, error)
-	GetSyncStatusesBySymbol(ctx context.Context, symbol string) ([]SyncStatus, error)
 	GetTickersByTimeRange(ctx context.Context, arg GetTickersByTimeRangeParams) ([]Ticker, error)
 	GetTradesByTimeRange(ctx context.Context, arg GetTradesByTimeRangeParams) ([]Trade, error)
+	GetTradesPage(ctx context.Context, arg GetTradesPageParams) ([]Trade, error)
+	HeartbeatSyncJob(ctx context.Context, arg HeartbeatSyncJobParams) (int64, error)
+	InsertBar(ctx context.Context, arg InsertBarParams) error
 	InsertDepthSnapshot(ctx context.Context, arg InsertDepthSnapshotParams) (InsertDepthSnapshotRow, error)
 	InsertKline(ctx context.Context, arg InsertKlineParams) error
 	InsertTicker(ctx context.Context, arg InsertTickerParams) error
 	InsertTrade(ctx context.Context, arg InsertTradeParams) (InsertTradeRow, error)
-	UpdateLastDataTime(ctx context.Context, arg UpdateLastDataTimeParams) error
+	ListSyncJobs(ctx context.Context, arg ListSyncJobsParams) ([]SyncJob, error)
+	ListSyncStatuses(ctx context.Context, arg ListSyncStatusesParams) ([]SyncStatus, error)
+	MarkKlineGapFailed(ctx context.Context, arg MarkKlineGapFailedParams) error
+	MarkKlineGapRepaired(ctx context.Context, id int64) error
+	RecordSyncProgress(ctx context.Context, arg RecordSyncProgressParams) error
+	RequeueStaleSyncJobs(ctx context.Context, lockedAt sql.NullInt64) (int64, error)
+	ResetBackfillJob(ctx context.Context, id int64) error
+	ResetSyncStatuses(ctx context.Context, arg ResetSyncStatusesParams) (int64, error)
+	SetSyncStatusError(ctx context.Context, arg SetSyncStatusErrorParams) error
+	UpdateBackfillJobProgress(ctx context.Context, arg UpdateBackfillJobProgressParams) error
 	UpdateSymbolStatus(ctx context.Context, arg UpdateSymbolStatusParams) error
+	UpdateSyncJobProgress(ctx context.Context, arg UpdateSyncJobProgressParams) (int64, error)
+	UpsertBackfillJob(ctx context.Context, arg UpsertBackfillJobParams) (BackfillJob, error)
+	UpsertKlineGap(ctx context.Context, arg UpsertKlineGapParams) error
 	UpsertSymbol(ctx context.Context, arg UpsertSymbolParams) (UpsertSymbolRow, error)
 	UpsertSyncStatus(ctx context.Context, arg UpsertSyncStatusParams) error
 }
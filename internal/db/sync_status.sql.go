@@ -10,75 +10,82 @@ import (
 	"database/sql"
 )
 
-const DeleteSyncStatus = `-- name: DeleteSyncStatus :exec
-DELETE FROM sync_status 
-WHERE symbol = $1 AND data_type = $2 AND COALESCE(interval, '') = COALESCE($3, '')
+const ClearSyncStatusError = `-- name: ClearSyncStatusError :exec
+UPDATE sync_status
+SET status = 'active',
+    error_message = NULL,
+    last_sync_time = EXTRACT(EPOCH FROM NOW()) * 1000,
+    updated_at = EXTRACT(EPOCH FROM NOW()) * 1000
+WHERE market = $1 AND symbol = $2 AND data_type = $3 AND interval = $4
+  AND status = 'error'
 `
 
-type DeleteSyncStatusParams struct {
-	Symbol   string         `db:"symbol" json:"symbol"`
-	DataType string         `db:"data_type" json:"data_type"`
-	Interval sql.NullString `db:"interval" json:"interval"`
+type ClearSyncStatusErrorParams struct {
+	Market   string `db:"market" json:"market"`
+	Symbol   string `db:"symbol" json:"symbol"`
+	DataType string `db:"data_type" json:"data_type"`
+	Interval string `db:"interval" json:"interval"`
 }
 
-func (q *Queries) DeleteSyncStatus(ctx context.Context, arg DeleteSyncStatusParams) error {
-	_, err := q.db.Exec(ctx, DeleteSyncStatus, arg.Symbol, arg.DataType, arg.Interval)
+func (q *Queries) ClearSyncStatusError(ctx context.Context, arg ClearSyncStatusErrorParams) error {
+	_, err := q.db.Exec(ctx, ClearSyncStatusError,
+		arg.Market,
+		arg.Symbol,
+		arg.DataType,
+		arg.Interval,
+	)
 	return err
 }
 
-const GetAllSyncStatuses = `-- name: GetAllSyncStatuses :many
-SELECT s.symbol, s.data_type, s.interval, s.last_sync_time, s.last_data_time,
-       s.status, s.error_message, s.updated_at
-FROM sync_status s
-INNER JOIN symbols sym ON s.symbol = sym.symbol
-WHERE sym.is_active = true
-ORDER BY s.symbol, s.data_type, s.interval
+const DeleteSyncStatuses = `-- name: DeleteSyncStatuses :execrows
+DELETE FROM sync_status
+WHERE ($1::text = '' OR market = $1::text)
+  AND ($2::text = '' OR symbol = $2::text)
+  AND ($3::text = '' OR data_type = $3::text)
+  AND ($4::text = '' OR interval = $4::text)
 `
 
-func (q *Queries) GetAllSyncStatuses(ctx context.Context) ([]SyncStatus, error) {
-	rows, err := q.db.Query(ctx, GetAllSyncStatuses)
+type DeleteSyncStatusesParams struct {
+	Market   string `db:"market" json:"market"`
+	Symbol   string `db:"symbol" json:"symbol"`
+	DataType string `db:"data_type" json:"data_type"`
+	Interval string `db:"interval" json:"interval"`
+}
+
+func (q *Queries) DeleteSyncStatuses(ctx context.Context, arg DeleteSyncStatusesParams) (int64, error) {
+	result, err := q.db.Exec(ctx, DeleteSyncStatuses,
+		arg.Market,
+		arg.Symbol,
+		arg.DataType,
+		arg.Interval,
+	)
 	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	items := []SyncStatus{}
-	for rows.Next() {
-		var i SyncStatus
-		if err := rows.Scan(
-			&i.Symbol,
-			&i.DataType,
-			&i.Interval,
-			&i.LastSyncTime,
-			&i.LastDataTime,
-			&i.Status,
-			&i.ErrorMessage,
-			&i.UpdatedAt,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
+		return 0, err
 	}
-	return items, nil
+	return result.RowsAffected(), nil
 }
 
 const GetSyncStatus = `-- name: GetSyncStatus :one
 SELECT symbol, data_type, interval, last_sync_time, last_data_time,
-       status, error_message, updated_at
+       status, error_message, updated_at, market
 FROM sync_status
-WHERE symbol = $1 AND data_type = $2 AND COALESCE(interval, '') = COALESCE($3, '')
+WHERE market = $1 AND symbol = $2 AND data_type = $3 AND interval = $4
 `
 
 type GetSyncStatusParams struct {
-	Symbol   string         `db:"symbol" json:"symbol"`
-	DataType string         `db:"data_type" json:"data_type"`
-	Interval sql.NullString `db:"interval" json:"interval"`
+	Market   string `db:"market" json:"market"`
+	Symbol   string `db:"symbol" json:"symbol"`
+	DataType string `db:"data_type" json:"data_type"`
+	Interval string `db:"interval" json:"interval"`
 }
 
 func (q *Queries) GetSyncStatus(ctx context.Context, arg GetSyncStatusParams) (SyncStatus, error) {
-	row := q.db.QueryRow(ctx, GetSyncStatus, arg.Symbol, arg.DataType, arg.Interval)
+	row := q.db.QueryRow(ctx, GetSyncStatus,
+		arg.Market,
+		arg.Symbol,
+		arg.DataType,
+		arg.Interval,
+	)
 	var i SyncStatus
 	err := row.Scan(
 		&i.Symbol,
@@ -89,20 +96,41 @@ func (q *Queries) GetSyncStatus(ctx context.Context, arg GetSyncStatusParams) (S
 		&i.Status,
 		&i.ErrorMessage,
 		&i.UpdatedAt,
+		&i.Market,
 	)
 	return i, err
 }
 
-const GetSyncStatusesBySymbol = `-- name: GetSyncStatusesBySymbol :many
-SELECT symbol, data_type, interval, last_sync_time, last_data_time,
-       status, error_message, updated_at
-FROM sync_status
-WHERE symbol = $1
-ORDER BY data_type, interval
+const ListSyncStatuses = `-- name: ListSyncStatuses :many
+SELECT s.symbol, s.data_type, s.interval, s.last_sync_time, s.last_data_time,
+       s.status, s.error_message, s.updated_at, s.market
+FROM sync_status s
+WHERE ($1::text = '' OR s.market = $1::text)
+  AND ($2::text = '' OR s.symbol = $2::text)
+  AND ($3::text = '' OR s.data_type = $3::text)
+  AND ($4::text = '' OR s.interval = $4::text)
+  AND (NOT $5::boolean OR EXISTS (
+      SELECT 1 FROM symbols sym WHERE sym.symbol = s.symbol AND sym.is_active = true
+  ))
+ORDER BY s.market, s.symbol, s.data_type, s.interval
 `
 
-func (q *Queries) GetSyncStatusesBySymbol(ctx context.Context, symbol string) ([]SyncStatus, error) {
-	rows, err := q.db.Query(ctx, GetSyncStatusesBySymbol, symbol)
+type ListSyncStatusesParams struct {
+	Market     string `db:"market" json:"market"`
+	Symbol     string `db:"symbol" json:"symbol"`
+	DataType   string `db:"data_type" json:"data_type"`
+	Interval   string `db:"interval" json:"interval"`
+	ActiveOnly bool   `db:"active_only" json:"active_only"`
+}
+
+func (q *Queries) ListSyncStatuses(ctx context.Context, arg ListSyncStatusesParams) ([]SyncStatus, error) {
+	rows, err := q.db.Query(ctx, ListSyncStatuses,
+		arg.Market,
+		arg.Symbol,
+		arg.DataType,
+		arg.Interval,
+		arg.ActiveOnly,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -119,6 +147,7 @@ func (q *Queries) GetSyncStatusesBySymbol(ctx context.Context, symbol string) ([
 			&i.Status,
 			&i.ErrorMessage,
 			&i.UpdatedAt,
+			&i.Market,
 		); err != nil {
 			return nil, err
 		}
@@ -130,38 +159,103 @@ func (q *Queries) GetSyncStatusesBySymbol(ctx context.Context, symbol string) ([
 	return items, nil
 }
 
-const UpdateLastDataTime = `-- name: UpdateLastDataTime :exec
+const RecordSyncProgress = `-- name: RecordSyncProgress :exec
+INSERT INTO sync_status (
+    market, symbol, data_type, interval, last_sync_time, last_data_time, status
+) VALUES ($1, $2, $3, $4, EXTRACT(EPOCH FROM NOW()) * 1000, $5, 'active')
+ON CONFLICT (market, symbol, data_type, interval) DO UPDATE SET
+    last_sync_time = EXCLUDED.last_sync_time,
+    last_data_time = GREATEST(sync_status.last_data_time, EXCLUDED.last_data_time),
+    updated_at = EXTRACT(EPOCH FROM NOW()) * 1000
+`
+
+type RecordSyncProgressParams struct {
+	Market       string `db:"market" json:"market"`
+	Symbol       string `db:"symbol" json:"symbol"`
+	DataType     string `db:"data_type" json:"data_type"`
+	Interval     string `db:"interval" json:"interval"`
+	LastDataTime int64  `db:"last_data_time" json:"last_data_time"`
+}
+
+func (q *Queries) RecordSyncProgress(ctx context.Context, arg RecordSyncProgressParams) error {
+	_, err := q.db.Exec(ctx, RecordSyncProgress,
+		arg.Market,
+		arg.Symbol,
+		arg.DataType,
+		arg.Interval,
+		arg.LastDataTime,
+	)
+	return err
+}
+
+const ResetSyncStatuses = `-- name: ResetSyncStatuses :execrows
 UPDATE sync_status
-SET last_data_time = $4,
+SET last_data_time = 0,
     last_sync_time = EXTRACT(EPOCH FROM NOW()) * 1000,
     status = 'active',
     error_message = NULL,
     updated_at = EXTRACT(EPOCH FROM NOW()) * 1000
-WHERE symbol = $1 AND data_type = $2 AND COALESCE(interval, '') = COALESCE($3, '')
+WHERE ($1::text = '' OR market = $1::text)
+  AND ($2::text = '' OR symbol = $2::text)
+  AND ($3::text = '' OR data_type = $3::text)
+  AND ($4::text = '' OR interval = $4::text)
+`
+
+type ResetSyncStatusesParams struct {
+	Market   string `db:"market" json:"market"`
+	Symbol   string `db:"symbol" json:"symbol"`
+	DataType string `db:"data_type" json:"data_type"`
+	Interval string `db:"interval" json:"interval"`
+}
+
+func (q *Queries) ResetSyncStatuses(ctx context.Context, arg ResetSyncStatusesParams) (int64, error) {
+	result, err := q.db.Exec(ctx, ResetSyncStatuses,
+		arg.Market,
+		arg.Symbol,
+		arg.DataType,
+		arg.Interval,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const SetSyncStatusError = `-- name: SetSyncStatusError :exec
+INSERT INTO sync_status (
+    market, symbol, data_type, interval, last_sync_time, last_data_time, status, error_message
+) VALUES ($1, $2, $3, $4, EXTRACT(EPOCH FROM NOW()) * 1000, 0, 'error', $5)
+ON CONFLICT (market, symbol, data_type, interval) DO UPDATE SET
+    last_sync_time = EXCLUDED.last_sync_time,
+    status = 'error',
+    error_message = EXCLUDED.error_message,
+    updated_at = EXTRACT(EPOCH FROM NOW()) * 1000
 `
 
-type UpdateLastDataTimeParams struct {
+type SetSyncStatusErrorParams struct {
+	Market       string         `db:"market" json:"market"`
 	Symbol       string         `db:"symbol" json:"symbol"`
 	DataType     string         `db:"data_type" json:"data_type"`
-	Interval     sql.NullString `db:"interval" json:"interval"`
-	LastDataTime int64          `db:"last_data_time" json:"last_data_time"`
+	Interval     string         `db:"interval" json:"interval"`
+	ErrorMessage sql.NullString `db:"error_message" json:"error_message"`
 }
 
-func (q *Queries) UpdateLastDataTime(ctx context.Context, arg UpdateLastDataTimeParams) error {
-	_, err := q.db.Exec(ctx, UpdateLastDataTime,
+func (q *Queries) SetSyncStatusError(ctx context.Context, arg SetSyncStatusErrorParams) error {
+	_, err := q.db.Exec(ctx, SetSyncStatusError,
+		arg.Market,
 		arg.Symbol,
 		arg.DataType,
 		arg.Interval,
-		arg.LastDataTime,
+		arg.ErrorMessage,
 	)
 	return err
 }
 
 const UpsertSyncStatus = `-- name: UpsertSyncStatus :exec
 INSERT INTO sync_status (
-    symbol, data_type, interval, last_sync_time, last_data_time, status, error_message
-) VALUES ($1, $2, $3, $4, $5, $6, $7)
-ON CONFLICT (symbol, data_type, interval) DO UPDATE SET
+    market, symbol, data_type, interval, last_sync_time, last_data_time, status, error_message
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+ON CONFLICT (market, symbol, data_type, interval) DO UPDATE SET
     last_sync_time = EXCLUDED.last_sync_time,
     last_data_time = EXCLUDED.last_data_time,
     status = EXCLUDED.status,
@@ -170,9 +264,10 @@ ON CONFLICT (symbol, data_type, interval) DO UPDATE SET
 `
 
 type UpsertSyncStatusParams struct {
+	Market       string         `db:"market" json:"market"`
 	Symbol       string         `db:"symbol" json:"symbol"`
 	DataType     string         `db:"data_type" json:"data_type"`
-	Interval     sql.NullString `db:"interval" json:"interval"`
+	Interval     string         `db:"interval" json:"interval"`
 	LastSyncTime int64          `db:"last_sync_time" json:"last_sync_time"`
 	LastDataTime int64          `db:"last_data_time" json:"last_data_time"`
 	Status       string         `db:"status" json:"status"`
@@ -181,6 +276,7 @@ type UpsertSyncStatusParams struct {
 
 func (q *Queries) UpsertSyncStatus(ctx context.Context, arg UpsertSyncStatusParams) error {
 	_, err := q.db.Exec(ctx, UpsertSyncStatus,
+		arg.Market,
 		arg.Symbol,
 		arg.DataType,
 		arg.Interval,
@@ -10,14 +10,17 @@ import (
 	"database/sql"
 )
 
-const DeleteOldTickers = `-- name: DeleteOldTickers :exec
+const DeleteOldTickers = `-- name: DeleteOldTickers :execrows
 DELETE FROM tickers 
 WHERE timestamp < $1
 `
 
-func (q *Queries) DeleteOldTickers(ctx context.Context, timestamp int64) error {
-	_, err := q.db.Exec(ctx, DeleteOldTickers, timestamp)
-	return err
+func (q *Queries) DeleteOldTickers(ctx context.Context, timestamp int64) (int64, error) {
+	result, err := q.db.Exec(ctx, DeleteOldTickers, timestamp)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
 }
 
 const GetAllLatestTickers = `-- name: GetAllLatestTickers :many
@@ -9,14 +9,17 @@ import (
 	"context"
 )
 
-const DeleteOldTrades = `-- name: DeleteOldTrades :exec
+const DeleteOldTrades = `-- name: DeleteOldTrades :execrows
 DELETE FROM trades 
 WHERE timestamp < $1
 `
 
-func (q *Queries) DeleteOldTrades(ctx context.Context, timestamp int64) error {
-	_, err := q.db.Exec(ctx, DeleteOldTrades, timestamp)
-	return err
+func (q *Queries) DeleteOldTrades(ctx context.Context, timestamp int64) (int64, error) {
+	result, err := q.db.Exec(ctx, DeleteOldTrades, timestamp)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
 }
 
 const GetLatestTrades = `-- name: GetLatestTrades :many
@@ -106,6 +109,60 @@ func (q *Queries) GetTradesByTimeRange(ctx context.Context, arg GetTradesByTimeR
 	return items, nil
 }
 
+const GetTradesPage = `-- name: GetTradesPage :many
+SELECT id, symbol, trade_id, timestamp, price, quantity, quote_quantity, is_buyer_maker, created_at
+FROM trades
+WHERE symbol = $1
+  AND timestamp >= $2 AND timestamp < $3
+  AND (timestamp, trade_id) > ($2::BIGINT, $4::BIGINT)
+ORDER BY timestamp ASC, trade_id ASC
+LIMIT $5
+`
+
+type GetTradesPageParams struct {
+	Symbol       string `db:"symbol" json:"symbol"`
+	AfterTime    int64  `db:"after_time" json:"after_time"`
+	EndTime      int64  `db:"end_time" json:"end_time"`
+	AfterTradeID int64  `db:"after_trade_id" json:"after_trade_id"`
+	RowLimit     int32  `db:"row_limit" json:"row_limit"`
+}
+
+func (q *Queries) GetTradesPage(ctx context.Context, arg GetTradesPageParams) ([]Trade, error) {
+	rows, err := q.db.Query(ctx, GetTradesPage,
+		arg.Symbol,
+		arg.AfterTime,
+		arg.EndTime,
+		arg.AfterTradeID,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Trade{}
+	for rows.Next() {
+		var i Trade
+		if err := rows.Scan(
+			&i.ID,
+			&i.Symbol,
+			&i.TradeID,
+			&i.Timestamp,
+			&i.Price,
+			&i.Quantity,
+			&i.QuoteQuantity,
+			&i.IsBuyerMaker,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const InsertTrade = `-- name: InsertTrade :one
 INSERT INTO trades (
     symbol, trade_id, timestamp, price, quantity, quote_quantity, is_buyer_maker
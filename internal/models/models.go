@@ -28,6 +28,60 @@ type Kline struct {
 	TakerBuyVolume      float64 `db:"taker_buy_volume"`
 	TakerBuyQuoteVolume float64 `db:"taker_buy_quote_volume"`
 	CreatedAt           int64   `db:"created_at"` // Unix timestamp in milliseconds
+	IsClosed            bool    `db:"-"`          // Final update of the candle, set for live klines only
+	EventTime           int64   `db:"-"`          // Binance event time of live klines, Unix timestamp in milliseconds
+}
+
+// Bar is a candle built locally from aggregated trades. Time bars cover a fixed
+// duration; volume, tick and dollar bars close once their threshold is reached.
+type Bar struct {
+	Symbol              string  `db:"symbol"`
+	Spec                string  `db:"spec"`       // Bar spec name, e.g. 2m or vol100
+	OpenTime            int64   `db:"open_time"`  // Unix timestamp in milliseconds
+	CloseTime           int64   `db:"close_time"` // Unix timestamp in milliseconds
+	OpenPrice           float64 `db:"open_price"`
+	HighPrice           float64 `db:"high_price"`
+	LowPrice            float64 `db:"low_price"`
+	ClosePrice          float64 `db:"close_price"`
+	Volume              float64 `db:"volume"`
+	QuoteVolume         float64 `db:"quote_volume"`
+	TradesCount         int     `db:"trades_count"`
+	TakerBuyVolume      float64 `db:"taker_buy_volume"`
+	TakerBuyQuoteVolume float64 `db:"taker_buy_quote_volume"`
+	FirstTradeID        int64   `db:"first_trade_id"` // First aggregate trade ID
+	LastTradeID         int64   `db:"last_trade_id"`  // Last aggregate trade ID
+	CreatedAt           int64   `db:"created_at"`     // Unix timestamp in milliseconds
+}
+
+// KlineGap represents a range of missing klines inside the stored history
+type KlineGap struct {
+	ID           int64   `db:"id"`
+	Symbol       string  `db:"symbol"`
+	Interval     string  `db:"interval"`
+	GapStart     int64   `db:"gap_start"` // Open time of the first missing kline (inclusive)
+	GapEnd       int64   `db:"gap_end"`   // Open time of the next stored kline (exclusive)
+	MissingCount int     `db:"missing_count"`
+	Status       string  `db:"status"`
+	Attempts     int     `db:"attempts"`
+	ErrorMessage *string `db:"error_message"`
+	DetectedAt   int64   `db:"detected_at"` // Unix timestamp in milliseconds
+	UpdatedAt    int64   `db:"updated_at"`  // Unix timestamp in milliseconds
+}
+
+// BackfillJob checkpoints a historical kline backfill over a fixed date range
+type BackfillJob struct {
+	ID             int64   `db:"id"`
+	Symbol         string  `db:"symbol"`
+	Interval       string  `db:"interval"`
+	RangeStart     int64   `db:"range_start"` // Unix timestamp in milliseconds (inclusive)
+	RangeEnd       int64   `db:"range_end"`   // Unix timestamp in milliseconds (exclusive)
+	CursorTime     int64   `db:"cursor_time"` // Everything before this open time has been processed
+	KlinesFetched  int64   `db:"klines_fetched"`
+	WindowsSkipped int     `db:"windows_skipped"`
+	Status         string  `db:"status"`
+	ErrorMessage   *string `db:"error_message"`
+	CreatedAt      int64   `db:"created_at"` // Unix timestamp in milliseconds
+	UpdatedAt      int64   `db:"updated_at"` // Unix timestamp in milliseconds
 }
 
 // Ticker represents 24hr ticker price data
@@ -73,11 +127,48 @@ type Trade struct {
 	CreatedAt     int64   `db:"created_at"` // Unix timestamp in milliseconds
 }
 
-// SyncStatus tracks the synchronization status for each symbol and data type
+// MarketSpot is the Binance spot market
+const MarketSpot = "spot"
+
+// Data types of synced streams
+const (
+	DataTypeKline  = "kline"
+	DataTypeTicker = "ticker"
+	DataTypeTrade  = "trade"
+	DataTypeDepth  = "depth"
+)
+
+// StreamKey identifies one synced data stream. Interval is set for klines and empty
+// for every other data type.
+type StreamKey struct {
+	Market   string `db:"market"`
+	Symbol   string `db:"symbol"`
+	DataType string `db:"data_type"`
+	Interval string `db:"interval"`
+}
+
+// KlineStreamKey returns the key of the spot kline stream of symbol and interval
+func KlineStreamKey(symbol, interval string) StreamKey {
+	return StreamKey{Market: MarketSpot, Symbol: symbol, DataType: DataTypeKline, Interval: interval}
+}
+
+// SpotStreamKey returns the key of a spot stream without an interval
+func SpotStreamKey(symbol, dataType string) StreamKey {
+	return StreamKey{Market: MarketSpot, Symbol: symbol, DataType: dataType}
+}
+
+// String formats the key as market:symbol:data_type[:interval]
+func (k StreamKey) String() string {
+	key := k.Market + ":" + k.Symbol + ":" + k.DataType
+	if k.Interval != "" {
+		key += ":" + k.Interval
+	}
+	return key
+}
+
+// SyncStatus tracks the synchronization progress of one stream
 type SyncStatus struct {
-	Symbol       string  `db:"symbol"`
-	DataType     string  `db:"data_type"`
-	Interval     *string `db:"interval"`
+	StreamKey
 	LastSyncTime int64   `db:"last_sync_time"` // Unix timestamp in milliseconds
 	LastDataTime int64   `db:"last_data_time"` // Unix timestamp in milliseconds
 	Status       string  `db:"status"`
@@ -85,10 +176,39 @@ type SyncStatus struct {
 	UpdatedAt    int64   `db:"updated_at"` // Unix timestamp in milliseconds
 }
 
+// SyncJob is a durable unit of sync work claimed by one worker at a time
+type SyncJob struct {
+	ID            int64   `db:"id"`
+	Symbol        string  `db:"symbol"`
+	DataType      string  `db:"data_type"`
+	Interval      string  `db:"interval"`
+	State         string  `db:"state"` // pending, running, completed, failed
+	Attempts      int     `db:"attempts"`
+	MaxAttempts   int     `db:"max_attempts"`
+	LastError     *string `db:"last_error"`
+	KlinesFetched int64   `db:"klines_fetched"`
+	LastDataTime  int64   `db:"last_data_time"` // Open time of the newest stored kline
+	RunAfter      int64   `db:"run_after"`      // Unix timestamp in milliseconds
+	LockedBy      *string `db:"locked_by"`
+	LockedAt      *int64  `db:"locked_at"` // Unix timestamp in milliseconds
+	CreatedAt     int64   `db:"created_at"`
+	UpdatedAt     int64   `db:"updated_at"`
+}
+
 // LiveData represents real-time data to be published to Redis
 type LiveData struct {
-	Type      string                 `json:"type"` // "kline", "ticker", "depth", "trade"
+	Format    string                 `json:"format"`  // Envelope header: always "json"
+	Version   int                    `json:"version"` // Envelope header: schema version
+	Type      string                 `json:"type"`    // "kline", "ticker", "depth", "trade"
 	Symbol    string                 `json:"symbol"`
 	Timestamp int64                  `json:"timestamp"` // Unix timestamp in milliseconds
 	Data      map[string]interface{} `json:"data"`
+	Metadata  Metadata               `json:"metadata"`
+}
+
+// Metadata is the delivery metadata of a live data message
+type Metadata struct {
+	Sequence    uint64 `json:"sequence"`     // Per channel and publisher, from 1 when it starts
+	EventTime   int64  `json:"event_time"`   // When the event happened at Binance, Unix timestamp in milliseconds
+	PublishTime int64  `json:"publish_time"` // When the message was published, Unix timestamp in milliseconds
 }
@@ -0,0 +1,112 @@
+package publisher
+
+import (
+	"fmt"
+	"strings"
+)
+
+// channelPrefix starts every channel and key written by the publishers
+const channelPrefix = "binance:"
+
+// KlineChannel returns the channel of a symbol's klines of one interval
+func KlineChannel(symbol, interval string) string {
+	return fmt.Sprintf("binance:kline:%s:%s", symbol, interval)
+}
+
+// ClosedKlineChannel returns the channel of a symbol's closed klines of one interval.
+// Closed klines are published on both it and the kline channel.
+func ClosedKlineChannel(symbol, interval string) string {
+	return fmt.Sprintf("binance:closed_kline:%s:%s", symbol, interval)
+}
+
+// BarChannel returns the channel of a symbol's bars of one spec
+func BarChannel(symbol, spec string) string {
+	return fmt.Sprintf("binance:bar:%s:%s", symbol, spec)
+}
+
+// TickerChannel returns the channel of a symbol's tickers
+func TickerChannel(symbol string) string {
+	return fmt.Sprintf("binance:ticker:%s", symbol)
+}
+
+// DepthChannel returns the channel of a symbol's depth updates
+func DepthChannel(symbol string) string {
+	return fmt.Sprintf("binance:depth:%s", symbol)
+}
+
+// TradeChannel returns the channel of a symbol's aggregated trades
+func TradeChannel(symbol string) string {
+	return fmt.Sprintf("binance:trade:%s", symbol)
+}
+
+// LatestKey returns the key caching the latest message of channel, e.g.
+// binance:latest:kline:BTCUSDT:1m for binance:kline:BTCUSDT:1m. Trades are not cached.
+func LatestKey(channel string) string {
+	return channelPrefix + "latest:" + strings.TrimPrefix(channel, channelPrefix)
+}
+
+// HistoryKey returns the sorted set keeping the recent history of channel, e.g.
+// binance:history:kline:BTCUSDT:1m for binance:kline:BTCUSDT:1m. It holds the last closed
+// klines or bars scored by open time, or the last trades scored by trade ID.
+func HistoryKey(channel string) string {
+	return channelPrefix + "history:" + strings.TrimPrefix(channel, channelPrefix)
+}
+
+// StreamKey returns the Redis stream mirroring channel in the streams publish mode, e.g.
+// binance:stream:kline:BTCUSDT:1m for binance:kline:BTCUSDT:1m
+func StreamKey(channel string) string {
+	return channelPrefix + "stream:" + strings.TrimPrefix(channel, channelPrefix)
+}
+
+// KafkaTopic returns the Kafka topic and message key of channel. The topic is the channel
+// without its symbol, which becomes the key so that a symbol's messages stay ordered in
+// one partition, e.g. binance.kline.1m and BTCUSDT for binance:kline:BTCUSDT:1m.
+func KafkaTopic(channel string) (topic, key string) {
+	parts := strings.Split(channel, ":")
+
+	// The symbol follows the data type, which JSON channels prefix with json
+	symbolIndex := 2
+	if len(parts) > 1 && channelPrefix+parts[1]+":" == jsonPrefix {
+		symbolIndex = 3
+	}
+	if len(parts) <= symbolIndex {
+		return strings.Join(parts, "."), ""
+	}
+
+	key = parts[symbolIndex]
+	parts = append(parts[:symbolIndex], parts[symbolIndex+1:]...)
+	return strings.Join(parts, "."), key
+}
+
+// NATSSubject returns the NATS subject of channel, e.g. binance.kline.BTCUSDT.1m for
+// binance:kline:BTCUSDT:1m. Subscribe to binance.kline.*.1m or binance.> for several.
+func NATSSubject(channel string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(channel, ".", "_"), ":", ".")
+}
+
+// ParseChannel validates a live data channel name and returns it with the symbol
+// upper-cased
+func ParseChannel(channel string) (string, error) {
+	parts := strings.Split(channel, ":")
+	if len(parts) < 3 || parts[0]+":" != channelPrefix || parts[2] == "" {
+		return "", fmt.Errorf("invalid channel %q", channel)
+	}
+
+	symbol := strings.ToUpper(parts[2])
+	switch {
+	case parts[1] == "kline" && len(parts) == 4 && parts[3] != "":
+		return KlineChannel(symbol, parts[3]), nil
+	case parts[1] == "closed_kline" && len(parts) == 4 && parts[3] != "":
+		return ClosedKlineChannel(symbol, parts[3]), nil
+	case parts[1] == "bar" && len(parts) == 4 && parts[3] != "":
+		return BarChannel(symbol, parts[3]), nil
+	case parts[1] == "ticker" && len(parts) == 3:
+		return TickerChannel(symbol), nil
+	case parts[1] == "depth" && len(parts) == 3:
+		return DepthChannel(symbol), nil
+	case parts[1] == "trade" && len(parts) == 3:
+		return TradeChannel(symbol), nil
+	}
+
+	return "", fmt.Errorf("invalid channel %q", channel)
+}
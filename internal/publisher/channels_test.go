@@ -0,0 +1,54 @@
+package publisher
+
+import "testing"
+
+func TestKafkaTopic(t *testing.T) {
+	tests := []struct {
+		channel string
+		topic   string
+		key     string
+	}{
+		{KlineChannel("BTCUSDT", "1m"), "binance.kline.1m", "BTCUSDT"},
+		{ClosedKlineChannel("BTCUSDT", "1h"), "binance.closed_kline.1h", "BTCUSDT"},
+		{BarChannel("ETHUSDT", "tick:100"), "binance.bar.tick.100", "ETHUSDT"},
+		{TickerChannel("BTCUSDT"), "binance.ticker", "BTCUSDT"},
+		{TradeChannel("ETHUSDT"), "binance.trade", "ETHUSDT"},
+		{JSONKey(KlineChannel("BTCUSDT", "1m")), "binance.json.kline.1m", "BTCUSDT"},
+		{JSONKey(DepthChannel("BTCUSDT")), "binance.json.depth", "BTCUSDT"},
+		{"binance:symbols", "binance.symbols", ""},
+		{JSONKey("binance:symbols"), "binance.json.symbols", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.channel, func(t *testing.T) {
+			topic, key := KafkaTopic(tt.channel)
+			if topic != tt.topic || key != tt.key {
+				t.Errorf("got topic %q and key %q, want %q and %q", topic, key, tt.topic, tt.key)
+			}
+		})
+	}
+}
+
+func TestNATSSubject(t *testing.T) {
+	tests := []struct {
+		channel string
+		subject string
+	}{
+		{KlineChannel("BTCUSDT", "1m"), "binance.kline.BTCUSDT.1m"},
+		{TickerChannel("BTCUSDT"), "binance.ticker.BTCUSDT"},
+		{JSONKey(KlineChannel("BTCUSDT", "1m")), "binance.json.kline.BTCUSDT.1m"},
+		{JSONKey(TradeChannel("ETHUSDT")), "binance.json.trade.ETHUSDT"},
+		// Dots would split a token into several
+		{BarChannel("BTCUSDT", "volume:0.5"), "binance.bar.BTCUSDT.volume.0_5"},
+		{JSONKey(BarChannel("BTCUSDT", "dollar:1.5")), "binance.json.bar.BTCUSDT.dollar.1_5"},
+		{channelPrefix, "binance."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.channel, func(t *testing.T) {
+			if subject := NATSSubject(tt.channel); subject != tt.subject {
+				t.Errorf("got %q, want %q", subject, tt.subject)
+			}
+		})
+	}
+}
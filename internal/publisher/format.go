@@ -0,0 +1,41 @@
+package publisher
+
+import (
+	"strings"
+)
+
+// Format selects how live data is encoded on the wire
+type Format string
+
+const (
+	FormatProtobuf Format = "protobuf" // LiveData from proto/binance.proto
+	FormatJSON     Format = "json"     // models.LiveData
+	FormatBoth     Format = "both"     // Protobuf on the channels, JSON on the binance:json: ones
+)
+
+// SchemaVersion is the version of the published messages. Both formats carry it with the
+// format in an envelope header: the version field of the protobuf LiveData, the format and
+// version fields of the JSON object, and the format and version fields of stream entries
+// and of Kafka and NATS message headers. The LiveData converters set it, so API responses
+// carry it too. It is raised when a change could break consumers:
+//
+//	0: messages published before versioning, read as version 1
+//	1: kline open and close times in seconds
+//	2: kline open and close times in milliseconds, is_closed added
+const SchemaVersion = 2
+
+// Envelope header names of Kafka and NATS messages
+const (
+	headerFormat  = "format"
+	headerVersion = "version"
+)
+
+// jsonPrefix starts the channels and keys of JSON messages in the both format
+const jsonPrefix = channelPrefix + "json:"
+
+// JSONKey returns where the JSON copy of a channel or key is written in the both format,
+// e.g. binance:json:kline:BTCUSDT:1m for binance:kline:BTCUSDT:1m and
+// binance:json:latest:kline:BTCUSDT:1m for its latest key
+func JSONKey(key string) string {
+	return jsonPrefix + strings.TrimPrefix(key, channelPrefix)
+}
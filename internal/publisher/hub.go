@@ -0,0 +1,205 @@
+package publisher
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+
+	"github.com/binance-live/internal/models"
+	binanceProto "github.com/binance-live/proto"
+)
+
+// ErrSlowSubscriber is the error of a subscription dropped because its buffer was full
+var ErrSlowSubscriber = errors.New("subscriber too slow, buffer full")
+
+// Hub is a Publisher that passes every message on to the next publisher and fans it
+// out in-process to subscribers, such as gRPC streams and the WebSocket gateway. Publishing never waits for a
+// subscriber: one whose buffer is full is dropped and must subscribe again.
+type Hub struct {
+	next        Publisher
+	sequence    sequencer
+	mu          sync.RWMutex
+	subscribers map[*Subscription]struct{}
+}
+
+// NewHub creates a new hub publishing to next
+func NewHub(next Publisher) *Hub {
+	return &Hub{
+		next:        next,
+		subscribers: make(map[*Subscription]struct{}),
+	}
+}
+
+// SubscriptionFilter selects live data by symbol, type and interval. An empty list
+// matches everything; intervals only apply to klines. Bars are klines whose interval is
+// their spec, e.g. tick:100, so Intervals must list the specs of the bars wanted.
+type SubscriptionFilter struct {
+	Symbols   []string
+	Types     []binanceProto.DataType
+	Intervals []string
+}
+
+// Message is live data with the channel it is published on
+type Message struct {
+	Channel string
+	Data    *binanceProto.LiveData
+
+	// Closed is set for closed klines: the same kline on its closed kline channel,
+	// numbered by that channel's own sequence as the Redis publisher does
+	Closed *binanceProto.LiveData
+}
+
+// Subscription receives the live data matching its filter
+type Subscription struct {
+	hub     *Hub
+	filter  SubscriptionFilter
+	ch      chan Message
+	done    chan struct{}
+	once    sync.Once
+	dropped bool
+}
+
+// Subscribe registers a subscription buffering up to buffer messages. Messages are
+// shared between subscribers and must not be modified.
+func (h *Hub) Subscribe(filter SubscriptionFilter, buffer int) *Subscription {
+	sub := &Subscription{
+		hub:    h,
+		filter: filter,
+		ch:     make(chan Message, max(buffer, 1)),
+		done:   make(chan struct{}),
+	}
+
+	h.mu.Lock()
+	h.subscribers[sub] = struct{}{}
+	h.mu.Unlock()
+
+	return sub
+}
+
+// C returns the channel delivering the subscription's messages
+func (s *Subscription) C() <-chan Message {
+	return s.ch
+}
+
+// Done returns a channel closed when the subscription ends, by Close or because it fell behind
+func (s *Subscription) Done() <-chan struct{} {
+	return s.done
+}
+
+// Err returns ErrSlowSubscriber once the subscription was dropped for falling behind
+func (s *Subscription) Err() error {
+	s.hub.mu.RLock()
+	defer s.hub.mu.RUnlock()
+
+	if s.dropped {
+		return ErrSlowSubscriber
+	}
+	return nil
+}
+
+// Close unregisters the subscription
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	delete(s.hub.subscribers, s)
+	s.hub.mu.Unlock()
+
+	s.once.Do(func() { close(s.done) })
+}
+
+// matches reports whether liveData passes the subscription filter
+func (s *Subscription) matches(liveData *binanceProto.LiveData) bool {
+	if len(s.filter.Symbols) > 0 && !slices.Contains(s.filter.Symbols, liveData.Symbol) {
+		return false
+	}
+	if len(s.filter.Types) > 0 && !slices.Contains(s.filter.Types, liveData.Type) {
+		return false
+	}
+	if kline := liveData.GetKline(); kline != nil && len(s.filter.Intervals) > 0 {
+		return slices.Contains(s.filter.Intervals, kline.Interval)
+	}
+	return true
+}
+
+// stamp sets the schema version and metadata of liveData, numbered by the hub's own
+// sequence of channel
+func (h *Hub) stamp(channel string, liveData *binanceProto.LiveData) {
+	liveData.Version = SchemaVersion
+	stampMetadata(liveData, h.sequence.next(channel))
+}
+
+// broadcast stamps the live data of msg and delivers it to every matching subscriber,
+// dropping those that are full
+func (h *Hub) broadcast(msg Message) {
+	h.stamp(msg.Channel, msg.Data)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for sub := range h.subscribers {
+		if !sub.matches(msg.Data) {
+			continue
+		}
+
+		select {
+		case sub.ch <- msg:
+		default:
+			sub.dropped = true
+			delete(h.subscribers, sub)
+			sub.once.Do(func() { close(sub.done) })
+		}
+	}
+}
+
+// PublishKline publishes a kline and fans it out to subscribers
+func (h *Hub) PublishKline(ctx context.Context, kline *models.Kline) error {
+	err := h.next.PublishKline(ctx, kline)
+
+	msg := Message{Channel: KlineChannel(kline.Symbol, kline.Interval), Data: KlineLiveData(kline)}
+	if kline.IsClosed {
+		msg.Closed = KlineLiveData(kline)
+		h.stamp(ClosedKlineChannel(kline.Symbol, kline.Interval), msg.Closed)
+	}
+	h.broadcast(msg)
+	return err
+}
+
+// PublishBar publishes a bar and fans it out to subscribers
+func (h *Hub) PublishBar(ctx context.Context, bar *models.Bar) error {
+	err := h.next.PublishBar(ctx, bar)
+	h.broadcast(Message{Channel: BarChannel(bar.Symbol, bar.Spec), Data: BarLiveData(bar)})
+	return err
+}
+
+// PublishTicker publishes a ticker and fans it out to subscribers
+func (h *Hub) PublishTicker(ctx context.Context, ticker *models.Ticker) error {
+	err := h.next.PublishTicker(ctx, ticker)
+	h.broadcast(Message{Channel: TickerChannel(ticker.Symbol), Data: TickerLiveData(ticker)})
+	return err
+}
+
+// PublishDepth publishes a depth update and fans it out to subscribers
+func (h *Hub) PublishDepth(ctx context.Context, depth *models.DepthSnapshot) error {
+	err := h.next.PublishDepth(ctx, depth)
+	if liveData, convErr := DepthLiveData(depth); convErr == nil {
+		h.broadcast(Message{Channel: DepthChannel(depth.Symbol), Data: liveData})
+	}
+	return err
+}
+
+// PublishTrade publishes a trade and fans it out to subscribers
+func (h *Hub) PublishTrade(ctx context.Context, trade *models.Trade) error {
+	err := h.next.PublishTrade(ctx, trade)
+	h.broadcast(Message{Channel: TradeChannel(trade.Symbol), Data: TradeLiveData(trade)})
+	return err
+}
+
+// PublishAllSymbols publishes the list of all active symbols; it is not fanned out
+func (h *Hub) PublishAllSymbols(ctx context.Context, symbols []models.Symbol) error {
+	return h.next.PublishAllSymbols(ctx, symbols)
+}
+
+// Close closes the next publisher
+func (h *Hub) Close() error {
+	return h.next.Close()
+}
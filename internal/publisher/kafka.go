@@ -0,0 +1,121 @@
+package publisher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/binance-live/internal/config"
+	"github.com/segmentio/kafka-go"
+	"go.uber.org/zap"
+)
+
+// kafkaTransport is the transport delivering live data to Kafka, see KafkaTopic. Messages
+// are batched and sent asynchronously by default so publishing never waits for the
+// brokers; failed batches are retried up to max_attempts times, then logged and dropped,
+// and publishing does not see the failure. In the sync mode, send waits for its message
+// to be acknowledged as acks says and returns the delivery error, which acks all needs to
+// guarantee that a message reported as published is stored.
+type kafkaTransport struct {
+	writer    *kafka.Writer
+	jsonKeys  bool // JSON goes to the binance.json. topics
+	async     bool // Delivery errors are only logged
+	logger    *zap.Logger
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// newKafkaTransport creates a Kafka transport. Topics are created by the brokers when
+// they allow it.
+func newKafkaTransport(cfg *config.KafkaConfig, jsonKeys bool, logger *zap.Logger) (*kafkaTransport, error) {
+	if len(cfg.Brokers) == 0 {
+		return nil, fmt.Errorf("publisher.kafka.brokers is required for the kafka backend")
+	}
+
+	var acks kafka.RequiredAcks
+	switch cfg.Acks {
+	case "none":
+		acks = kafka.RequireNone
+	case "one":
+		acks = kafka.RequireOne
+	case "all", "":
+		acks = kafka.RequireAll
+	default:
+		return nil, fmt.Errorf("unknown publisher.kafka.acks %q (none, one or all)", cfg.Acks)
+	}
+
+	t := &kafkaTransport{
+		jsonKeys: jsonKeys,
+		async:    !cfg.Sync,
+		logger:   logger,
+	}
+	t.writer = &kafka.Writer{
+		Addr:                   kafka.TCP(cfg.Brokers...),
+		Balancer:               &kafka.Hash{}, // Same key, same partition
+		RequiredAcks:           acks,
+		MaxAttempts:            cfg.MaxAttempts,
+		BatchTimeout:           time.Duration(cfg.BatchTimeout) * time.Millisecond,
+		Async:                  t.async,
+		AllowAutoTopicCreation: true,
+		Completion:             t.completed,
+	}
+
+	return t, nil
+}
+
+// completed logs the batches that could not be delivered asynchronously; in the sync mode
+// send returns the error instead
+func (t *kafkaTransport) completed(messages []kafka.Message, err error) {
+	if err == nil || len(messages) == 0 || !t.async {
+		return
+	}
+
+	t.logger.Warn("Failed to publish live data to Kafka",
+		zap.String("topic", messages[0].Topic),
+		zap.Int("messages", len(messages)),
+		zap.Error(err),
+	)
+}
+
+// send queues data on the topic of channel, keyed by symbol, or in the sync mode writes it
+// and waits for the acks. Kafka keeps no values.
+func (t *kafkaTransport) send(ctx context.Context, channel string, format Format, data []byte, k keep) error {
+	topic, key := KafkaTopic(formatKey(format, t.jsonKeys, channel))
+
+	err := t.writer.WriteMessages(ctx, kafka.Message{
+		Topic: topic,
+		Key:   []byte(key),
+		Value: data,
+		Headers: []kafka.Header{
+			{Key: headerFormat, Value: []byte(format)},
+			{Key: headerVersion, Value: []byte(strconv.Itoa(SchemaVersion))},
+		},
+	})
+	var writeErrs kafka.WriteErrors
+	if errors.As(err, &writeErrs) && len(writeErrs) == 1 {
+		err = writeErrs[0] // The error of the only message written
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write Kafka message: %w", err)
+	}
+
+	return nil
+}
+
+// store does nothing; Kafka keeps no values
+func (t *kafkaTransport) store(ctx context.Context, key string, format Format, data []byte) error {
+	return nil
+}
+
+// Close sends the batched messages and closes the connections to the brokers
+func (t *kafkaTransport) Close() error {
+	t.closeOnce.Do(func() {
+		if err := t.writer.Close(); err != nil {
+			t.closeErr = fmt.Errorf("failed to close Kafka writer: %w", err)
+		}
+	})
+	return t.closeErr
+}
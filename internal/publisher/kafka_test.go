@@ -0,0 +1,215 @@
+package publisher
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/binance-live/internal/config"
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/protocol"
+	metadataAPI "github.com/segmentio/kafka-go/protocol/metadata"
+	produceAPI "github.com/segmentio/kafka-go/protocol/produce"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// fakeBroker is a kafka.RoundTripper serving one-partition topics. It records the
+// messages produced and rejects them with errorCode if set.
+type fakeBroker struct {
+	mu        sync.Mutex
+	errorCode int16
+	messages  []kafka.Message
+}
+
+func (b *fakeBroker) RoundTrip(ctx context.Context, addr net.Addr, req protocol.Message) (protocol.Message, error) {
+	switch req := req.(type) {
+	case *metadataAPI.Request:
+		res := &metadataAPI.Response{}
+		for _, topic := range req.TopicNames {
+			res.Topics = append(res.Topics, metadataAPI.ResponseTopic{
+				Name:       topic,
+				Partitions: []metadataAPI.ResponsePartition{{PartitionIndex: 0}},
+			})
+		}
+		return res, nil
+
+	case *produceAPI.Request:
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		res := &produceAPI.Response{}
+		for _, topic := range req.Topics {
+			resTopic := produceAPI.ResponseTopic{Topic: topic.Topic}
+			for _, partition := range topic.Partitions {
+				if b.errorCode == 0 {
+					if err := b.record(topic.Topic, partition.RecordSet.Records); err != nil {
+						return nil, err
+					}
+				}
+				resTopic.Partitions = append(resTopic.Partitions, produceAPI.ResponsePartition{
+					Partition: partition.Partition,
+					ErrorCode: b.errorCode,
+				})
+			}
+			res.Topics = append(res.Topics, resTopic)
+		}
+		return res, nil
+	}
+
+	return nil, errors.New("unexpected request")
+}
+
+// record keeps the records produced to topic
+func (b *fakeBroker) record(topic string, records protocol.RecordReader) error {
+	for {
+		record, err := records.ReadRecord()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		message := kafka.Message{Topic: topic}
+		if message.Key, err = protocol.ReadAll(record.Key); err != nil {
+			return err
+		}
+		if message.Value, err = protocol.ReadAll(record.Value); err != nil {
+			return err
+		}
+		for _, header := range record.Headers {
+			message.Headers = append(message.Headers, kafka.Header{Key: header.Key, Value: header.Value})
+		}
+		b.messages = append(b.messages, message)
+	}
+}
+
+// produced returns the messages produced so far
+func (b *fakeBroker) produced() []kafka.Message {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]kafka.Message(nil), b.messages...)
+}
+
+// testKafkaTransport creates a Kafka transport writing to a fake broker
+func testKafkaTransport(t *testing.T, cfg config.KafkaConfig, jsonKeys bool) (*kafkaTransport, *fakeBroker) {
+	t.Helper()
+
+	cfg.Brokers = []string{"kafka:9092"}
+	transport, err := newKafkaTransport(&cfg, jsonKeys, zap.NewNop())
+	if err != nil {
+		t.Fatalf("failed to create Kafka transport: %v", err)
+	}
+
+	broker := &fakeBroker{}
+	transport.writer.Transport = broker
+	return transport, broker
+}
+
+func TestKafkaTransportMessages(t *testing.T) {
+	// A long batch timeout: only Close sends the batch
+	transport, broker := testKafkaTransport(t, config.KafkaConfig{Acks: "all", BatchTimeout: 60000}, true)
+
+	sends := []struct {
+		channel string
+		format  Format
+	}{
+		{KlineChannel("BTCUSDT", "1m"), FormatProtobuf},
+		{KlineChannel("BTCUSDT", "1m"), FormatJSON},
+		{TradeChannel("ETHUSDT"), FormatProtobuf},
+	}
+	for _, s := range sends {
+		if err := transport.send(context.Background(), s.channel, s.format, []byte(s.channel), keep{}); err != nil {
+			t.Fatalf("failed to send: %v", err)
+		}
+	}
+	if got := broker.produced(); len(got) != 0 {
+		t.Fatalf("got %d messages produced before Close, want them batched", len(got))
+	}
+
+	if err := transport.Close(); err != nil {
+		t.Fatalf("failed to close: %v", err)
+	}
+
+	want := map[string]struct{ key, format string }{
+		"binance.kline.1m":      {"BTCUSDT", "protobuf"},
+		"binance.json.kline.1m": {"BTCUSDT", "json"},
+		"binance.trade":         {"ETHUSDT", "protobuf"},
+	}
+	got := broker.produced()
+	if len(got) != len(want) {
+		t.Fatalf("got %d messages produced on Close, want %d", len(got), len(want))
+	}
+	for _, message := range got {
+		w, ok := want[message.Topic]
+		if !ok {
+			t.Errorf("got a message on unexpected topic %s", message.Topic)
+			continue
+		}
+		if string(message.Key) != w.key {
+			t.Errorf("got key %s on %s, want %s", message.Key, message.Topic, w.key)
+		}
+
+		headers := map[string]string{}
+		for _, header := range message.Headers {
+			headers[header.Key] = string(header.Value)
+		}
+		if headers[headerFormat] != w.format || headers[headerVersion] != "2" {
+			t.Errorf("got headers %v on %s, want format %s and version 2", headers, message.Topic, w.format)
+		}
+	}
+}
+
+func TestKafkaTransportDeliveryErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		sync    bool
+		wantErr bool
+		wantLog bool
+	}{
+		{"async only logs", false, false, true},
+		{"sync returns the error", true, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport, broker := testKafkaTransport(t, config.KafkaConfig{Acks: "all", BatchTimeout: 1, MaxAttempts: 1, Sync: tt.sync}, false)
+			broker.errorCode = int16(kafka.NotEnoughReplicas)
+			core, logs := observer.New(zap.WarnLevel)
+			transport.logger = zap.New(core)
+
+			err := transport.send(context.Background(), TickerChannel("BTCUSDT"), FormatProtobuf, []byte("ticker"), keep{})
+			if tt.wantErr && !errors.Is(err, kafka.NotEnoughReplicas) {
+				t.Errorf("got error %v, want NotEnoughReplicas", err)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("got error %v, want none", err)
+			}
+
+			// Close waits for the async delivery
+			if err := transport.Close(); err != nil {
+				t.Fatalf("failed to close: %v", err)
+			}
+			if logged := logs.Len() > 0; logged != tt.wantLog {
+				t.Errorf("got failure logged %v, want %v", logged, tt.wantLog)
+			}
+		})
+	}
+}
+
+func TestKafkaTransportSync(t *testing.T) {
+	transport, broker := testKafkaTransport(t, config.KafkaConfig{Acks: "all", BatchTimeout: 1, Sync: true}, false)
+	defer transport.Close()
+
+	// The message is stored once send returns
+	if err := transport.send(context.Background(), DepthChannel("BTCUSDT"), FormatProtobuf, []byte("depth"), keep{}); err != nil {
+		t.Fatalf("failed to send: %v", err)
+	}
+	if got := broker.produced(); len(got) != 1 || got[0].Topic != "binance.depth" {
+		t.Errorf("got %v produced, want the depth message", got)
+	}
+}
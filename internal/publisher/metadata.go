@@ -0,0 +1,44 @@
+package publisher
+
+import (
+	"sync"
+	"time"
+
+	binanceProto "github.com/binance-live/proto"
+)
+
+// sequencer numbers the messages published on each channel, from 1. The zero value is ready to use.
+type sequencer struct {
+	mu   sync.Mutex
+	last map[string]uint64
+}
+
+// next returns the sequence number of the next message on channel
+func (s *sequencer) next(channel string) uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.last == nil {
+		s.last = make(map[string]uint64)
+	}
+	s.last[channel]++
+	return s.last[channel]
+}
+
+// eventMetadata returns the metadata of a message converted from an event at eventTime,
+// nil when the time is unknown, as for stored klines
+func eventMetadata(eventTime int64) *binanceProto.Metadata {
+	if eventTime == 0 {
+		return nil
+	}
+	return &binanceProto.Metadata{EventTime: eventTime}
+}
+
+// stampMetadata sets the sequence number and publish time of liveData, keeping its event time
+func stampMetadata(liveData *binanceProto.LiveData, sequence uint64) {
+	liveData.Metadata = &binanceProto.Metadata{
+		Sequence:    sequence,
+		EventTime:   liveData.GetMetadata().GetEventTime(),
+		PublishTime: time.Now().UnixMilli(),
+	}
+}
@@ -0,0 +1,65 @@
+package publisher
+
+import (
+	"context"
+	"errors"
+
+	"github.com/binance-live/internal/models"
+)
+
+// MultiPublisher publishes every message to several publishers, e.g. one per backend.
+// A publisher failing does not keep the message from the others.
+type MultiPublisher struct {
+	publishers []Publisher
+}
+
+// NewMultiPublisher creates a new publisher fanning out to publishers
+func NewMultiPublisher(publishers ...Publisher) *MultiPublisher {
+	return &MultiPublisher{publishers: publishers}
+}
+
+// each calls publish for every publisher and joins their errors
+func (m *MultiPublisher) each(publish func(Publisher) error) error {
+	var errs []error
+	for _, p := range m.publishers {
+		if err := publish(p); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// PublishKline publishes kline data to every publisher
+func (m *MultiPublisher) PublishKline(ctx context.Context, kline *models.Kline) error {
+	return m.each(func(p Publisher) error { return p.PublishKline(ctx, kline) })
+}
+
+// PublishBar publishes a closed bar to every publisher
+func (m *MultiPublisher) PublishBar(ctx context.Context, bar *models.Bar) error {
+	return m.each(func(p Publisher) error { return p.PublishBar(ctx, bar) })
+}
+
+// PublishTicker publishes ticker data to every publisher
+func (m *MultiPublisher) PublishTicker(ctx context.Context, ticker *models.Ticker) error {
+	return m.each(func(p Publisher) error { return p.PublishTicker(ctx, ticker) })
+}
+
+// PublishDepth publishes depth data to every publisher
+func (m *MultiPublisher) PublishDepth(ctx context.Context, depth *models.DepthSnapshot) error {
+	return m.each(func(p Publisher) error { return p.PublishDepth(ctx, depth) })
+}
+
+// PublishTrade publishes trade data to every publisher
+func (m *MultiPublisher) PublishTrade(ctx context.Context, trade *models.Trade) error {
+	return m.each(func(p Publisher) error { return p.PublishTrade(ctx, trade) })
+}
+
+// PublishAllSymbols publishes the list of all active symbols to every publisher
+func (m *MultiPublisher) PublishAllSymbols(ctx context.Context, symbols []models.Symbol) error {
+	return m.each(func(p Publisher) error { return p.PublishAllSymbols(ctx, symbols) })
+}
+
+// Close closes every publisher, sending their pending messages
+func (m *MultiPublisher) Close() error {
+	return m.each(func(p Publisher) error { return p.Close() })
+}
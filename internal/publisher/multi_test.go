@@ -0,0 +1,69 @@
+package publisher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/binance-live/internal/models"
+)
+
+// recordingPublisher records the tickers it publishes and fails with err if set
+type recordingPublisher struct {
+	Publisher
+	err     error
+	tickers []string
+	closed  bool
+}
+
+func (p *recordingPublisher) PublishTicker(ctx context.Context, ticker *models.Ticker) error {
+	if p.err != nil {
+		return p.err
+	}
+	p.tickers = append(p.tickers, ticker.Symbol)
+	return nil
+}
+
+func (p *recordingPublisher) Close() error {
+	p.closed = true
+	return p.err
+}
+
+func TestMultiPublisherFailingPublisher(t *testing.T) {
+	errKafka := errors.New("kafka unavailable")
+	errNATS := errors.New("nats unavailable")
+
+	redis := &recordingPublisher{}
+	kafka := &recordingPublisher{err: errKafka}
+	nats := &recordingPublisher{err: fmt.Errorf("failed to publish ticker: %w", errNATS)}
+	last := &recordingPublisher{}
+	m := NewMultiPublisher(redis, kafka, nats, last)
+
+	err := m.PublishTicker(context.Background(), &models.Ticker{Symbol: "BTCUSDT"})
+	if !errors.Is(err, errKafka) || !errors.Is(err, errNATS) {
+		t.Errorf("got error %v, want both publisher errors", err)
+	}
+	for i, p := range []*recordingPublisher{redis, last} {
+		if len(p.tickers) != 1 || p.tickers[0] != "BTCUSDT" {
+			t.Errorf("publisher %d got tickers %v, want BTCUSDT", i, p.tickers)
+		}
+	}
+
+	err = m.Close()
+	if !errors.Is(err, errKafka) || !errors.Is(err, errNATS) {
+		t.Errorf("got close error %v, want both publisher errors", err)
+	}
+	for i, p := range []*recordingPublisher{redis, kafka, nats, last} {
+		if !p.closed {
+			t.Errorf("publisher %d was not closed", i)
+		}
+	}
+}
+
+func TestMultiPublisherNoErrors(t *testing.T) {
+	m := NewMultiPublisher(&recordingPublisher{}, &recordingPublisher{})
+	if err := m.PublishTicker(context.Background(), &models.Ticker{Symbol: "BTCUSDT"}); err != nil {
+		t.Errorf("got error %v, want none", err)
+	}
+}
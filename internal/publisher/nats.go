@@ -0,0 +1,128 @@
+package publisher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/binance-live/internal/config"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"go.uber.org/zap"
+)
+
+// natsCloseTimeout bounds how long Close waits for JetStream acks
+const natsCloseTimeout = 5 * time.Second
+
+// natsTransport is the transport delivering live data to NATS, see NATSSubject. Core NATS
+// delivers at most once, to the subscribers connected at the time. With JetStream the
+// stream stores every message and acks it; publishing does not wait for the ack unless
+// max_pending messages are unacknowledged, and failed messages are logged.
+type natsTransport struct {
+	conn      *nats.Conn
+	js        jetstream.JetStream // nil: core NATS
+	jsonKeys  bool                // JSON goes to the binance.json. subjects
+	logger    *zap.Logger
+	closeOnce sync.Once
+}
+
+// newNATSTransport connects to NATS and, with JetStream and a stream name, creates the
+// stream capturing binance.> if it does not exist yet
+func newNATSTransport(cfg *config.NATSConfig, jsonKeys bool, logger *zap.Logger) (*natsTransport, error) {
+	conn, err := nats.Connect(cfg.URL, nats.Name("binance-live"), nats.MaxReconnects(-1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to NATS: %w", err)
+	}
+
+	t := &natsTransport{
+		conn:     conn,
+		jsonKeys: jsonKeys,
+		logger:   logger,
+	}
+	if !cfg.JetStream {
+		return t, nil
+	}
+
+	js, err := jetstream.New(conn,
+		jetstream.WithPublishAsyncMaxPending(max(cfg.MaxPending, 1)),
+		jetstream.WithPublishAsyncErrHandler(func(_ jetstream.JetStream, msg *nats.Msg, err error) {
+			logger.Warn("Failed to publish live data to NATS",
+				zap.String("subject", msg.Subject),
+				zap.Error(err),
+			)
+		}),
+	)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to create JetStream context: %w", err)
+	}
+	t.js = js
+
+	if cfg.Stream != "" {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		_, err := js.CreateStream(ctx, jetstream.StreamConfig{
+			Name:              cfg.Stream,
+			Subjects:          []string{NATSSubject(channelPrefix) + ">"},
+			MaxMsgsPerSubject: cfg.MaxMsgsPerSubject,
+		})
+		if err != nil && !errors.Is(err, jetstream.ErrStreamNameAlreadyInUse) {
+			conn.Close()
+			return nil, fmt.Errorf("failed to create JetStream stream: %w", err)
+		}
+	}
+
+	return t, nil
+}
+
+// send publishes data on the subject of channel. NATS keeps no values; with JetStream,
+// consumers can read the last messages per subject instead.
+func (t *natsTransport) send(ctx context.Context, channel string, format Format, data []byte, k keep) error {
+	msg := nats.NewMsg(NATSSubject(formatKey(format, t.jsonKeys, channel)))
+	msg.Data = data
+	msg.Header.Set(headerFormat, string(format))
+	msg.Header.Set(headerVersion, strconv.Itoa(SchemaVersion))
+
+	if t.js == nil {
+		if err := t.conn.PublishMsg(msg); err != nil {
+			return fmt.Errorf("failed to publish NATS message: %w", err)
+		}
+		return nil
+	}
+
+	if _, err := t.js.PublishMsgAsync(msg); err != nil {
+		return fmt.Errorf("failed to publish JetStream message: %w", err)
+	}
+	return nil
+}
+
+// store does nothing; NATS keeps no values
+func (t *natsTransport) store(ctx context.Context, key string, format Format, data []byte) error {
+	return nil
+}
+
+// Close waits for the outstanding JetStream acks, sends the buffered messages and closes
+// the connection
+func (t *natsTransport) Close() error {
+	t.closeOnce.Do(func() {
+		if t.js != nil {
+			select {
+			case <-t.js.PublishAsyncComplete():
+			case <-time.After(natsCloseTimeout):
+				t.logger.Warn("Timed out waiting for JetStream acks",
+					zap.Int("pending", t.js.PublishAsyncPending()),
+				)
+			}
+		}
+
+		if err := t.conn.Flush(); err != nil {
+			t.logger.Warn("Failed to flush NATS connection", zap.Error(err))
+		}
+		t.conn.Close()
+	})
+	return nil
+}
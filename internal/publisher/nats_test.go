@@ -0,0 +1,159 @@
+package publisher
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/binance-live/internal/config"
+	"github.com/binance-live/internal/models"
+	binanceProto "github.com/binance-live/proto"
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+)
+
+// runNATSServer starts an in-process NATS server, with JetStream if jetStream is set
+func runNATSServer(t *testing.T, jetStream bool) *server.Server {
+	t.Helper()
+
+	s, err := server.NewServer(&server.Options{
+		Host:      "127.0.0.1",
+		Port:      server.RANDOM_PORT,
+		NoLog:     true,
+		NoSigs:    true,
+		JetStream: jetStream,
+		StoreDir:  t.TempDir(),
+	})
+	if err != nil {
+		t.Fatalf("failed to create NATS server: %v", err)
+	}
+	go s.Start()
+	if !s.ReadyForConnections(5 * time.Second) {
+		t.Fatal("NATS server did not start")
+	}
+	t.Cleanup(s.Shutdown)
+
+	return s
+}
+
+// checkNATSMessage checks the subject and envelope headers of msg
+func checkNATSMessage(t *testing.T, subject string, header nats.Header, wantSubject string, wantFormat Format) {
+	t.Helper()
+
+	if subject != wantSubject {
+		t.Errorf("got subject %q, want %q", subject, wantSubject)
+	}
+	if format := header.Get(headerFormat); format != string(wantFormat) {
+		t.Errorf("got format header %q on %s, want %q", format, subject, wantFormat)
+	}
+	if version := header.Get(headerVersion); version != strconv.Itoa(SchemaVersion) {
+		t.Errorf("got version header %q on %s, want %d", version, subject, SchemaVersion)
+	}
+}
+
+func TestNATSTransportCore(t *testing.T) {
+	s := runNATSServer(t, false)
+
+	conn, err := nats.Connect(s.ClientURL())
+	if err != nil {
+		t.Fatalf("failed to connect to NATS: %v", err)
+	}
+	defer conn.Close()
+
+	sub, err := conn.SubscribeSync(NATSSubject(channelPrefix) + ">")
+	if err != nil {
+		t.Fatalf("failed to subscribe: %v", err)
+	}
+	if err := conn.Flush(); err != nil {
+		t.Fatalf("failed to flush subscription: %v", err)
+	}
+
+	transport, err := newNATSTransport(&config.NATSConfig{URL: s.ClientURL()}, true, zap.NewNop())
+	if err != nil {
+		t.Fatalf("failed to create NATS transport: %v", err)
+	}
+	p := formatPublisher(transport, FormatBoth)
+
+	if err := p.PublishTicker(context.Background(), &models.Ticker{Symbol: "BTCUSDT", Price: 42000}); err != nil {
+		t.Fatalf("failed to publish ticker: %v", err)
+	}
+	if err := p.Close(); err != nil {
+		t.Fatalf("failed to close publisher: %v", err)
+	}
+
+	// The both format publishes protobuf, then JSON on the binance.json. subjects
+	msg, err := sub.NextMsg(2 * time.Second)
+	if err != nil {
+		t.Fatalf("failed to receive protobuf message: %v", err)
+	}
+	checkNATSMessage(t, msg.Subject, msg.Header, "binance.ticker.BTCUSDT", FormatProtobuf)
+
+	var liveData binanceProto.LiveData
+	if err := proto.Unmarshal(msg.Data, &liveData); err != nil {
+		t.Fatalf("failed to decode protobuf message: %v", err)
+	}
+	if liveData.Symbol != "BTCUSDT" || liveData.GetTicker().GetPrice() != 42000 {
+		t.Errorf("got %v, want the BTCUSDT ticker at 42000", &liveData)
+	}
+
+	msg, err = sub.NextMsg(2 * time.Second)
+	if err != nil {
+		t.Fatalf("failed to receive JSON message: %v", err)
+	}
+	checkNATSMessage(t, msg.Subject, msg.Header, "binance.json.ticker.BTCUSDT", FormatJSON)
+}
+
+func TestNATSTransportJetStream(t *testing.T) {
+	s := runNATSServer(t, true)
+
+	cfg := &config.NATSConfig{URL: s.ClientURL(), JetStream: true, Stream: "BINANCE", MaxPending: 16}
+	transport, err := newNATSTransport(cfg, false, zap.NewNop())
+	if err != nil {
+		t.Fatalf("failed to create NATS transport: %v", err)
+	}
+	p := formatPublisher(transport, FormatProtobuf)
+
+	kline := &models.Kline{Symbol: "BTCUSDT", Interval: "1m", OpenTime: 1704067200000, CloseTime: 1704067259999}
+	if err := p.PublishKline(context.Background(), kline); err != nil {
+		t.Fatalf("failed to publish kline: %v", err)
+	}
+	// Close waits for the acks
+	if err := p.Close(); err != nil {
+		t.Fatalf("failed to close publisher: %v", err)
+	}
+
+	conn, err := nats.Connect(s.ClientURL())
+	if err != nil {
+		t.Fatalf("failed to connect to NATS: %v", err)
+	}
+	defer conn.Close()
+	js, err := jetstream.New(conn)
+	if err != nil {
+		t.Fatalf("failed to create JetStream context: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream, err := js.Stream(ctx, "BINANCE")
+	if err != nil {
+		t.Fatalf("the stream was not created: %v", err)
+	}
+
+	msg, err := stream.GetLastMsgForSubject(ctx, "binance.kline.BTCUSDT.1m")
+	if err != nil {
+		t.Fatalf("the kline was not stored: %v", err)
+	}
+	checkNATSMessage(t, msg.Subject, msg.Header, "binance.kline.BTCUSDT.1m", FormatProtobuf)
+
+	var liveData binanceProto.LiveData
+	if err := proto.Unmarshal(msg.Data, &liveData); err != nil {
+		t.Fatalf("failed to decode stored kline: %v", err)
+	}
+	if liveData.GetKline().GetOpenTime() != kline.OpenTime {
+		t.Errorf("got open time %d, want %d", liveData.GetKline().GetOpenTime(), kline.OpenTime)
+	}
+}
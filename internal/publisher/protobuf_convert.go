@@ -0,0 +1,179 @@
+package publisher
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/binance-live/internal/models"
+	binanceProto "github.com/binance-live/proto"
+	"google.golang.org/protobuf/proto"
+)
+
+// KlineLiveData converts a kline to the protobuf live data message
+func KlineLiveData(kline *models.Kline) *binanceProto.LiveData {
+	return &binanceProto.LiveData{
+		Type:      binanceProto.DataType_DATA_TYPE_KLINE,
+		Symbol:    kline.Symbol,
+		Timestamp: kline.OpenTime,
+		Version:   SchemaVersion,
+		Metadata:  eventMetadata(kline.EventTime),
+		Data: &binanceProto.LiveData_Kline{
+			Kline: &binanceProto.KlineData{
+				Interval:            kline.Interval,
+				OpenTime:            kline.OpenTime,
+				CloseTime:           kline.CloseTime,
+				OpenPrice:           kline.OpenPrice,
+				HighPrice:           kline.HighPrice,
+				LowPrice:            kline.LowPrice,
+				ClosePrice:          kline.ClosePrice,
+				Volume:              kline.Volume,
+				QuoteVolume:         kline.QuoteVolume,
+				TradesCount:         int32(kline.TradesCount),
+				TakerBuyVolume:      kline.TakerBuyVolume,
+				TakerBuyQuoteVolume: kline.TakerBuyQuoteVolume,
+				IsClosed:            kline.IsClosed,
+			},
+		},
+	}
+}
+
+// BarLiveData converts a bar to the protobuf live data message. Bars reuse the kline
+// message with the bar spec name as interval; their event time is the close time.
+func BarLiveData(bar *models.Bar) *binanceProto.LiveData {
+	return &binanceProto.LiveData{
+		Type:      binanceProto.DataType_DATA_TYPE_KLINE,
+		Symbol:    bar.Symbol,
+		Timestamp: bar.OpenTime,
+		Version:   SchemaVersion,
+		Metadata:  eventMetadata(bar.CloseTime),
+		Data: &binanceProto.LiveData_Kline{
+			Kline: &binanceProto.KlineData{
+				Interval:            bar.Spec,
+				OpenTime:            bar.OpenTime,
+				CloseTime:           bar.CloseTime,
+				OpenPrice:           bar.OpenPrice,
+				HighPrice:           bar.HighPrice,
+				LowPrice:            bar.LowPrice,
+				ClosePrice:          bar.ClosePrice,
+				Volume:              bar.Volume,
+				QuoteVolume:         bar.QuoteVolume,
+				TradesCount:         int32(bar.TradesCount),
+				TakerBuyVolume:      bar.TakerBuyVolume,
+				TakerBuyQuoteVolume: bar.TakerBuyQuoteVolume,
+				IsClosed:            true,
+			},
+		},
+	}
+}
+
+// TickerLiveData converts a ticker to the protobuf live data message
+func TickerLiveData(ticker *models.Ticker) *binanceProto.LiveData {
+	tickerData := &binanceProto.TickerData{
+		Price:                  ticker.Price,
+		BidPrice:               ticker.BidPrice,
+		BidQty:                 ticker.BidQty,
+		AskPrice:               ticker.AskPrice,
+		AskQty:                 ticker.AskQty,
+		Volume_24H:             ticker.Volume24h,
+		QuoteVolume_24H:        ticker.QuoteVolume24h,
+		PriceChange_24H:        ticker.PriceChange24h,
+		PriceChangePercent_24H: ticker.PriceChangePercent24h,
+		High_24H:               ticker.High24h,
+		Low_24H:                ticker.Low24h,
+	}
+	if ticker.TradesCount24h != nil {
+		tickerData.TradesCount_24H = proto.Int32(int32(*ticker.TradesCount24h))
+	}
+
+	return &binanceProto.LiveData{
+		Type:      binanceProto.DataType_DATA_TYPE_TICKER,
+		Symbol:    ticker.Symbol,
+		Timestamp: ticker.Timestamp,
+		Version:   SchemaVersion,
+		Metadata:  eventMetadata(ticker.Timestamp),
+		Data: &binanceProto.LiveData_Ticker{
+			Ticker: tickerData,
+		},
+	}
+}
+
+// DepthLiveData converts a depth snapshot to the protobuf live data message
+func DepthLiveData(depth *models.DepthSnapshot) (*binanceProto.LiveData, error) {
+	bids, err := parsePriceLevels(depth.Bids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse bids: %w", err)
+	}
+
+	asks, err := parsePriceLevels(depth.Asks)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse asks: %w", err)
+	}
+
+	return &binanceProto.LiveData{
+		Type:      binanceProto.DataType_DATA_TYPE_DEPTH,
+		Symbol:    depth.Symbol,
+		Timestamp: depth.Timestamp,
+		Version:   SchemaVersion,
+		Metadata:  eventMetadata(depth.Timestamp),
+		Data: &binanceProto.LiveData_Depth{
+			Depth: &binanceProto.DepthData{
+				LastUpdateId: depth.LastUpdateID,
+				Bids:         bids,
+				Asks:         asks,
+			},
+		},
+	}, nil
+}
+
+// TradeLiveData converts an aggregated trade to the protobuf live data message
+func TradeLiveData(trade *models.Trade) *binanceProto.LiveData {
+	return &binanceProto.LiveData{
+		Type:      binanceProto.DataType_DATA_TYPE_TRADE,
+		Symbol:    trade.Symbol,
+		Timestamp: trade.Timestamp,
+		Version:   SchemaVersion,
+		Metadata:  eventMetadata(trade.Timestamp),
+		Data: &binanceProto.LiveData_Trade{
+			Trade: &binanceProto.TradeData{
+				TradeId:       trade.TradeID,
+				Price:         trade.Price,
+				Quantity:      trade.Quantity,
+				QuoteQuantity: trade.QuoteQuantity,
+				IsBuyerMaker:  trade.IsBuyerMaker,
+			},
+		},
+	}
+}
+
+// parsePriceLevels parses the JSON [price, quantity] string pairs of a depth snapshot
+func parsePriceLevels(jsonData string) ([]*binanceProto.PriceLevel, error) {
+	if jsonData == "" || jsonData == "null" {
+		return []*binanceProto.PriceLevel{}, nil
+	}
+
+	var pairs [][]string
+	if err := json.Unmarshal([]byte(jsonData), &pairs); err != nil {
+		return nil, err
+	}
+
+	levels := make([]*binanceProto.PriceLevel, 0, len(pairs))
+	for _, pair := range pairs {
+		if len(pair) < 2 {
+			return nil, fmt.Errorf("invalid price level %v", pair)
+		}
+
+		price, err := strconv.ParseFloat(pair[0], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid price %q: %w", pair[0], err)
+		}
+		quantity, err := strconv.ParseFloat(pair[1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid quantity %q: %w", pair[1], err)
+		}
+
+		levels = append(levels, &binanceProto.PriceLevel{Price: price, Quantity: quantity})
+	}
+
+	return levels, nil
+}
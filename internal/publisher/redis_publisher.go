@@ -2,52 +2,159 @@ package publisher
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"time"
 
+	"github.com/binance-live/internal/config"
 	"github.com/binance-live/internal/models"
 	"github.com/binance-live/internal/redis"
 	"go.uber.org/zap"
 )
 
-// Publisher interface defines the contract for publishing live data
+// Publisher interface defines the contract for publishing live data. Publish methods
+// return delivery errors, except where messages are sent later: with Redis batching
+// (publisher.flush_interval_ms above 0) they return nil once the message is queued, and
+// a failed delivery is only logged.
 type Publisher interface {
 	PublishKline(ctx context.Context, kline *models.Kline) error
+	PublishBar(ctx context.Context, bar *models.Bar) error
 	PublishTicker(ctx context.Context, ticker *models.Ticker) error
 	PublishDepth(ctx context.Context, depth *models.DepthSnapshot) error
 	PublishTrade(ctx context.Context, trade *models.Trade) error
 	PublishAllSymbols(ctx context.Context, symbols []models.Symbol) error
+	Close() error // Sends pending messages
 }
 
 // JSONPublisher handles publishing live data to Redis using JSON
 type JSONPublisher struct {
-	redis  *redis.Client
-	logger *zap.Logger
+	transport transport
+	sequence  sequencer
 }
 
-// NewJSONPublisher creates a new JSON publisher
+// NewJSONPublisher creates a new JSON publisher in the pubsub mode
 func NewJSONPublisher(redisClient *redis.Client, logger *zap.Logger) *JSONPublisher {
-	return &JSONPublisher{
-		redis:  redisClient,
-		logger: logger,
+	return &JSONPublisher{transport: newRedisSink(redisClient, logger)}
+}
+
+// PublishMode selects how live data is delivered through Redis
+type PublishMode string
+
+const (
+	PublishPubSub  PublishMode = "pubsub"  // PUBLISH to channels, fire and forget
+	PublishStreams PublishMode = "streams" // XADD to streams that consumers can replay
+	PublishBoth    PublishMode = "both"    // Both of the above
+)
+
+// New creates a new publisher for the configured backends (Redis by default), format
+// (protobuf by default for better performance) and Redis publish mode. With several
+// backends, every message goes to all of them.
+func New(redisClient *redis.Client, cfg *config.PublisherConfig, logger *zap.Logger) (Publisher, error) {
+	format := Format(cfg.Format)
+	switch format {
+	case "":
+		format = FormatProtobuf
+	case FormatProtobuf, FormatJSON, FormatBoth:
+	default:
+		return nil, fmt.Errorf("unknown publisher.format %q (protobuf, json or both)", cfg.Format)
+	}
+
+	mode := PublishMode(cfg.Mode)
+	switch mode {
+	case "":
+		mode = PublishPubSub
+	case PublishPubSub, PublishStreams, PublishBoth:
+	default:
+		return nil, fmt.Errorf("unknown publisher.mode %q (pubsub, streams or both)", cfg.Mode)
+	}
+
+	if mode != PublishPubSub && cfg.StreamMaxLen <= 0 {
+		return nil, fmt.Errorf("publisher.stream_max_len must be positive in %s mode", mode)
+	}
+
+	if cfg.FlushInterval < 0 || cfg.BatchSize < 0 {
+		return nil, fmt.Errorf("publisher.flush_interval_ms and publisher.batch_size must not be negative")
 	}
+
+	if cfg.History.Klines < 0 || cfg.History.Trades < 0 {
+		return nil, fmt.Errorf("publisher.history.klines and publisher.history.trades must not be negative")
+	}
+
+	backends := cfg.Backends
+	if len(backends) == 0 {
+		backends = []string{string(BackendRedis)}
+	}
+
+	publishers := make([]Publisher, 0, len(backends))
+	for _, backend := range backends {
+		var t transport
+		switch Backend(backend) {
+		case BackendRedis:
+			sink := newRedisSink(redisClient, logger)
+			sink.mode = mode
+			sink.streamMaxLen = cfg.StreamMaxLen
+			sink.jsonKeys = format == FormatBoth
+			sink.klineHistory = cfg.History.Klines
+			sink.tradeHistory = cfg.History.Trades
+			if cfg.FlushInterval > 0 {
+				sink.batch = redisClient.NewBatchWriter(cfg.BatchSize, time.Duration(cfg.FlushInterval)*time.Millisecond)
+			}
+			t = sink
+		case BackendKafka:
+			kafkaTransport, err := newKafkaTransport(&cfg.Kafka, format == FormatBoth, logger)
+			if err != nil {
+				NewMultiPublisher(publishers...).Close()
+				return nil, err
+			}
+			t = kafkaTransport
+		case BackendNATS:
+			natsTransport, err := newNATSTransport(&cfg.NATS, format == FormatBoth, logger)
+			if err != nil {
+				NewMultiPublisher(publishers...).Close()
+				return nil, err
+			}
+			t = natsTransport
+		default:
+			NewMultiPublisher(publishers...).Close()
+			return nil, fmt.Errorf("unknown publisher backend %q (redis, kafka or nats)", backend)
+		}
+
+		publishers = append(publishers, formatPublisher(t, format))
+	}
+
+	if len(publishers) == 1 {
+		return publishers[0], nil
+	}
+	return NewMultiPublisher(publishers...), nil
 }
 
-// New creates a new publisher (defaults to protobuf for better performance)
-func New(redisClient *redis.Client, logger *zap.Logger) Publisher {
-	return NewProtobufPublisher(redisClient, logger)
+// send stamps liveData with the envelope header and metadata, encodes it and sends it on channel
+func (p *JSONPublisher) send(ctx context.Context, channel string, liveData models.LiveData, k keep) error {
+	liveData.Format = string(FormatJSON)
+	liveData.Version = SchemaVersion
+	liveData.Metadata.Sequence = p.sequence.next(channel)
+	liveData.Metadata.PublishTime = time.Now().UnixMilli()
+	data, err := json.Marshal(liveData)
+	if err != nil {
+		return fmt.Errorf("failed to marshal data: %w", err)
+	}
+
+	return p.transport.send(ctx, channel, FormatJSON, data, k)
 }
 
-// PublishKline publishes kline data to Redis
+// PublishKline publishes kline data to Redis. Closed klines also go to the closed kline
+// channel, and are added to the recent history.
 func (p *JSONPublisher) PublishKline(ctx context.Context, kline *models.Kline) error {
 	// Create live data structure
 	liveData := models.LiveData{
 		Type:      "kline",
 		Symbol:    kline.Symbol,
 		Timestamp: kline.OpenTime,
+		Metadata:  models.Metadata{EventTime: kline.EventTime},
 		Data: map[string]interface{}{
 			"interval":               kline.Interval,
-			"open_time":              kline.OpenTime / 1000,  // Convert milliseconds to seconds
-			"close_time":             kline.CloseTime / 1000, // Convert milliseconds to seconds
+			"open_time":              kline.OpenTime,
+			"close_time":             kline.CloseTime,
 			"open_price":             kline.OpenPrice,
 			"high_price":             kline.HighPrice,
 			"low_price":              kline.LowPrice,
@@ -57,23 +164,56 @@ func (p *JSONPublisher) PublishKline(ctx context.Context, kline *models.Kline) e
 			"trades_count":           kline.TradesCount,
 			"taker_buy_volume":       kline.TakerBuyVolume,
 			"taker_buy_quote_volume": kline.TakerBuyQuoteVolume,
+			"is_closed":              kline.IsClosed,
 		},
 	}
 
-	// Publish to channel
-	channel := fmt.Sprintf("binance:kline:%s:%s", kline.Symbol, kline.Interval)
-	if err := p.redis.PublishJSON(ctx, channel, liveData); err != nil {
+	channel := KlineChannel(kline.Symbol, kline.Interval)
+	k := keep{latest: true, history: kline.IsClosed, order: kline.OpenTime}
+	if err := p.send(ctx, channel, liveData, k); err != nil {
 		return fmt.Errorf("failed to publish kline: %w", err)
 	}
 
-	// Also store latest kline in Redis for quick access
-	key := fmt.Sprintf("binance:latest:kline:%s:%s", kline.Symbol, kline.Interval)
-	if err := p.redis.SetJSON(ctx, key, liveData, 0); err != nil {
-		p.logger.Warn("Failed to cache kline in Redis",
-			zap.String("symbol", kline.Symbol),
-			zap.String("interval", kline.Interval),
-			zap.Error(err),
-		)
+	if kline.IsClosed {
+		channel := ClosedKlineChannel(kline.Symbol, kline.Interval)
+		if err := p.send(ctx, channel, liveData, keep{latest: true}); err != nil {
+			return fmt.Errorf("failed to publish closed kline: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// PublishBar publishes a closed bar built from aggregated trades to Redis
+func (p *JSONPublisher) PublishBar(ctx context.Context, bar *models.Bar) error {
+	liveData := models.LiveData{
+		Type:      "bar",
+		Symbol:    bar.Symbol,
+		Timestamp: bar.OpenTime,
+		Metadata:  models.Metadata{EventTime: bar.CloseTime},
+		Data: map[string]interface{}{
+			"spec":                   bar.Spec,
+			"open_time":              bar.OpenTime,
+			"close_time":             bar.CloseTime,
+			"open_price":             bar.OpenPrice,
+			"high_price":             bar.HighPrice,
+			"low_price":              bar.LowPrice,
+			"close_price":            bar.ClosePrice,
+			"volume":                 bar.Volume,
+			"quote_volume":           bar.QuoteVolume,
+			"trades_count":           bar.TradesCount,
+			"taker_buy_volume":       bar.TakerBuyVolume,
+			"taker_buy_quote_volume": bar.TakerBuyQuoteVolume,
+			"first_trade_id":         bar.FirstTradeID,
+			"last_trade_id":          bar.LastTradeID,
+			"is_closed":              true,
+		},
+	}
+
+	channel := BarChannel(bar.Symbol, bar.Spec)
+	k := keep{latest: true, history: true, order: bar.OpenTime}
+	if err := p.send(ctx, channel, liveData, k); err != nil {
+		return fmt.Errorf("failed to publish bar: %w", err)
 	}
 
 	return nil
@@ -85,6 +225,7 @@ func (p *JSONPublisher) PublishTicker(ctx context.Context, ticker *models.Ticker
 		Type:      "ticker",
 		Symbol:    ticker.Symbol,
 		Timestamp: ticker.Timestamp,
+		Metadata:  models.Metadata{EventTime: ticker.Timestamp},
 		Data: map[string]interface{}{
 			"price":                    ticker.Price,
 			"bid_price":                ticker.BidPrice,
@@ -101,21 +242,11 @@ func (p *JSONPublisher) PublishTicker(ctx context.Context, ticker *models.Ticker
 		},
 	}
 
-	// Publish to channel
-	channel := fmt.Sprintf("binance:ticker:%s", ticker.Symbol)
-	if err := p.redis.PublishJSON(ctx, channel, liveData); err != nil {
+	channel := TickerChannel(ticker.Symbol)
+	if err := p.send(ctx, channel, liveData, keep{latest: true}); err != nil {
 		return fmt.Errorf("failed to publish ticker: %w", err)
 	}
 
-	// Cache in Redis
-	key := fmt.Sprintf("binance:latest:ticker:%s", ticker.Symbol)
-	if err := p.redis.SetJSON(ctx, key, liveData, 0); err != nil {
-		p.logger.Warn("Failed to cache ticker in Redis",
-			zap.String("symbol", ticker.Symbol),
-			zap.Error(err),
-		)
-	}
-
 	return nil
 }
 
@@ -125,37 +256,29 @@ func (p *JSONPublisher) PublishDepth(ctx context.Context, depth *models.DepthSna
 		Type:      "depth",
 		Symbol:    depth.Symbol,
 		Timestamp: depth.Timestamp,
+		Metadata:  models.Metadata{EventTime: depth.Timestamp},
 		Data: map[string]interface{}{
 			"last_update_id": depth.LastUpdateID,
-			"bids":           depth.Bids,
-			"asks":           depth.Asks,
+			"bids":           rawPriceLevels(depth.Bids),
+			"asks":           rawPriceLevels(depth.Asks),
 		},
 	}
 
-	// Publish to channel
-	channel := fmt.Sprintf("binance:depth:%s", depth.Symbol)
-	if err := p.redis.PublishJSON(ctx, channel, liveData); err != nil {
+	channel := DepthChannel(depth.Symbol)
+	if err := p.send(ctx, channel, liveData, keep{latest: true}); err != nil {
 		return fmt.Errorf("failed to publish depth: %w", err)
 	}
 
-	// Cache in Redis
-	key := fmt.Sprintf("binance:latest:depth:%s", depth.Symbol)
-	if err := p.redis.SetJSON(ctx, key, liveData, 0); err != nil {
-		p.logger.Warn("Failed to cache depth in Redis",
-			zap.String("symbol", depth.Symbol),
-			zap.Error(err),
-		)
-	}
-
 	return nil
 }
 
-// PublishTrade publishes trade data to Redis
+// PublishTrade publishes trade data to Redis, and adds it to the recent history
 func (p *JSONPublisher) PublishTrade(ctx context.Context, trade *models.Trade) error {
 	liveData := models.LiveData{
 		Type:      "trade",
 		Symbol:    trade.Symbol,
 		Timestamp: trade.Timestamp,
+		Metadata:  models.Metadata{EventTime: trade.Timestamp},
 		Data: map[string]interface{}{
 			"trade_id":       trade.TradeID,
 			"price":          trade.Price,
@@ -165,9 +288,9 @@ func (p *JSONPublisher) PublishTrade(ctx context.Context, trade *models.Trade) e
 		},
 	}
 
-	// Publish to channel
-	channel := fmt.Sprintf("binance:trade:%s", trade.Symbol)
-	if err := p.redis.PublishJSON(ctx, channel, liveData); err != nil {
+	channel := TradeChannel(trade.Symbol)
+	k := keep{history: true, order: trade.TradeID}
+	if err := p.send(ctx, channel, liveData, k); err != nil {
 		return fmt.Errorf("failed to publish trade: %w", err)
 	}
 
@@ -181,10 +304,27 @@ func (p *JSONPublisher) PublishAllSymbols(ctx context.Context, symbols []models.
 		symbolList[i] = s.Symbol
 	}
 
-	key := "binance:symbols:active"
-	if err := p.redis.SetJSON(ctx, key, symbolList, 0); err != nil {
+	data, err := json.Marshal(symbolList)
+	if err != nil {
+		return fmt.Errorf("failed to marshal data: %w", err)
+	}
+
+	if err := p.transport.store(ctx, "binance:symbols:active", FormatJSON, data); err != nil {
 		return fmt.Errorf("failed to publish symbols: %w", err)
 	}
 
 	return nil
 }
+
+// Close sends pending messages
+func (p *JSONPublisher) Close() error {
+	return p.transport.Close()
+}
+
+// rawPriceLevels embeds the JSON [price, quantity] pairs of a depth snapshot as is
+func rawPriceLevels(jsonData string) json.RawMessage {
+	if jsonData == "" {
+		return json.RawMessage("[]")
+	}
+	return json.RawMessage(jsonData)
+}
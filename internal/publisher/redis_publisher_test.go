@@ -0,0 +1,102 @@
+package publisher
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/binance-live/internal/config"
+	"github.com/binance-live/internal/models"
+	"github.com/binance-live/internal/redis"
+	binanceProto "github.com/binance-live/proto"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+)
+
+// testRedis starts an in-memory Redis server and connects a client to it
+func testRedis(t *testing.T) (*miniredis.Miniredis, *redis.Client) {
+	t.Helper()
+
+	m := miniredis.RunT(t)
+	port, err := strconv.Atoi(m.Port())
+	if err != nil {
+		t.Fatalf("invalid miniredis port: %v", err)
+	}
+	client, err := redis.New(&config.RedisConfig{Host: m.Host(), Port: port, PoolSize: 2, LiveDataTTL: 60}, zap.NewNop())
+	if err != nil {
+		t.Fatalf("failed to connect to miniredis: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+
+	return m, client
+}
+
+func TestPublishModeStreams(t *testing.T) {
+	tests := []struct {
+		mode    string
+		streams bool
+	}{
+		{"pubsub", false},
+		{"streams", true},
+		{"both", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			m, client := testRedis(t)
+
+			p, err := New(client, &config.PublisherConfig{Mode: tt.mode, StreamMaxLen: 100}, zap.NewNop())
+			if err != nil {
+				t.Fatalf("failed to create publisher: %v", err)
+			}
+			defer p.Close()
+
+			for _, openTime := range []int64{0, 60000} {
+				kline := &models.Kline{Symbol: "BTCUSDT", Interval: "1m", OpenTime: openTime, CloseTime: openTime + 59999, IsClosed: true}
+				if err := p.PublishKline(context.Background(), kline); err != nil {
+					t.Fatalf("failed to publish kline: %v", err)
+				}
+			}
+
+			// The latest value is cached in every mode
+			if !m.Exists(LatestKey(KlineChannel("BTCUSDT", "1m"))) {
+				t.Error("latest kline was not set")
+			}
+
+			stream := StreamKey(KlineChannel("BTCUSDT", "1m"))
+			if !tt.streams {
+				if m.Exists(stream) {
+					t.Errorf("%s was written in the pubsub mode", stream)
+				}
+				return
+			}
+
+			entries, err := m.Stream(stream)
+			if err != nil {
+				t.Fatalf("failed to read %s: %v", stream, err)
+			}
+			if len(entries) != 2 {
+				t.Fatalf("got %d stream entries, want 2", len(entries))
+			}
+			for i, entry := range entries {
+				values := map[string]string{}
+				for j := 0; j+1 < len(entry.Values); j += 2 {
+					values[entry.Values[j]] = entry.Values[j+1]
+				}
+
+				if values[redis.StreamFormatField] != string(FormatProtobuf) || values[redis.StreamVersionField] != strconv.Itoa(SchemaVersion) {
+					t.Errorf("got entry header %v, want protobuf version %d", values, SchemaVersion)
+				}
+
+				var liveData binanceProto.LiveData
+				if err := proto.Unmarshal([]byte(values[redis.StreamDataField]), &liveData); err != nil {
+					t.Fatalf("failed to unmarshal entry: %v", err)
+				}
+				if got := liveData.GetKline().GetOpenTime(); got != int64(i)*60000 {
+					t.Errorf("got entry %d opening at %d, want %d", i, got, int64(i)*60000)
+				}
+			}
+		})
+	}
+}
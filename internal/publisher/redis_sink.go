@@ -0,0 +1,97 @@
+package publisher
+
+import (
+	"context"
+	"strings"
+
+	"github.com/binance-live/internal/redis"
+	"go.uber.org/zap"
+)
+
+// redisSink is the transport delivering live data to Redis. The commands of a message
+// are pipelined; with a batch writer, messages are batched too.
+type redisSink struct {
+	redis        *redis.Client
+	mode         PublishMode
+	streamMaxLen int64
+	jsonKeys     bool               // JSON goes to the binance:json: channels and keys
+	klineHistory int64              // Closed klines and bars kept per channel, 0: none
+	tradeHistory int64              // Trades kept per symbol, 0: none
+	batch        *redis.BatchWriter // nil: each message is sent on its own
+	logger       *zap.Logger
+}
+
+// newRedisSink creates a sink in the pubsub mode
+func newRedisSink(redisClient *redis.Client, logger *zap.Logger) *redisSink {
+	return &redisSink{
+		redis:  redisClient,
+		mode:   PublishPubSub,
+		logger: logger,
+	}
+}
+
+// key returns where data encoded in format is written for a channel or key
+func (s *redisSink) key(format Format, key string) string {
+	return formatKey(format, s.jsonKeys, key)
+}
+
+// historyLen returns how many messages the history of channel keeps
+func (s *redisSink) historyLen(channel string) int64 {
+	if strings.HasPrefix(channel, TradeChannel("")) {
+		return s.tradeHistory
+	}
+	return s.klineHistory
+}
+
+// send publishes data encoded in format on channel (PUBLISH, XADD to its stream or both,
+// depending on the mode), caches it as the channel's latest value and adds it to the
+// channel's history as k says. Batched messages are sent later; their errors are logged
+// per message.
+func (s *redisSink) send(ctx context.Context, channel string, format Format, data []byte, k keep) error {
+	queue := func(b *redis.Batch) {
+		if s.mode != PublishStreams {
+			b.Publish(s.key(format, channel), data)
+		}
+		if s.mode != PublishPubSub {
+			b.AddToStream(s.key(format, StreamKey(channel)), s.streamMaxLen, map[string]interface{}{
+				redis.StreamDataField:    data,
+				redis.StreamFormatField:  string(format),
+				redis.StreamVersionField: SchemaVersion,
+			})
+		}
+		if k.latest {
+			b.Set(s.key(format, LatestKey(channel)), data)
+		}
+		if maxLen := s.historyLen(channel); k.history && maxLen > 0 {
+			b.AddToHistory(s.key(format, HistoryKey(channel)), k.order, maxLen, data)
+		}
+	}
+
+	if s.batch == nil {
+		return s.redis.Pipelined(ctx, queue)
+	}
+
+	return s.batch.Enqueue(queue, func(err error) {
+		if err != nil {
+			s.logger.Warn("Failed to publish live data",
+				zap.String("channel", s.key(format, channel)),
+				zap.Error(err),
+			)
+		}
+	})
+}
+
+// store sets key to data with the live data TTL
+func (s *redisSink) store(ctx context.Context, key string, format Format, data []byte) error {
+	return s.redis.Pipelined(ctx, func(b *redis.Batch) {
+		b.Set(s.key(format, key), data)
+	})
+}
+
+// Close sends the messages still batched
+func (s *redisSink) Close() error {
+	if s.batch != nil {
+		s.batch.Close()
+	}
+	return nil
+}
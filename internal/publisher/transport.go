@@ -0,0 +1,52 @@
+package publisher
+
+import (
+	"context"
+)
+
+// Backend selects a message system live data is published to
+type Backend string
+
+const (
+	BackendRedis Backend = "redis" // Pub/sub or streams, plus the latest value caches
+	BackendKafka Backend = "kafka" // A topic per channel without its symbol, keyed by symbol
+	BackendNATS  Backend = "nats"  // A subject per channel, core NATS or JetStream
+)
+
+// keep says what a transport keeps of a message besides delivering it
+type keep struct {
+	latest  bool  // The latest value of the channel
+	history bool  // The recent history of the channel, see HistoryKey
+	order   int64 // Orders the history: open time, or trade ID for trades
+}
+
+// transport delivers encoded live data for the ProtobufPublisher and JSONPublisher.
+// Close sends pending messages and may be called more than once.
+type transport interface {
+	// send delivers data encoded in format on channel, and keeps it as k says if the
+	// transport keeps values
+	send(ctx context.Context, channel string, format Format, data []byte, k keep) error
+	// store keeps data under key, such as the active symbol list, if the transport keeps values
+	store(ctx context.Context, key string, format Format, data []byte) error
+	Close() error
+}
+
+// formatKey returns where data encoded in format is written for a channel or key: the
+// binance:json: name for JSON when jsonKeys is set, see JSONKey
+func formatKey(format Format, jsonKeys bool, key string) string {
+	if format == FormatJSON && jsonKeys {
+		return JSONKey(key)
+	}
+	return key
+}
+
+// formatPublisher returns the publisher encoding live data in format for t
+func formatPublisher(t transport, format Format) Publisher {
+	switch format {
+	case FormatJSON:
+		return &JSONPublisher{transport: t}
+	case FormatBoth:
+		return NewMultiPublisher(&ProtobufPublisher{transport: t}, &JSONPublisher{transport: t})
+	}
+	return &ProtobufPublisher{transport: t}
+}
@@ -0,0 +1,209 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrBatchWriterClosed is returned when queueing on a closed batch writer
+var ErrBatchWriterClosed = errors.New("batch writer closed")
+
+// batchFlushTimeout bounds one pipeline round trip of the batch writer
+const batchFlushTimeout = 5 * time.Second
+
+// Batch queues the commands of one message on a pipeline. The commands are sent
+// together and report a single error.
+type Batch struct {
+	ctx     context.Context
+	pipe    redis.Pipeliner
+	ttl     time.Duration
+	scripts bool // Commands run scripts, loaded before the pipeline is sent
+}
+
+// Publish queues a PUBLISH of data to channel
+func (b *Batch) Publish(channel string, data []byte) {
+	b.pipe.Publish(b.ctx, channel, data)
+}
+
+// Set queues a SET of key with the live data TTL
+func (b *Batch) Set(key string, data []byte) {
+	b.pipe.Set(b.ctx, key, data, b.ttl)
+}
+
+// AddToStream queues an XADD of an entry with values to stream, trimmed to about maxLen entries
+func (b *Batch) AddToStream(stream string, maxLen int64, values map[string]interface{}) {
+	b.pipe.XAdd(b.ctx, &redis.XAddArgs{
+		Stream: stream,
+		MaxLen: maxLen,
+		Approx: true,
+		Values: values,
+	})
+}
+
+// Pipelined sends the commands queued by fn in one round trip and returns the first error
+func (c *Client) Pipelined(ctx context.Context, fn func(*Batch)) error {
+	batch := &Batch{ctx: ctx, pipe: c.client.Pipeline(), ttl: c.ttl}
+	fn(batch)
+
+	_, err := c.exec(batch)
+	return err
+}
+
+// exec loads the scripts the batch needs, then sends its pipeline
+func (c *Client) exec(batch *Batch) ([]redis.Cmder, error) {
+	if batch.scripts {
+		if err := c.loadScripts(batch.ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	cmds, err := batch.pipe.Exec(batch.ctx)
+	c.checkScripts(cmds)
+	return cmds, err
+}
+
+// batchEntry is a message waiting in the batch writer
+type batchEntry struct {
+	queue func(*Batch)
+	done  func(error)
+}
+
+// BatchWriter collects messages and sends them in pipelines of up to size messages,
+// at least every interval. Each message's done callback receives its own result.
+type BatchWriter struct {
+	client   *Client
+	size     int
+	interval time.Duration
+
+	mu      sync.Mutex
+	pending []batchEntry
+	closed  bool
+
+	flushMu sync.Mutex // Serializes flushes so messages are sent in order
+	stop    chan struct{}
+	stopped chan struct{}
+}
+
+// NewBatchWriter creates a batch writer and starts its flush timer
+func (c *Client) NewBatchWriter(size int, interval time.Duration) *BatchWriter {
+	w := &BatchWriter{
+		client:   c,
+		size:     max(size, 1),
+		interval: interval,
+		pending:  make([]batchEntry, 0, max(size, 1)),
+		stop:     make(chan struct{}),
+		stopped:  make(chan struct{}),
+	}
+
+	go w.run()
+	return w
+}
+
+// Enqueue adds a message whose commands are queued by fn. done, if not nil, is called
+// with the message's result once its pipeline has been sent. When the batch is full the
+// caller sends it, which slows down producers that outpace Redis.
+func (w *BatchWriter) Enqueue(fn func(*Batch), done func(error)) error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return ErrBatchWriterClosed
+	}
+	w.pending = append(w.pending, batchEntry{queue: fn, done: done})
+	full := len(w.pending) >= w.size
+	w.mu.Unlock()
+
+	if full {
+		w.Flush()
+	}
+	return nil
+}
+
+// Flush sends the queued messages
+func (w *BatchWriter) Flush() {
+	w.flushMu.Lock()
+	defer w.flushMu.Unlock()
+
+	w.mu.Lock()
+	entries := w.pending
+	w.pending = make([]batchEntry, 0, w.size)
+	w.mu.Unlock()
+
+	if len(entries) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), batchFlushTimeout)
+	defer cancel()
+
+	// Remember which pipeline commands belong to which message
+	batch := &Batch{ctx: ctx, pipe: w.client.client.Pipeline(), ttl: w.client.ttl}
+	ends := make([]int, len(entries))
+	for i, entry := range entries {
+		entry.queue(batch)
+		ends[i] = batch.pipe.Len()
+	}
+
+	cmds, err := w.client.exec(batch)
+	start := 0
+	for i, entry := range entries {
+		if entry.done == nil {
+			start = ends[i]
+			continue
+		}
+
+		var entryErr error
+		if ends[i] > len(cmds) {
+			// The pipeline failed before returning this message's commands
+			entryErr = err
+			if entryErr == nil {
+				entryErr = errors.New("pipeline returned no result")
+			}
+		} else {
+			for _, cmd := range cmds[start:ends[i]] {
+				if cmdErr := cmd.Err(); cmdErr != nil {
+					entryErr = cmdErr
+					break
+				}
+			}
+		}
+
+		entry.done(entryErr)
+		start = ends[i]
+	}
+}
+
+// Close sends the queued messages and stops the flush timer. Later messages are refused.
+func (w *BatchWriter) Close() {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return
+	}
+	w.closed = true
+	w.mu.Unlock()
+
+	close(w.stop)
+	<-w.stopped
+	w.Flush()
+}
+
+// run flushes every interval until Close
+func (w *BatchWriter) run() {
+	defer close(w.stopped)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			w.Flush()
+		case <-w.stop:
+			return
+		}
+	}
+}
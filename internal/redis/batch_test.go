@@ -0,0 +1,88 @@
+package redis
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBatchWriterPerMessageErrors(t *testing.T) {
+	m, client := testClient(t)
+
+	// XADD to a string key fails with WRONGTYPE
+	m.Set("binance:stream:broken", "not a stream")
+
+	// A long interval, so only the full batch is sent
+	w := client.NewBatchWriter(3, time.Hour)
+	defer w.Close()
+
+	var (
+		mu      sync.Mutex
+		results = map[string]error{}
+		done    sync.WaitGroup
+	)
+	enqueue := func(name string, fn func(*Batch)) {
+		done.Add(1)
+		err := w.Enqueue(fn, func(err error) {
+			mu.Lock()
+			results[name] = err
+			mu.Unlock()
+			done.Done()
+		})
+		if err != nil {
+			t.Fatalf("failed to enqueue %s: %v", name, err)
+		}
+	}
+
+	enqueue("first", func(b *Batch) {
+		b.Publish("binance:ticker:BTCUSDT", []byte("1"))
+		b.Set("binance:latest:ticker:BTCUSDT", []byte("1"))
+	})
+	enqueue("broken", func(b *Batch) {
+		b.Set("binance:latest:ticker:ETHUSDT", []byte("2"))
+		b.AddToStream("binance:stream:broken", 100, map[string]interface{}{StreamDataField: "2"})
+	})
+	enqueue("last", func(b *Batch) {
+		b.Set("binance:latest:ticker:BNBUSDT", []byte("3"))
+	})
+	done.Wait()
+
+	if err := results["broken"]; err == nil || !strings.Contains(err.Error(), "WRONGTYPE") {
+		t.Errorf("got error %v for the broken message, want WRONGTYPE", err)
+	}
+	for _, name := range []string{"first", "last"} {
+		if err := results[name]; err != nil {
+			t.Errorf("got error %v for the %s message, want none", err, name)
+		}
+	}
+
+	// The other commands were still applied
+	for _, key := range []string{"binance:latest:ticker:BTCUSDT", "binance:latest:ticker:ETHUSDT", "binance:latest:ticker:BNBUSDT"} {
+		if !m.Exists(key) {
+			t.Errorf("%s was not set", key)
+		}
+	}
+}
+
+func TestBatchWriterClose(t *testing.T) {
+	m, client := testClient(t)
+
+	w := client.NewBatchWriter(100, time.Hour)
+
+	var got error = errors.New("not sent")
+	if err := w.Enqueue(func(b *Batch) { b.Set("binance:latest:depth:BTCUSDT", []byte("1")) }, func(err error) { got = err }); err != nil {
+		t.Fatalf("failed to enqueue: %v", err)
+	}
+
+	// Close sends what is queued, then refuses more
+	w.Close()
+	if got != nil || !m.Exists("binance:latest:depth:BTCUSDT") {
+		t.Errorf("queued message was not sent on Close: %v", got)
+	}
+	if err := w.Enqueue(func(b *Batch) {}, nil); !errors.Is(err, ErrBatchWriterClosed) {
+		t.Errorf("got error %v after Close, want ErrBatchWriterClosed", err)
+	}
+	w.Close()
+}
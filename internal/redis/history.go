@@ -0,0 +1,72 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// historyScript adds a message to a capped history, a sorted set ordered by score. A
+// message with the same score replaces the previous one, then the lowest scores beyond
+// the cap are removed, all atomically.
+//
+// KEYS[1]: history key, ARGV[1]: score, ARGV[2]: message, ARGV[3]: messages kept
+var historyScript = redis.NewScript(`
+redis.call('ZREMRANGEBYSCORE', KEYS[1], ARGV[1], ARGV[1])
+redis.call('ZADD', KEYS[1], ARGV[1], ARGV[2])
+redis.call('ZREMRANGEBYRANK', KEYS[1], 0, -tonumber(ARGV[3]) - 1)
+return 0
+`)
+
+// AddToHistory queues adding data to the history at key with score, keeping the maxLen
+// highest scores. The script is sent by its hash, loaded before the pipeline runs.
+func (b *Batch) AddToHistory(key string, score int64, maxLen int64, data []byte) {
+	b.scripts = true
+	historyScript.EvalSha(b.ctx, b.pipe, []string{key}, score, data, maxLen)
+}
+
+// loadScripts loads the history script into Redis unless it is already loaded
+func (c *Client) loadScripts(ctx context.Context) error {
+	if c.scriptsLoaded.Load() {
+		return nil
+	}
+
+	if err := historyScript.Load(ctx, c.client).Err(); err != nil {
+		return fmt.Errorf("failed to load history script: %w", err)
+	}
+	c.scriptsLoaded.Store(true)
+
+	return nil
+}
+
+// checkScripts notes when Redis no longer knows the scripts, e.g. after a restart, so
+// the next pipeline loads them again. The commands that failed are not retried.
+func (c *Client) checkScripts(cmds []redis.Cmder) {
+	for _, cmd := range cmds {
+		if err := cmd.Err(); err != nil && redis.HasErrorPrefix(err, "NOSCRIPT") {
+			c.scriptsLoaded.Store(false)
+			return
+		}
+	}
+}
+
+// GetHistory returns up to limit of the newest messages of the history at key, oldest
+// first. It returns no messages when the history does not exist.
+func (c *Client) GetHistory(ctx context.Context, key string, limit int64) ([][]byte, error) {
+	if limit <= 0 {
+		return nil, nil
+	}
+
+	values, err := c.client.ZRange(ctx, key, -limit, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history from Redis: %w", err)
+	}
+
+	result := make([][]byte, len(values))
+	for i, value := range values {
+		result[i] = []byte(value)
+	}
+
+	return result, nil
+}
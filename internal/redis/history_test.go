@@ -0,0 +1,100 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+// addToHistory adds messages by score to the history at key, capped at maxLen
+func addToHistory(t *testing.T, client *Client, key string, maxLen int64, scores []int64, suffix string) {
+	t.Helper()
+
+	err := client.Pipelined(context.Background(), func(b *Batch) {
+		for _, score := range scores {
+			b.AddToHistory(key, score, maxLen, []byte(fmt.Sprintf("%d%s", score, suffix)))
+		}
+	})
+	if err != nil {
+		t.Fatalf("failed to add to history: %v", err)
+	}
+}
+
+// readHistory returns the newest limit messages of the history at key as strings
+func readHistory(t *testing.T, client *Client, key string, limit int64) []string {
+	t.Helper()
+
+	values, err := client.GetHistory(context.Background(), key, limit)
+	if err != nil {
+		t.Fatalf("failed to read history: %v", err)
+	}
+
+	result := make([]string, len(values))
+	for i, value := range values {
+		result[i] = string(value)
+	}
+	return result
+}
+
+func TestHistory(t *testing.T) {
+	const key = "binance:history:kline:BTCUSDT:1m"
+
+	tests := []struct {
+		name    string
+		scores  []int64 // Added in order, as "<score>"
+		updates []int64 // Then added again, as "<score>b"
+		limit   int64
+		want    []string
+	}{
+		{"oldest first", []int64{3, 1, 2}, nil, 10, []string{"1", "2", "3"}},
+		{"capped to the highest scores", []int64{1, 2, 3, 4, 5, 6}, nil, 10, []string{"3", "4", "5", "6"}},
+		{"late message below the cap dropped", []int64{5, 6, 7, 8, 1}, nil, 10, []string{"5", "6", "7", "8"}},
+		{"same score replaced", []int64{1, 2, 3}, []int64{2, 3}, 10, []string{"1", "2b", "3b"}},
+		{"limit keeps the newest", []int64{1, 2, 3, 4}, nil, 2, []string{"3", "4"}},
+		{"no limit", []int64{1, 2}, nil, 0, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, client := testClient(t)
+
+			addToHistory(t, client, key, 4, tt.scores, "")
+			addToHistory(t, client, key, 4, tt.updates, "b")
+
+			got := readHistory(t, client, key, tt.limit)
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("got history %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHistoryMissing(t *testing.T) {
+	_, client := testClient(t)
+
+	if got := readHistory(t, client, "binance:history:trade:BTCUSDT", 10); len(got) != 0 {
+		t.Errorf("got history %v for a missing key, want none", got)
+	}
+}
+
+func TestHistoryScriptReloaded(t *testing.T) {
+	const key = "binance:history:trade:BTCUSDT"
+
+	_, client := testClient(t)
+	addToHistory(t, client, key, 10, []int64{1}, "")
+
+	// Redis forgets the loaded scripts on a restart; the pipeline then fails once, and
+	// the next one loads the script again
+	if err := client.client.ScriptFlush(context.Background()).Err(); err != nil {
+		t.Fatalf("failed to flush scripts: %v", err)
+	}
+	err := client.Pipelined(context.Background(), func(b *Batch) { b.AddToHistory(key, 2, 10, []byte("2")) })
+	if err == nil {
+		t.Fatal("got no error for an unknown script, want NOSCRIPT")
+	}
+
+	addToHistory(t, client, key, 10, []int64{3}, "")
+	if got := readHistory(t, client, key, 10); fmt.Sprint(got) != "[1 3]" {
+		t.Errorf("got history %v, want [1 3]", got)
+	}
+}
@@ -3,7 +3,9 @@ package redis
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"sync/atomic"
 	"time"
 
 	"github.com/binance-live/internal/config"
@@ -12,11 +14,16 @@ import (
 	"google.golang.org/protobuf/proto"
 )
 
+// ErrKeyNotFound is returned when a requested key does not exist
+var ErrKeyNotFound = errors.New("key not found")
+
 // Client wraps the Redis client
 type Client struct {
 	client *redis.Client
 	logger *zap.Logger
 	ttl    time.Duration
+
+	scriptsLoaded atomic.Bool // The history script is known to Redis
 }
 
 // New creates a new Redis client
@@ -80,6 +87,12 @@ func (c *Client) PublishProtobuf(ctx context.Context, channel string, data proto
 	return nil
 }
 
+// Subscribe opens a pub/sub connection listening on channels. More channels can be
+// added and removed on the returned PubSub, which the caller must close.
+func (c *Client) Subscribe(ctx context.Context, channels ...string) *redis.PubSub {
+	return c.client.Subscribe(ctx, channels...)
+}
+
 // SetJSON sets a key with JSON value and TTL
 func (c *Client) SetJSON(ctx context.Context, key string, data interface{}, ttl time.Duration) error {
 	jsonData, err := json.Marshal(data)
@@ -121,7 +134,7 @@ func (c *Client) GetJSON(ctx context.Context, key string, dest interface{}) erro
 	val, err := c.client.Get(ctx, key).Result()
 	if err != nil {
 		if err == redis.Nil {
-			return fmt.Errorf("key not found: %s", key)
+			return fmt.Errorf("%w: %s", ErrKeyNotFound, key)
 		}
 		return fmt.Errorf("failed to get key from Redis: %w", err)
 	}
@@ -138,7 +151,7 @@ func (c *Client) GetProtobuf(ctx context.Context, key string, dest proto.Message
 	val, err := c.client.Get(ctx, key).Result()
 	if err != nil {
 		if err == redis.Nil {
-			return fmt.Errorf("key not found: %s", key)
+			return fmt.Errorf("%w: %s", ErrKeyNotFound, key)
 		}
 		return fmt.Errorf("failed to get key from Redis: %w", err)
 	}
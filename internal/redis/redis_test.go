@@ -0,0 +1,28 @@
+package redis
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/binance-live/internal/config"
+	"go.uber.org/zap"
+)
+
+// testClient starts an in-memory Redis server and connects a client to it
+func testClient(t *testing.T) (*miniredis.Miniredis, *Client) {
+	t.Helper()
+
+	m := miniredis.RunT(t)
+	port, err := strconv.Atoi(m.Port())
+	if err != nil {
+		t.Fatalf("invalid miniredis port: %v", err)
+	}
+	client, err := New(&config.RedisConfig{Host: m.Host(), Port: port, PoolSize: 2, LiveDataTTL: 60}, zap.NewNop())
+	if err != nil {
+		t.Fatalf("failed to connect to miniredis: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+
+	return m, client
+}
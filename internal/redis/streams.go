@@ -0,0 +1,206 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/protobuf/proto"
+)
+
+// Fields of a live data stream entry
+const (
+	StreamDataField    = "data"    // The encoded message
+	StreamFormatField  = "format"  // Its wire format, protobuf when missing
+	StreamVersionField = "version" // Its schema version
+)
+
+// AddProtobufToStream appends a protobuf message to a stream, trimming it to about
+// maxLen entries (MAXLEN ~), and returns the entry ID
+func (c *Client) AddProtobufToStream(ctx context.Context, stream string, maxLen int64, data proto.Message) (string, error) {
+	protoData, err := proto.Marshal(data)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal protobuf data: %w", err)
+	}
+
+	id, err := c.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: stream,
+		MaxLen: maxLen,
+		Approx: true,
+		Values: map[string]interface{}{StreamDataField: protoData},
+	}).Result()
+	if err != nil {
+		return "", fmt.Errorf("failed to add to Redis stream: %w", err)
+	}
+
+	return id, nil
+}
+
+// ReadStream reads up to count entries of a stream after afterID ("0" for the start),
+// waiting up to block for new ones (0: do not wait). It returns no entries on timeout.
+func (c *Client) ReadStream(ctx context.Context, stream, afterID string, count int64, block time.Duration) ([]redis.XMessage, error) {
+	if block <= 0 {
+		block = -1 // Without BLOCK
+	}
+
+	streams, err := c.client.XRead(ctx, &redis.XReadArgs{
+		Streams: []string{stream, afterID},
+		Count:   count,
+		Block:   block,
+	}).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read Redis stream: %w", err)
+	}
+
+	if len(streams) == 0 {
+		return nil, nil
+	}
+	return streams[0].Messages, nil
+}
+
+// CreateStreamGroup creates a consumer group on a stream, creating the stream too if
+// needed. The group starts reading after startID ("$" for new entries only, "0" for
+// all). An existing group is left as is.
+func (c *Client) CreateStreamGroup(ctx context.Context, stream, group, startID string) error {
+	err := c.client.XGroupCreateMkStream(ctx, stream, group, startID).Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return fmt.Errorf("failed to create Redis stream group: %w", err)
+	}
+
+	return nil
+}
+
+// ReadStreamGroup reads up to count entries per stream for a consumer of group. With
+// id ">" it reads entries never delivered to the group, waiting up to block; with "0"
+// it reads the consumer's own pending entries. It returns no entries on timeout.
+func (c *Client) ReadStreamGroup(
+	ctx context.Context,
+	group, consumer string,
+	streams []string,
+	id string,
+	count int64,
+	block time.Duration,
+) ([]redis.XStream, error) {
+	if block <= 0 {
+		block = -1 // Without BLOCK
+	}
+
+	args := make([]string, 0, 2*len(streams))
+	args = append(args, streams...)
+	for range streams {
+		args = append(args, id)
+	}
+
+	result, err := c.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+		Group:    group,
+		Consumer: consumer,
+		Streams:  args,
+		Count:    count,
+		Block:    block,
+	}).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read Redis stream group: %w", err)
+	}
+
+	return result, nil
+}
+
+// AckStream acknowledges processed entries of a stream for group
+func (c *Client) AckStream(ctx context.Context, stream, group string, ids ...string) error {
+	if err := c.client.XAck(ctx, stream, group, ids...).Err(); err != nil {
+		return fmt.Errorf("failed to ack Redis stream entries: %w", err)
+	}
+
+	return nil
+}
+
+// AckStream queues an XACK of entries of a stream for group
+func (b *Batch) AckStream(stream, group string, ids ...string) {
+	b.pipe.XAck(b.ctx, stream, group, ids...)
+}
+
+// StreamDeliveryCounts returns how many times each of ids, pending for consumer of
+// group, has been delivered, from XPENDING. IDs no longer pending are left out.
+func (c *Client) StreamDeliveryCounts(ctx context.Context, stream, group, consumer string, ids []string) (map[string]int64, error) {
+	counts := make(map[string]int64, len(ids))
+	if len(ids) == 0 {
+		return counts, nil
+	}
+
+	// The IDs are ascending; the consumer may have other entries pending between them
+	start, end := ids[0], ids[len(ids)-1]
+	for {
+		pending, err := c.client.XPendingExt(ctx, &redis.XPendingExtArgs{
+			Stream:   stream,
+			Group:    group,
+			Start:    start,
+			End:      end,
+			Count:    int64(len(ids)),
+			Consumer: consumer,
+		}).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read pending Redis stream entries: %w", err)
+		}
+
+		for _, entry := range pending {
+			counts[entry.ID] = entry.RetryCount
+		}
+		if len(pending) < len(ids) {
+			return counts, nil
+		}
+
+		start, err = nextStreamID(pending[len(pending)-1].ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// nextStreamID returns the stream entry ID following id
+func nextStreamID(id string) (string, error) {
+	ms, seq, ok := strings.Cut(id, "-")
+	if !ok {
+		return "", fmt.Errorf("invalid stream entry ID %q", id)
+	}
+	n, err := strconv.ParseUint(seq, 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("invalid stream entry ID %q: %w", id, err)
+	}
+
+	return ms + "-" + strconv.FormatUint(n+1, 10), nil
+}
+
+// ClaimStreamPending transfers to consumer up to count entries of group pending for at
+// least minIdle, scanning from start ("0-0" for the beginning). It returns the claimed
+// entries and the start of the next scan, "0-0" when the scan is complete.
+func (c *Client) ClaimStreamPending(
+	ctx context.Context,
+	stream, group, consumer string,
+	minIdle time.Duration,
+	start string,
+	count int64,
+) ([]redis.XMessage, string, error) {
+	messages, next, err := c.client.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+		Stream:   stream,
+		Group:    group,
+		Consumer: consumer,
+		MinIdle:  minIdle,
+		Start:    start,
+		Count:    count,
+	}).Result()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to claim pending Redis stream entries: %w", err)
+	}
+
+	return messages, next, nil
+}
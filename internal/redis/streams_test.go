@@ -0,0 +1,77 @@
+package redis
+
+import (
+	"context"
+	"testing"
+)
+
+func TestStreamDeliveryCounts(t *testing.T) {
+	_, client := testClient(t)
+	ctx := context.Background()
+	const stream, group = "binance:stream:trade:BTCUSDT", "indicators"
+
+	if err := client.CreateStreamGroup(ctx, stream, group, "0"); err != nil {
+		t.Fatalf("failed to create group: %v", err)
+	}
+	var ids []string
+	for i := 0; i < 6; i++ {
+		id, err := client.AddProtobufToStream(ctx, stream, 100, nil)
+		if err != nil {
+			t.Fatalf("failed to add to stream: %v", err)
+		}
+		ids = append(ids, id)
+	}
+
+	// Deliver all entries to a, acknowledge the last, then deliver the ones after the
+	// third once more
+	if _, err := client.ReadStreamGroup(ctx, group, "a", []string{stream}, ">", 10, 0); err != nil {
+		t.Fatalf("failed to read stream group: %v", err)
+	}
+	if err := client.AckStream(ctx, stream, group, ids[5]); err != nil {
+		t.Fatalf("failed to ack: %v", err)
+	}
+	if _, err := client.ReadStreamGroup(ctx, group, "a", []string{stream}, ids[2], 10, 0); err != nil {
+		t.Fatalf("failed to read pending entries: %v", err)
+	}
+
+	// Other pending entries lie between the IDs asked for, so XPENDING is read in pages
+	counts, err := client.StreamDeliveryCounts(ctx, stream, group, "a", []string{ids[0], ids[4], ids[5]})
+	if err != nil {
+		t.Fatalf("failed to read delivery counts: %v", err)
+	}
+
+	want := map[string]int64{ids[0]: 1, ids[1]: 1, ids[2]: 1, ids[3]: 2, ids[4]: 2}
+	for id, count := range want {
+		if counts[id] != count {
+			t.Errorf("got %d deliveries of %s, want %d", counts[id], id, count)
+		}
+	}
+	if _, ok := counts[ids[5]]; ok {
+		t.Errorf("got deliveries of the acknowledged entry %s", ids[5])
+	}
+
+	// Other consumers' entries are left out
+	counts, err = client.StreamDeliveryCounts(ctx, stream, group, "b", ids)
+	if err != nil || len(counts) != 0 {
+		t.Errorf("got counts %v (%v) for a consumer without entries, want none", counts, err)
+	}
+}
+
+func TestNextStreamID(t *testing.T) {
+	tests := []struct {
+		id, want string
+	}{
+		{"1704067200000-0", "1704067200000-1"},
+		{"1704067200000-9", "1704067200000-10"},
+	}
+
+	for _, tt := range tests {
+		got, err := nextStreamID(tt.id)
+		if err != nil || got != tt.want {
+			t.Errorf("nextStreamID(%q) = %q, %v, want %q", tt.id, got, err, tt.want)
+		}
+	}
+	if _, err := nextStreamID("1704067200000"); err == nil {
+		t.Error("got no error for an ID without a sequence")
+	}
+}
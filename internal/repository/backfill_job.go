@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/binance-live/internal/database"
+	"github.com/binance-live/internal/db"
+	"github.com/binance-live/internal/models"
+)
+
+// BackfillJobRepository handles range backfill checkpoints
+type BackfillJobRepository struct {
+	database *database.Database
+	queries  *db.Queries
+}
+
+// NewBackfillJobRepository creates a new backfill job repository
+func NewBackfillJobRepository(database *database.Database) *BackfillJobRepository {
+	return &BackfillJobRepository{
+		database: database,
+		queries:  db.New(database.Pool),
+	}
+}
+
+// GetOrCreate returns the job for the given range, creating it if needed.
+// An existing unfinished job is marked running again so it can be resumed.
+func (r *BackfillJobRepository) GetOrCreate(
+	ctx context.Context,
+	symbol, interval string,
+	rangeStart, rangeEnd int64,
+) (*models.BackfillJob, error) {
+	dbJob, err := r.queries.UpsertBackfillJob(ctx, db.UpsertBackfillJobParams{
+		Symbol:     symbol,
+		Interval:   interval,
+		RangeStart: rangeStart,
+		RangeEnd:   rangeEnd,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to upsert backfill job: %w", err)
+	}
+
+	job := &models.BackfillJob{
+		ID:             dbJob.ID,
+		Symbol:         dbJob.Symbol,
+		Interval:       dbJob.Interval,
+		RangeStart:     dbJob.RangeStart,
+		RangeEnd:       dbJob.RangeEnd,
+		CursorTime:     dbJob.CursorTime,
+		KlinesFetched:  dbJob.KlinesFetched,
+		WindowsSkipped: int(dbJob.WindowsSkipped),
+		Status:         dbJob.Status,
+		CreatedAt:      dbJob.CreatedAt,
+		UpdatedAt:      dbJob.UpdatedAt,
+	}
+
+	if dbJob.ErrorMessage.Valid {
+		job.ErrorMessage = &dbJob.ErrorMessage.String
+	}
+
+	return job, nil
+}
+
+// Reset rewinds a job to the start of its range
+func (r *BackfillJobRepository) Reset(ctx context.Context, job *models.BackfillJob) error {
+	if err := r.queries.ResetBackfillJob(ctx, job.ID); err != nil {
+		return fmt.Errorf("failed to reset backfill job: %w", err)
+	}
+
+	job.CursorTime = job.RangeStart
+	job.KlinesFetched = 0
+	job.WindowsSkipped = 0
+	job.Status = "running"
+	job.ErrorMessage = nil
+
+	return nil
+}
+
+// SaveProgress persists the job checkpoint
+func (r *BackfillJobRepository) SaveProgress(ctx context.Context, job *models.BackfillJob) error {
+	err := r.queries.UpdateBackfillJobProgress(ctx, db.UpdateBackfillJobProgressParams{
+		ID:             job.ID,
+		CursorTime:     job.CursorTime,
+		KlinesFetched:  job.KlinesFetched,
+		WindowsSkipped: int32(job.WindowsSkipped),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save backfill progress: %w", err)
+	}
+
+	return nil
+}
+
+// Finish marks a job as completed, or failed when errorMessage is not nil
+func (r *BackfillJobRepository) Finish(ctx context.Context, job *models.BackfillJob, errorMessage *string) error {
+	status := "completed"
+	var errorMessageParam sql.NullString
+	if errorMessage != nil {
+		status = "failed"
+		errorMessageParam = sql.NullString{String: *errorMessage, Valid: true}
+	}
+
+	err := r.queries.FinishBackfillJob(ctx, db.FinishBackfillJobParams{
+		ID:           job.ID,
+		Status:       status,
+		ErrorMessage: errorMessageParam,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to finish backfill job: %w", err)
+	}
+
+	job.Status = status
+	job.ErrorMessage = errorMessage
+
+	return nil
+}
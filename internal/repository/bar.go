@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/binance-live/internal/database"
+	"github.com/binance-live/internal/db"
+	"github.com/binance-live/internal/models"
+)
+
+// BarRepository handles bars built from the aggTrade stream
+type BarRepository struct {
+	database *database.Database
+	queries  *db.Queries
+}
+
+// NewBarRepository creates a new bar repository
+func NewBarRepository(database *database.Database) *BarRepository {
+	return &BarRepository{
+		database: database,
+		queries:  db.New(database.Pool),
+	}
+}
+
+// Insert stores a closed bar. Bars that are already stored are left unchanged.
+func (r *BarRepository) Insert(ctx context.Context, bar *models.Bar) error {
+	err := r.queries.InsertBar(ctx, db.InsertBarParams{
+		Symbol:              bar.Symbol,
+		Spec:                bar.Spec,
+		OpenTime:            bar.OpenTime,
+		CloseTime:           bar.CloseTime,
+		OpenPrice:           bar.OpenPrice,
+		HighPrice:           bar.HighPrice,
+		LowPrice:            bar.LowPrice,
+		ClosePrice:          bar.ClosePrice,
+		Volume:              bar.Volume,
+		QuoteVolume:         bar.QuoteVolume,
+		TradesCount:         int32(bar.TradesCount),
+		TakerBuyVolume:      bar.TakerBuyVolume,
+		TakerBuyQuoteVolume: bar.TakerBuyQuoteVolume,
+		FirstTradeID:        bar.FirstTradeID,
+		LastTradeID:         bar.LastTradeID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to insert bar: %w", err)
+	}
+
+	return nil
+}
+
+// GetBarsByTimeRange retrieves the bars of symbol and spec opened in [startTime, endTime)
+func (r *BarRepository) GetBarsByTimeRange(ctx context.Context, symbol, spec string, startTime, endTime int64) ([]models.Bar, error) {
+	dbBars, err := r.queries.GetBarsByTimeRange(ctx, db.GetBarsByTimeRangeParams{
+		Symbol:     symbol,
+		Spec:       spec,
+		OpenTime:   startTime,
+		OpenTime_2: endTime,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query bars: %w", err)
+	}
+
+	bars := make([]models.Bar, 0, len(dbBars))
+	for _, dbBar := range dbBars {
+		bars = append(bars, models.Bar{
+			Symbol:              dbBar.Symbol,
+			Spec:                dbBar.Spec,
+			OpenTime:            dbBar.OpenTime,
+			CloseTime:           dbBar.CloseTime,
+			OpenPrice:           dbBar.OpenPrice,
+			HighPrice:           dbBar.HighPrice,
+			LowPrice:            dbBar.LowPrice,
+			ClosePrice:          dbBar.ClosePrice,
+			Volume:              dbBar.Volume,
+			QuoteVolume:         dbBar.QuoteVolume,
+			TradesCount:         int(dbBar.TradesCount),
+			TakerBuyVolume:      dbBar.TakerBuyVolume,
+			TakerBuyQuoteVolume: dbBar.TakerBuyQuoteVolume,
+			FirstTradeID:        dbBar.FirstTradeID,
+			LastTradeID:         dbBar.LastTradeID,
+			CreatedAt:           dbBar.CreatedAt,
+		})
+	}
+
+	return bars, nil
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/binance-live/internal/database"
+	"github.com/jackc/pgx/v5"
+)
+
+// bulkInsert loads rows into table through a temporary staging table with COPY,
+// then moves them into the target table skipping rows that violate conflictKey.
+// It returns the number of rows actually inserted.
+func bulkInsert(
+	ctx context.Context,
+	database *database.Database,
+	table string,
+	columns []string,
+	conflictKey string,
+	rows [][]any,
+) (int64, error) {
+	if len(rows) == 0 {
+		return 0, nil
+	}
+
+	tx, err := database.Pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(context.Background())
+
+	staging := table + "_staging"
+	createSQL := fmt.Sprintf(
+		"CREATE TEMP TABLE %s (LIKE %s INCLUDING DEFAULTS) ON COMMIT DROP",
+		staging, table,
+	)
+	if _, err := tx.Exec(ctx, createSQL); err != nil {
+		return 0, fmt.Errorf("failed to create staging table: %w", err)
+	}
+
+	if _, err := tx.CopyFrom(ctx, pgx.Identifier{staging}, columns, pgx.CopyFromRows(rows)); err != nil {
+		return 0, fmt.Errorf("failed to copy rows: %w", err)
+	}
+
+	columnList := strings.Join(columns, ", ")
+	insertSQL := fmt.Sprintf(
+		"INSERT INTO %s (%s) SELECT %s FROM %s ON CONFLICT (%s) DO NOTHING",
+		table, columnList, columnList, staging, conflictKey,
+	)
+	tag, err := tx.Exec(ctx, insertSQL)
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert from staging table: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
@@ -15,6 +15,7 @@ import (
 type KlineRepository struct {
 	database *database.Database
 	queries  *db.Queries
+	derived  map[string]DerivedKlineView
 }
 
 // NewKlineRepository creates a new kline repository
@@ -147,6 +148,10 @@ func (r *KlineRepository) executeBatchInsert(ctx context.Context, klines []model
 
 // GetLastKline retrieves the most recent kline for a symbol and interval
 func (r *KlineRepository) GetLastKline(ctx context.Context, symbol, interval string) (*models.Kline, error) {
+	if view, ok := r.derivedView(interval); ok {
+		return r.getLastDerived(ctx, view, symbol)
+	}
+
 	dbKline, err := r.queries.GetLastKline(ctx, db.GetLastKlineParams{
 		Symbol:   symbol,
 		Interval: interval,
@@ -158,22 +163,8 @@ func (r *KlineRepository) GetLastKline(ctx context.Context, symbol, interval str
 		return nil, fmt.Errorf("failed to get last kline: %w", err)
 	}
 
-	return &models.Kline{
-		Symbol:              dbKline.Symbol,
-		Interval:            dbKline.Interval,
-		OpenTime:            dbKline.OpenTime,
-		CloseTime:           dbKline.CloseTime,
-		OpenPrice:           dbKline.OpenPrice,
-		HighPrice:           dbKline.HighPrice,
-		LowPrice:            dbKline.LowPrice,
-		ClosePrice:          dbKline.ClosePrice,
-		Volume:              dbKline.Volume,
-		QuoteVolume:         dbKline.QuoteVolume,
-		TradesCount:         int(dbKline.TradesCount),
-		TakerBuyVolume:      dbKline.TakerBuyVolume,
-		TakerBuyQuoteVolume: dbKline.TakerBuyQuoteVolume,
-		CreatedAt:           dbKline.CreatedAt,
-	}, nil
+	kline := convertKline(dbKline)
+	return &kline, nil
 }
 
 // GetKlinesByTimeRange retrieves klines within a time range
@@ -182,6 +173,10 @@ func (r *KlineRepository) GetKlinesByTimeRange(
 	symbol, interval string,
 	startTime, endTime int64,
 ) ([]models.Kline, error) {
+	if view, ok := r.derivedView(interval); ok {
+		return r.getDerivedByTimeRange(ctx, view, symbol, startTime, endTime)
+	}
+
 	dbKlines, err := r.queries.GetKlinesByTimeRange(ctx, db.GetKlinesByTimeRangeParams{
 		Symbol:     symbol,
 		Interval:   interval,
@@ -194,23 +189,88 @@ func (r *KlineRepository) GetKlinesByTimeRange(
 
 	klines := make([]models.Kline, 0, len(dbKlines))
 	for _, dbKline := range dbKlines {
-		klines = append(klines, models.Kline{
-			Symbol:              dbKline.Symbol,
-			Interval:            dbKline.Interval,
-			OpenTime:            dbKline.OpenTime,
-			CloseTime:           dbKline.CloseTime,
-			OpenPrice:           dbKline.OpenPrice,
-			HighPrice:           dbKline.HighPrice,
-			LowPrice:            dbKline.LowPrice,
-			ClosePrice:          dbKline.ClosePrice,
-			Volume:              dbKline.Volume,
-			QuoteVolume:         dbKline.QuoteVolume,
-			TradesCount:         int(dbKline.TradesCount),
-			TakerBuyVolume:      dbKline.TakerBuyVolume,
-			TakerBuyQuoteVolume: dbKline.TakerBuyQuoteVolume,
-			CreatedAt:           dbKline.CreatedAt,
-		})
+		klines = append(klines, convertKline(dbKline))
 	}
 
 	return klines, nil
 }
+
+// CountByTimeRange counts stored klines with open times in [startTime, endTime)
+func (r *KlineRepository) CountByTimeRange(
+	ctx context.Context,
+	symbol, interval string,
+	startTime, endTime int64,
+) (int64, error) {
+	if view, ok := r.derivedView(interval); ok {
+		return r.countDerived(ctx, view, symbol, startTime, endTime)
+	}
+
+	count, err := r.queries.CountKlinesByTimeRange(ctx, db.CountKlinesByTimeRangeParams{
+		Symbol:     symbol,
+		Interval:   interval,
+		OpenTime:   startTime,
+		OpenTime_2: endTime,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to count klines: %w", err)
+	}
+
+	return count, nil
+}
+
+// klineColumns lists the klines columns written by BulkInsert
+var klineColumns = []string{
+	"symbol", "interval", "open_time", "close_time", "open_price", "high_price",
+	"low_price", "close_price", "volume", "quote_volume", "trades_count",
+	"taker_buy_volume", "taker_buy_quote_volume",
+}
+
+// BulkInsert loads klines with COPY, skipping candles that are already stored.
+// It is intended for large historical imports and returns the number of new rows.
+func (r *KlineRepository) BulkInsert(ctx context.Context, klines []models.Kline) (int64, error) {
+	rows := make([][]any, 0, len(klines))
+	for _, kline := range klines {
+		rows = append(rows, []any{
+			kline.Symbol,
+			kline.Interval,
+			kline.OpenTime,
+			kline.CloseTime,
+			kline.OpenPrice,
+			kline.HighPrice,
+			kline.LowPrice,
+			kline.ClosePrice,
+			kline.Volume,
+			kline.QuoteVolume,
+			int32(kline.TradesCount),
+			kline.TakerBuyVolume,
+			kline.TakerBuyQuoteVolume,
+		})
+	}
+
+	inserted, err := bulkInsert(ctx, r.database, "klines", klineColumns, "symbol, interval, open_time", rows)
+	if err != nil {
+		return 0, fmt.Errorf("failed to bulk insert klines: %w", err)
+	}
+
+	return inserted, nil
+}
+
+// convertKline converts a klines row to the model
+func convertKline(dbKline db.Kline) models.Kline {
+	return models.Kline{
+		Symbol:              dbKline.Symbol,
+		Interval:            dbKline.Interval,
+		OpenTime:            dbKline.OpenTime,
+		CloseTime:           dbKline.CloseTime,
+		OpenPrice:           dbKline.OpenPrice,
+		HighPrice:           dbKline.HighPrice,
+		LowPrice:            dbKline.LowPrice,
+		ClosePrice:          dbKline.ClosePrice,
+		Volume:              dbKline.Volume,
+		QuoteVolume:         dbKline.QuoteVolume,
+		TradesCount:         int(dbKline.TradesCount),
+		TakerBuyVolume:      dbKline.TakerBuyVolume,
+		TakerBuyQuoteVolume: dbKline.TakerBuyQuoteVolume,
+		CreatedAt:           dbKline.CreatedAt,
+	}
+}
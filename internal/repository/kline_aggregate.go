@@ -0,0 +1,245 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/binance-live/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// DerivedKlineView describes a TimescaleDB continuous aggregate that derives one kline
+// interval from the stored candles of a finer base interval
+type DerivedKlineView struct {
+	Interval     string // Derived interval, e.g. "1h"
+	BaseInterval string // Stored interval it is built from, e.g. "1m"
+	Width        int64  // Bucket width in milliseconds
+	BaseWidth    int64  // Base candle width in milliseconds
+	Offset       int64  // Bucket origin offset in milliseconds (weekly candles start on Monday)
+}
+
+// Name returns the name of the continuous aggregate
+func (v DerivedKlineView) Name() string {
+	return fmt.Sprintf("klines_%s_from_%s", v.Interval, v.BaseInterval)
+}
+
+// CandlesPerBucket returns how many base candles make up a complete derived candle
+func (v DerivedKlineView) CandlesPerBucket() int64 {
+	return v.Width / v.BaseWidth
+}
+
+// source selects the buckets of the continuous aggregate built from all their base
+// candles, and the bucket still in progress. Buckets missing base candles, at a gap or
+// the start of retention, would read as complete candles with wrong prices and volumes.
+func (v DerivedKlineView) source() string {
+	return fmt.Sprintf(`(SELECT * FROM %s WHERE candle_count = %d OR bucket + %d > unix_now_ms()) complete`,
+		pgx.Identifier{v.Name()}.Sanitize(), v.CandlesPerBucket(), v.Width)
+}
+
+// derivedViewSQL creates a continuous aggregate with Binance kline semantics: the open is the
+// first base open, the close the last base close, and volumes and trade counts are summed.
+// Real-time aggregation is enabled so the newest buckets are visible before materialization.
+const derivedViewSQL = `
+CREATE MATERIALIZED VIEW IF NOT EXISTS %[1]s
+WITH (timescaledb.continuous, timescaledb.materialized_only = false) AS
+SELECT symbol,
+       time_bucket(%[2]d::BIGINT, open_time, %[3]d::BIGINT) AS bucket,
+` + derivedAggregates + `
+FROM klines
+WHERE interval = %[4]s
+GROUP BY symbol, time_bucket(%[2]d::BIGINT, open_time, %[3]d::BIGINT)
+WITH NO DATA
+`
+
+// derivedAggregates folds base candles into one derived candle. It is shared by the
+// continuous aggregates and by ResampleKlines.
+const derivedAggregates = `       first(open_price, open_time) AS open_price,
+       max(high_price) AS high_price,
+       min(low_price) AS low_price,
+       last(close_price, open_time) AS close_price,
+       sum(volume) AS volume,
+       sum(quote_volume) AS quote_volume,
+       sum(trades_count)::BIGINT AS trades_count,
+       sum(taker_buy_volume) AS taker_buy_volume,
+       sum(taker_buy_quote_volume) AS taker_buy_quote_volume,
+       count(*) AS candle_count`
+
+// derivedColumns selects a derived view row in the column order of models.Kline
+const derivedColumns = `symbol, bucket, bucket + %d - 1, open_price, high_price, low_price, close_price,
+       volume, quote_volume, trades_count, taker_buy_volume, taker_buy_quote_volume`
+
+// UseDerivedViews makes the read methods serve the given intervals from their continuous
+// aggregates, so callers query derived and native intervals the same way
+func (r *KlineRepository) UseDerivedViews(views []DerivedKlineView) {
+	r.derived = make(map[string]DerivedKlineView, len(views))
+	for _, view := range views {
+		r.derived[view.Interval] = view
+	}
+}
+
+// derivedView returns the continuous aggregate serving interval, if any
+func (r *KlineRepository) derivedView(interval string) (DerivedKlineView, bool) {
+	view, ok := r.derived[interval]
+	return view, ok
+}
+
+// EnsureDerivedView creates the continuous aggregate for view and its refresh policy.
+// The policy keeps the last few buckets materialized; use RefreshDerivedView after
+// backfilling older base candles.
+func (r *KlineRepository) EnsureDerivedView(ctx context.Context, view DerivedKlineView) error {
+	if err := setIntegerNowFunc(ctx, r.database, "klines"); err != nil {
+		return err
+	}
+
+	name := pgx.Identifier{view.Name()}.Sanitize()
+	createSQL := fmt.Sprintf(derivedViewSQL, name, view.Width, view.Offset, quoteLiteral(view.BaseInterval))
+	if _, err := r.database.Pool.Exec(ctx, createSQL); err != nil {
+		return fmt.Errorf("failed to create continuous aggregate %s: %w", view.Name(), err)
+	}
+	if err := r.checkDerivedOffset(ctx, view); err != nil {
+		return err
+	}
+
+	// Refresh at most hourly, looking back three buckets and leaving the open bucket
+	// to real-time aggregation
+	schedule := time.Duration(view.Width) * time.Millisecond
+	if schedule > time.Hour {
+		schedule = time.Hour
+	}
+
+	_, err := r.database.Pool.Exec(ctx,
+		`SELECT add_continuous_aggregate_policy($1::regclass,
+			start_offset => $2::BIGINT,
+			end_offset => $3::BIGINT,
+			schedule_interval => $4::INTERVAL,
+			if_not_exists => TRUE)`,
+		view.Name(), 3*view.Width, view.Width, schedule.String(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to add refresh policy for %s: %w", view.Name(), err)
+	}
+
+	return nil
+}
+
+// checkDerivedOffset fails when an existing continuous aggregate of view has buckets
+// that do not start at view.Offset, as created by versions that misaligned 3d and 1w
+// candles. CREATE MATERIALIZED VIEW IF NOT EXISTS keeps such a view as it is.
+func (r *KlineRepository) checkDerivedOffset(ctx context.Context, view DerivedKlineView) error {
+	name := pgx.Identifier{view.Name()}.Sanitize()
+
+	var bucket int64
+	err := r.database.Pool.QueryRow(ctx, `SELECT bucket FROM `+name+` LIMIT 1`).Scan(&bucket)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to check continuous aggregate %s: %w", view.Name(), err)
+	}
+
+	if shift := (bucket - view.Offset) % view.Width; shift != 0 {
+		return fmt.Errorf("continuous aggregate %s has buckets not aligned to Binance %s candles; "+
+			"drop it with DROP MATERIALIZED VIEW %s and restart to recreate it", view.Name(), view.Interval, name)
+	}
+
+	return nil
+}
+
+// RefreshDerivedView materializes the continuous aggregate for open times in [startTime, endTime)
+func (r *KlineRepository) RefreshDerivedView(ctx context.Context, view DerivedKlineView, startTime, endTime int64) error {
+	_, err := r.database.Pool.Exec(ctx,
+		`CALL refresh_continuous_aggregate($1::regclass, $2::BIGINT, $3::BIGINT)`,
+		view.Name(), startTime, endTime,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to refresh %s: %w", view.Name(), err)
+	}
+
+	return nil
+}
+
+// getDerivedByTimeRange reads derived klines with open times in [startTime, endTime)
+func (r *KlineRepository) getDerivedByTimeRange(
+	ctx context.Context,
+	view DerivedKlineView,
+	symbol string,
+	startTime, endTime int64,
+) ([]models.Kline, error) {
+	return r.queryDerived(ctx, view, `WHERE symbol = $1 AND bucket >= $2 AND bucket < $3
+ORDER BY bucket ASC`, symbol, startTime, endTime)
+}
+
+// getLastDerived reads the newest derived kline, or nil if there is none
+func (r *KlineRepository) getLastDerived(ctx context.Context, view DerivedKlineView, symbol string) (*models.Kline, error) {
+	query := fmt.Sprintf(`SELECT `+derivedColumns+`
+FROM %s
+WHERE symbol = $1
+ORDER BY bucket DESC
+LIMIT 1`, view.Width, view.source())
+
+	kline, err := scanDerivedKline(r.database.Pool.QueryRow(ctx, query, symbol), view.Interval)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil // No data found
+		}
+		return nil, fmt.Errorf("failed to get last derived kline: %w", err)
+	}
+
+	return kline, nil
+}
+
+// countDerived counts derived klines with open times in [startTime, endTime)
+func (r *KlineRepository) countDerived(
+	ctx context.Context,
+	view DerivedKlineView,
+	symbol string,
+	startTime, endTime int64,
+) (int64, error) {
+	query := fmt.Sprintf(`SELECT COUNT(*) FROM %s WHERE symbol = $1 AND bucket >= $2 AND bucket < $3`,
+		view.source())
+
+	var count int64
+	if err := r.database.Pool.QueryRow(ctx, query, symbol, startTime, endTime).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count derived klines: %w", err)
+	}
+
+	return count, nil
+}
+
+// scanDerivedKline scans a row selected with derivedColumns
+func scanDerivedKline(row pgx.Row, interval string) (*models.Kline, error) {
+	var (
+		kline       models.Kline
+		tradesCount int64
+	)
+
+	err := row.Scan(
+		&kline.Symbol,
+		&kline.OpenTime,
+		&kline.CloseTime,
+		&kline.OpenPrice,
+		&kline.HighPrice,
+		&kline.LowPrice,
+		&kline.ClosePrice,
+		&kline.Volume,
+		&kline.QuoteVolume,
+		&tradesCount,
+		&kline.TakerBuyVolume,
+		&kline.TakerBuyQuoteVolume,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	kline.Interval = interval
+	kline.TradesCount = int(tradesCount)
+	return &kline, nil
+}
+
+// quoteLiteral quotes a string as an SQL literal for DDL that cannot take parameters
+func quoteLiteral(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/binance-live/internal/models"
+)
+
+const (
+	aggregateTestSymbol = "TESTAGGUSDT"
+	dayMillis           = int64(24 * 60 * 60 * 1000)
+)
+
+func TestResampleKlinesWeeklyBuckets(t *testing.T) {
+	db := testDatabase(t)
+	repo := NewKlineRepository(db)
+	ctx := context.Background()
+
+	cleanup := func() {
+		if _, err := db.Pool.Exec(ctx, `DELETE FROM klines WHERE symbol = $1`, aggregateTestSymbol); err != nil {
+			t.Fatalf("failed to delete test klines: %v", err)
+		}
+	}
+	cleanup()
+	t.Cleanup(cleanup)
+
+	// Daily candles from Sunday 2024-01-07 to Sunday 2024-01-14; Binance's weekly candle
+	// opens on Monday 2024-01-08
+	const sunday = int64(1704585600000)
+	var klines []models.Kline
+	for i := int64(0); i < 8; i++ {
+		openTime := sunday + i*dayMillis
+		klines = append(klines, models.Kline{
+			Symbol:     aggregateTestSymbol,
+			Interval:   "1d",
+			OpenTime:   openTime,
+			CloseTime:  openTime + dayMillis - 1,
+			OpenPrice:  float64(100 + i),
+			HighPrice:  float64(110 + i),
+			LowPrice:   float64(90 + i),
+			ClosePrice: float64(101 + i),
+			Volume:     1,
+		})
+	}
+	if err := repo.BatchInsert(ctx, klines); err != nil {
+		t.Fatalf("failed to insert klines: %v", err)
+	}
+
+	view := DerivedKlineView{
+		Interval:     "1w",
+		BaseInterval: "1d",
+		Width:        7 * dayMillis,
+		BaseWidth:    dayMillis,
+		Offset:       4 * dayMillis,
+	}
+	weeks, err := repo.ResampleKlines(ctx, view, aggregateTestSymbol, sunday, sunday+8*dayMillis)
+	if err != nil {
+		t.Fatalf("failed to resample klines: %v", err)
+	}
+
+	// The week ending on the first Sunday misses six days and is left out
+	if len(weeks) != 1 {
+		t.Fatalf("got %d weekly klines, want 1: %v", len(weeks), weeks)
+	}
+	week := weeks[0]
+	if week.OpenTime != sunday+dayMillis || week.CloseTime != sunday+8*dayMillis-1 {
+		t.Errorf("got week [%d, %d], want [%d, %d]", week.OpenTime, week.CloseTime, sunday+dayMillis, sunday+8*dayMillis-1)
+	}
+	if week.OpenPrice != 101 || week.ClosePrice != 108 || week.HighPrice != 117 || week.LowPrice != 91 || week.Volume != 7 {
+		t.Errorf("got week %+v, want Monday to Sunday", week)
+	}
+}
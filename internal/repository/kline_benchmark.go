@@ -0,0 +1,274 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/binance-live/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// KlineLayout is a candidate column type for kline prices and volumes
+type KlineLayout struct {
+	Name       string
+	ColumnType string
+	Scale      float64 // Stored as round(value * Scale) in an integer column when set
+	MaxValue   float64 // Largest absolute value the column holds, 0: unbounded
+}
+
+// KlineLayouts are the layouts compared by BenchmarkKlineLayout
+var KlineLayouts = []KlineLayout{
+	{Name: "decimal", ColumnType: "DECIMAL(20, 8)", MaxValue: 1e12},
+	{Name: "double", ColumnType: "DOUBLE PRECISION"},
+	{Name: "scaled", ColumnType: "BIGINT", Scale: 1e8},
+}
+
+// errKlineOverflow reports a value the layout cannot store
+var errKlineOverflow = errors.New("value out of range")
+
+// encode converts a value to its column representation, refusing values the column
+// would overflow on instead of letting the whole insert fail
+func (l KlineLayout) encode(value float64) (any, error) {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return nil, errKlineOverflow
+	}
+
+	if l.Scale == 0 {
+		if l.MaxValue > 0 && math.Abs(value) >= l.MaxValue {
+			return nil, errKlineOverflow
+		}
+		return value, nil
+	}
+
+	// float64(math.MaxInt64) rounds up to 2^63, which is already out of range
+	scaled := math.Round(value * l.Scale)
+	if scaled >= math.MaxInt64 || scaled < math.MinInt64 {
+		return nil, errKlineOverflow
+	}
+	return int64(scaled), nil
+}
+
+// decode converts a scaled integer column value back to float64
+func (l KlineLayout) decode(value int64) float64 {
+	return float64(value) / l.Scale
+}
+
+// KlineLayoutResult is the outcome of benchmarking one layout
+type KlineLayoutResult struct {
+	Layout          KlineLayout
+	Rows            int // Rows inserted
+	Overflows       int // Rows skipped because a value did not fit the layout
+	InsertTime      time.Duration
+	QueryTime       time.Duration
+	QueriedRows     int
+	TotalBytes      int64
+	CompressedBytes int64 // Size after compressing every chunk, 0 unless compressed
+}
+
+// klineBenchmarkCreateSQL creates a scratch kline hypertable with the layout's value columns
+const klineBenchmarkCreateSQL = `
+CREATE TABLE %[1]s (
+    symbol VARCHAR(20) NOT NULL,
+    interval VARCHAR(5) NOT NULL,
+    open_time BIGINT NOT NULL,
+    close_time BIGINT NOT NULL,
+    open_price %[2]s NOT NULL,
+    high_price %[2]s NOT NULL,
+    low_price %[2]s NOT NULL,
+    close_price %[2]s NOT NULL,
+    volume %[2]s NOT NULL,
+    quote_volume %[2]s NOT NULL,
+    trades_count INTEGER NOT NULL,
+    taker_buy_volume %[2]s NOT NULL,
+    taker_buy_quote_volume %[2]s NOT NULL,
+    created_at BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW()) * 1000,
+    PRIMARY KEY (symbol, interval, open_time)
+)`
+
+// BenchmarkKlineLayout loads klines into a scratch hypertable using layout, reads every
+// symbol back passes times and measures the table size, optionally after compression.
+// The scratch table is dropped afterwards.
+func (r *MaintenanceRepository) BenchmarkKlineLayout(
+	ctx context.Context,
+	layout KlineLayout,
+	klines []models.Kline,
+	batchSize, passes int,
+	compress bool,
+) (*KlineLayoutResult, error) {
+	table := Hypertable{
+		Name:      "kline_bench_" + layout.Name,
+		SegmentBy: "symbol, interval",
+		OrderBy:   "open_time DESC",
+	}
+	name := pgx.Identifier{table.Name}.Sanitize()
+
+	if _, err := r.database.Pool.Exec(ctx, "DROP TABLE IF EXISTS "+name); err != nil {
+		return nil, fmt.Errorf("failed to drop %s: %w", table.Name, err)
+	}
+	if _, err := r.database.Pool.Exec(ctx, fmt.Sprintf(klineBenchmarkCreateSQL, name, layout.ColumnType)); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", table.Name, err)
+	}
+	defer r.database.Pool.Exec(context.WithoutCancel(ctx), "DROP TABLE IF EXISTS "+name)
+
+	if _, err := r.database.Pool.Exec(ctx,
+		`SELECT create_hypertable($1::regclass, 'open_time', chunk_time_interval => 86400000)`, table.Name,
+	); err != nil {
+		return nil, fmt.Errorf("failed to create hypertable %s: %w", table.Name, err)
+	}
+
+	result := &KlineLayoutResult{Layout: layout}
+
+	// Insert with the statement shape used by the stream and sync services
+	insertSQL := fmt.Sprintf(`INSERT INTO %s (
+    symbol, interval, open_time, close_time, open_price, high_price,
+    low_price, close_price, volume, quote_volume, trades_count,
+    taker_buy_volume, taker_buy_quote_volume
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+ON CONFLICT (symbol, interval, open_time) DO NOTHING`, name)
+
+	started := time.Now()
+	batch := &pgx.Batch{}
+	for i := range klines {
+		kline := &klines[i]
+
+		values, err := layout.encodeKline(kline)
+		if err != nil {
+			result.Overflows++
+			continue
+		}
+		args := append([]any{kline.Symbol, kline.Interval, kline.OpenTime, kline.CloseTime}, values[:6]...)
+		args = append(args, int32(kline.TradesCount), values[6], values[7])
+		batch.Queue(insertSQL, args...)
+		result.Rows++
+
+		if batch.Len() >= batchSize {
+			if err := r.database.Pool.SendBatch(ctx, batch).Close(); err != nil {
+				return nil, fmt.Errorf("failed to insert into %s: %w", table.Name, err)
+			}
+			batch = &pgx.Batch{}
+		}
+	}
+	if batch.Len() > 0 {
+		if err := r.database.Pool.SendBatch(ctx, batch).Close(); err != nil {
+			return nil, fmt.Errorf("failed to insert into %s: %w", table.Name, err)
+		}
+	}
+	result.InsertTime = time.Since(started)
+
+	if _, err := r.database.Pool.Exec(ctx, "ANALYZE "+name); err != nil {
+		return nil, fmt.Errorf("failed to analyze %s: %w", table.Name, err)
+	}
+
+	symbols := make(map[string]bool)
+	for _, kline := range klines {
+		symbols[kline.Symbol] = true
+	}
+
+	querySQL := fmt.Sprintf(`SELECT open_time, open_price, high_price, low_price, close_price,
+       volume, quote_volume, taker_buy_volume, taker_buy_quote_volume
+FROM %s
+WHERE symbol = $1
+ORDER BY open_time ASC`, name)
+
+	started = time.Now()
+	for pass := 0; pass < passes; pass++ {
+		for symbol := range symbols {
+			queried, err := r.readKlineLayout(ctx, layout, querySQL, symbol)
+			if err != nil {
+				return nil, fmt.Errorf("failed to query %s: %w", table.Name, err)
+			}
+			result.QueriedRows += queried
+		}
+	}
+	result.QueryTime = time.Since(started)
+
+	stats, err := r.Stats(ctx, table)
+	if err != nil {
+		return nil, err
+	}
+	result.TotalBytes = stats.TotalBytes
+
+	if !compress {
+		return result, nil
+	}
+
+	if err := r.EnableCompression(ctx, table); err != nil {
+		return nil, err
+	}
+	if _, err := r.database.Pool.Exec(ctx,
+		`SELECT compress_chunk(c, if_not_compressed => TRUE) FROM show_chunks($1::regclass) c`, table.Name,
+	); err != nil {
+		return nil, fmt.Errorf("failed to compress %s: %w", table.Name, err)
+	}
+
+	stats, err = r.Stats(ctx, table)
+	if err != nil {
+		return nil, err
+	}
+	result.CompressedBytes = stats.TotalBytes
+
+	return result, nil
+}
+
+// encodeKline encodes the eight price and volume fields of a kline in column order
+func (l KlineLayout) encodeKline(kline *models.Kline) ([]any, error) {
+	fields := []float64{
+		kline.OpenPrice, kline.HighPrice, kline.LowPrice, kline.ClosePrice,
+		kline.Volume, kline.QuoteVolume, kline.TakerBuyVolume, kline.TakerBuyQuoteVolume,
+	}
+
+	values := make([]any, len(fields))
+	for i, field := range fields {
+		value, err := l.encode(field)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+	}
+
+	return values, nil
+}
+
+// readKlineLayout reads the klines of symbol from a benchmark table into float64 values,
+// the way the repository reads klines, and returns the number of rows
+func (r *MaintenanceRepository) readKlineLayout(ctx context.Context, layout KlineLayout, query, symbol string) (int, error) {
+	rows, err := r.database.Pool.Query(ctx, query, symbol)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	count := 0
+	for rows.Next() {
+		var (
+			openTime int64
+			kline    models.Kline
+		)
+
+		if layout.Scale == 0 {
+			err = rows.Scan(&openTime, &kline.OpenPrice, &kline.HighPrice, &kline.LowPrice, &kline.ClosePrice,
+				&kline.Volume, &kline.QuoteVolume, &kline.TakerBuyVolume, &kline.TakerBuyQuoteVolume)
+		} else {
+			var scaled [8]int64
+			err = rows.Scan(&openTime, &scaled[0], &scaled[1], &scaled[2], &scaled[3],
+				&scaled[4], &scaled[5], &scaled[6], &scaled[7])
+			kline.OpenPrice = layout.decode(scaled[0])
+			kline.HighPrice = layout.decode(scaled[1])
+			kline.LowPrice = layout.decode(scaled[2])
+			kline.ClosePrice = layout.decode(scaled[3])
+			kline.Volume = layout.decode(scaled[4])
+			kline.QuoteVolume = layout.decode(scaled[5])
+			kline.TakerBuyVolume = layout.decode(scaled[6])
+			kline.TakerBuyQuoteVolume = layout.decode(scaled[7])
+		}
+		if err != nil {
+			return 0, err
+		}
+		count++
+	}
+
+	return count, rows.Err()
+}
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/binance-live/internal/database"
+	"github.com/binance-live/internal/db"
+	"github.com/binance-live/internal/models"
+)
+
+// KlineGapRepository handles kline gap detection and tracking
+type KlineGapRepository struct {
+	database *database.Database
+	queries  *db.Queries
+}
+
+// NewKlineGapRepository creates a new kline gap repository
+func NewKlineGapRepository(database *database.Database) *KlineGapRepository {
+	return &KlineGapRepository{
+		database: database,
+		queries:  db.New(database.Pool),
+	}
+}
+
+// FindGaps scans stored klines with open times in [startTime, endTime) and returns
+// the holes between consecutive candles. MissingCount is left for the caller to fill in.
+func (r *KlineGapRepository) FindGaps(
+	ctx context.Context,
+	symbol, interval string,
+	startTime, endTime int64,
+) ([]models.KlineGap, error) {
+	rows, err := r.queries.FindKlineGaps(ctx, db.FindKlineGapsParams{
+		Symbol:     symbol,
+		Interval:   interval,
+		OpenTime:   startTime,
+		OpenTime_2: endTime,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find kline gaps: %w", err)
+	}
+
+	gaps := make([]models.KlineGap, 0, len(rows))
+	for _, row := range rows {
+		gaps = append(gaps, models.KlineGap{
+			Symbol:   symbol,
+			Interval: interval,
+			GapStart: row.GapStart,
+			GapEnd:   row.GapEnd,
+		})
+	}
+
+	return gaps, nil
+}
+
+// Upsert records a detected gap. A gap seen again after it was repaired is reopened with
+// a fresh retry budget; an open gap keeps its attempts.
+func (r *KlineGapRepository) Upsert(ctx context.Context, gap *models.KlineGap) error {
+	err := r.queries.UpsertKlineGap(ctx, db.UpsertKlineGapParams{
+		Symbol:       gap.Symbol,
+		Interval:     gap.Interval,
+		GapStart:     gap.GapStart,
+		GapEnd:       gap.GapEnd,
+		MissingCount: int32(gap.MissingCount),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upsert kline gap: %w", err)
+	}
+
+	return nil
+}
+
+// GetOpenGaps retrieves open gaps that have been attempted fewer than maxAttempts times
+func (r *KlineGapRepository) GetOpenGaps(ctx context.Context, maxAttempts, limit int) ([]models.KlineGap, error) {
+	dbGaps, err := r.queries.GetOpenKlineGaps(ctx, db.GetOpenKlineGapsParams{
+		Attempts: int32(maxAttempts),
+		Limit:    int32(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query open kline gaps: %w", err)
+	}
+
+	return convertKlineGaps(dbGaps), nil
+}
+
+// GetGapsBySymbol retrieves all recorded gaps for a symbol
+func (r *KlineGapRepository) GetGapsBySymbol(ctx context.Context, symbol string) ([]models.KlineGap, error) {
+	dbGaps, err := r.queries.GetKlineGapsBySymbol(ctx, symbol)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query kline gaps: %w", err)
+	}
+
+	return convertKlineGaps(dbGaps), nil
+}
+
+// MarkRepaired marks a gap as repaired
+func (r *KlineGapRepository) MarkRepaired(ctx context.Context, id int64) error {
+	if err := r.queries.MarkKlineGapRepaired(ctx, id); err != nil {
+		return fmt.Errorf("failed to mark kline gap repaired: %w", err)
+	}
+
+	return nil
+}
+
+// MarkFailed records a failed repair attempt
+func (r *KlineGapRepository) MarkFailed(ctx context.Context, id int64, errorMessage string) error {
+	err := r.queries.MarkKlineGapFailed(ctx, db.MarkKlineGapFailedParams{
+		ID:           id,
+		ErrorMessage: sql.NullString{String: errorMessage, Valid: true},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to mark kline gap failed: %w", err)
+	}
+
+	return nil
+}
+
+// convertKlineGaps converts sqlc kline gaps to models
+func convertKlineGaps(dbGaps []db.KlineGap) []models.KlineGap {
+	gaps := make([]models.KlineGap, 0, len(dbGaps))
+	for _, dbGap := range dbGaps {
+		gap := models.KlineGap{
+			ID:           dbGap.ID,
+			Symbol:       dbGap.Symbol,
+			Interval:     dbGap.Interval,
+			GapStart:     dbGap.GapStart,
+			GapEnd:       dbGap.GapEnd,
+			MissingCount: int(dbGap.MissingCount),
+			Status:       dbGap.Status,
+			Attempts:     int(dbGap.Attempts),
+			DetectedAt:   dbGap.DetectedAt,
+			UpdatedAt:    dbGap.UpdatedAt,
+		}
+
+		if dbGap.ErrorMessage.Valid {
+			gap.ErrorMessage = &dbGap.ErrorMessage.String
+		}
+
+		gaps = append(gaps, gap)
+	}
+
+	return gaps
+}
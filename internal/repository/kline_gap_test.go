@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/binance-live/internal/models"
+)
+
+const gapTestSymbol = "TESTGAPUSDT"
+
+// testGap returns the recorded gap of the test symbol
+func testGap(t *testing.T, repo *KlineGapRepository) models.KlineGap {
+	t.Helper()
+
+	gaps, err := repo.GetGapsBySymbol(context.Background(), gapTestSymbol)
+	if err != nil {
+		t.Fatalf("failed to get gaps: %v", err)
+	}
+	if len(gaps) != 1 {
+		t.Fatalf("got %d gaps, want 1", len(gaps))
+	}
+	return gaps[0]
+}
+
+// hasOpenTestGap reports whether GetOpenGaps returns the test gap
+func hasOpenTestGap(t *testing.T, repo *KlineGapRepository, maxAttempts int) bool {
+	t.Helper()
+
+	gaps, err := repo.GetOpenGaps(context.Background(), maxAttempts, 1000)
+	if err != nil {
+		t.Fatalf("failed to get open gaps: %v", err)
+	}
+	for _, gap := range gaps {
+		if gap.Symbol == gapTestSymbol {
+			return true
+		}
+	}
+	return false
+}
+
+func TestKlineGapReopenedWithFreshAttempts(t *testing.T) {
+	ctx := context.Background()
+	db := testDatabase(t)
+	repo := NewKlineGapRepository(db)
+
+	cleanup := func() {
+		if _, err := db.Pool.Exec(ctx, `DELETE FROM kline_gaps WHERE symbol = $1`, gapTestSymbol); err != nil {
+			t.Fatalf("failed to delete test gaps: %v", err)
+		}
+	}
+	cleanup()
+	t.Cleanup(cleanup)
+
+	const maxAttempts = 2
+	gap := &models.KlineGap{Symbol: gapTestSymbol, Interval: "1m", GapStart: 60000, GapEnd: 180000, MissingCount: 2}
+	if err := repo.Upsert(ctx, gap); err != nil {
+		t.Fatalf("failed to record gap: %v", err)
+	}
+	id := testGap(t, repo).ID
+
+	// Used up its attempts: detecting it again does not retry it
+	for range maxAttempts {
+		if err := repo.MarkFailed(ctx, id, "binance unavailable"); err != nil {
+			t.Fatalf("failed to mark gap failed: %v", err)
+		}
+	}
+	if err := repo.Upsert(ctx, gap); err != nil {
+		t.Fatalf("failed to record gap: %v", err)
+	}
+	if got := testGap(t, repo); got.Attempts != maxAttempts || got.ErrorMessage == nil {
+		t.Errorf("got %d attempts and error %v on an open gap, want %d and the error", got.Attempts, got.ErrorMessage, maxAttempts)
+	}
+	if hasOpenTestGap(t, repo, maxAttempts) {
+		t.Error("exhausted gap is retried")
+	}
+
+	// Repaired, then missing again: reopened with a fresh retry budget
+	if err := repo.MarkRepaired(ctx, id); err != nil {
+		t.Fatalf("failed to mark gap repaired: %v", err)
+	}
+	if err := repo.Upsert(ctx, gap); err != nil {
+		t.Fatalf("failed to record gap: %v", err)
+	}
+	got := testGap(t, repo)
+	if got.Status != "open" || got.Attempts != 0 || got.ErrorMessage != nil {
+		t.Errorf("got %s gap with %d attempts and error %v, want open with none", got.Status, got.Attempts, got.ErrorMessage)
+	}
+	if !hasOpenTestGap(t, repo, maxAttempts) {
+		t.Error("reopened gap is not retried")
+	}
+}
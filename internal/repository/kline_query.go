@@ -0,0 +1,247 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/binance-live/internal/db"
+	"github.com/binance-live/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// Every read below filters on symbol, interval and an open_time range or order, so it is
+// served by the (symbol, interval, open_time DESC) index, or the view's own index for
+// derived intervals.
+
+// GetLatestKlines retrieves the newest limit klines of a symbol, oldest first
+func (r *KlineRepository) GetLatestKlines(ctx context.Context, symbol, interval string, limit int) ([]models.Kline, error) {
+	if limit <= 0 {
+		return []models.Kline{}, nil
+	}
+
+	var klines []models.Kline
+	if view, ok := r.derivedView(interval); ok {
+		var err error
+		klines, err = r.queryDerived(ctx, view, `WHERE symbol = $1
+ORDER BY bucket DESC
+LIMIT $2`, symbol, limit)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		dbKlines, err := r.queries.GetLatestKlines(ctx, db.GetLatestKlinesParams{
+			Symbol:   symbol,
+			Interval: interval,
+			Limit:    int32(limit),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to query latest klines: %w", err)
+		}
+
+		klines = make([]models.Kline, 0, len(dbKlines))
+		for _, dbKline := range dbKlines {
+			klines = append(klines, convertKline(dbKline))
+		}
+	}
+
+	// Both queries read newest first to use the index; callers expect time order
+	slices.Reverse(klines)
+	return klines, nil
+}
+
+// GetKlinesBySymbols retrieves the klines of several symbols with open times in
+// [startTime, endTime) in one query, keyed by symbol. Symbols without data are absent.
+func (r *KlineRepository) GetKlinesBySymbols(
+	ctx context.Context,
+	symbols []string,
+	interval string,
+	startTime, endTime int64,
+) (map[string][]models.Kline, error) {
+	bySymbol := make(map[string][]models.Kline, len(symbols))
+	if len(symbols) == 0 {
+		return bySymbol, nil
+	}
+
+	if view, ok := r.derivedView(interval); ok {
+		klines, err := r.queryDerived(ctx, view, `WHERE symbol = ANY($1::text[]) AND bucket >= $2 AND bucket < $3
+ORDER BY symbol ASC, bucket ASC`, symbols, startTime, endTime)
+		if err != nil {
+			return nil, err
+		}
+		for _, kline := range klines {
+			bySymbol[kline.Symbol] = append(bySymbol[kline.Symbol], kline)
+		}
+		return bySymbol, nil
+	}
+
+	dbKlines, err := r.queries.GetKlinesBySymbols(ctx, db.GetKlinesBySymbolsParams{
+		Symbols:   symbols,
+		Interval:  interval,
+		StartTime: startTime,
+		EndTime:   endTime,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query klines by symbols: %w", err)
+	}
+
+	for _, dbKline := range dbKlines {
+		bySymbol[dbKline.Symbol] = append(bySymbol[dbKline.Symbol], convertKline(dbKline))
+	}
+
+	return bySymbol, nil
+}
+
+// IterateKlines passes the klines with open times in [startTime, endTime) to fn in time
+// order, pageSize at a time, so large ranges are never held in memory at once. Each page
+// is a separate query continuing after the last open time seen; an error from fn stops
+// the iteration and is returned as is.
+func (r *KlineRepository) IterateKlines(
+	ctx context.Context,
+	symbol, interval string,
+	startTime, endTime int64,
+	pageSize int,
+	fn func([]models.Kline) error,
+) error {
+	if pageSize <= 0 {
+		return fmt.Errorf("page size must be positive")
+	}
+
+	cursor := startTime
+	for cursor < endTime {
+		page, err := r.GetKlinesPage(ctx, symbol, interval, cursor, endTime, pageSize)
+		if err != nil {
+			return err
+		}
+		if len(page) == 0 {
+			return nil
+		}
+
+		if err := fn(page); err != nil {
+			return err
+		}
+
+		if len(page) < pageSize {
+			return nil
+		}
+		cursor = page[len(page)-1].OpenTime + 1
+	}
+
+	return nil
+}
+
+// GetKlinesPage reads at most limit klines with open times in [startTime, endTime),
+// oldest first. Continue with startTime set just after the last open time returned.
+func (r *KlineRepository) GetKlinesPage(
+	ctx context.Context,
+	symbol, interval string,
+	startTime, endTime int64,
+	limit int,
+) ([]models.Kline, error) {
+	if view, ok := r.derivedView(interval); ok {
+		return r.queryDerived(ctx, view, `WHERE symbol = $1 AND bucket >= $2 AND bucket < $3
+ORDER BY bucket ASC
+LIMIT $4`, symbol, startTime, endTime, limit)
+	}
+
+	dbKlines, err := r.queries.GetKlinesPage(ctx, db.GetKlinesPageParams{
+		Symbol:     symbol,
+		Interval:   interval,
+		OpenTime:   startTime,
+		OpenTime_2: endTime,
+		Limit:      int32(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query klines page: %w", err)
+	}
+
+	klines := make([]models.Kline, 0, len(dbKlines))
+	for _, dbKline := range dbKlines {
+		klines = append(klines, convertKline(dbKline))
+	}
+
+	return klines, nil
+}
+
+// resampleSQL aggregates stored base candles into view buckets on read, with the same
+// semantics as the continuous aggregates. Buckets missing base candles are left out,
+// except one cut by the end of the range.
+const resampleSQL = `SELECT ` + derivedColumns + `
+FROM (
+    SELECT symbol,
+           time_bucket(%d::BIGINT, open_time, %d::BIGINT) AS bucket,
+` + derivedAggregates + `
+    FROM klines
+    WHERE symbol = $1 AND interval = $2 AND open_time >= $3 AND open_time < $4
+    GROUP BY symbol, bucket
+) resampled
+WHERE candle_count = %d OR bucket + %d > $4
+ORDER BY bucket ASC`
+
+// ResampleKlines aggregates the stored view.BaseInterval klines of symbol into
+// view.Interval candles with open times in [startTime, endTime), without a continuous
+// aggregate. startTime is rounded down to a bucket boundary; a bucket cut by endTime is
+// built from the base candles before it, like the newest candle of a derived view.
+// Other buckets missing base candles are left out.
+func (r *KlineRepository) ResampleKlines(
+	ctx context.Context,
+	view DerivedKlineView,
+	symbol string,
+	startTime, endTime int64,
+) ([]models.Kline, error) {
+	if view.Width <= 0 || view.BaseWidth <= 0 {
+		return nil, fmt.Errorf("invalid resample width %d", view.Width)
+	}
+
+	shift := (startTime - view.Offset) % view.Width
+	if shift < 0 {
+		shift += view.Width
+	}
+
+	query := fmt.Sprintf(resampleSQL, view.Width, view.Width, view.Offset, view.CandlesPerBucket(), view.Width)
+	rows, err := r.database.Pool.Query(ctx, query, symbol, view.BaseInterval, startTime-shift, endTime)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resample klines: %w", err)
+	}
+
+	return collectDerivedKlines(rows, view.Interval)
+}
+
+// queryDerived reads the derived klines of view selected by clause
+func (r *KlineRepository) queryDerived(
+	ctx context.Context,
+	view DerivedKlineView,
+	clause string,
+	args ...any,
+) ([]models.Kline, error) {
+	query := fmt.Sprintf(`SELECT `+derivedColumns+`
+FROM %s
+`, view.Width, view.source()) + clause
+
+	rows, err := r.database.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query derived klines: %w", err)
+	}
+
+	return collectDerivedKlines(rows, view.Interval)
+}
+
+// collectDerivedKlines scans and closes rows selected with derivedColumns
+func collectDerivedKlines(rows pgx.Rows, interval string) ([]models.Kline, error) {
+	defer rows.Close()
+
+	klines := []models.Kline{}
+	for rows.Next() {
+		kline, err := scanDerivedKline(rows, interval)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan derived kline: %w", err)
+		}
+		klines = append(klines, *kline)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query derived klines: %w", err)
+	}
+
+	return klines, nil
+}
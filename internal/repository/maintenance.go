@@ -0,0 +1,225 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/binance-live/internal/database"
+	"github.com/binance-live/internal/db"
+	"github.com/jackc/pgx/v5"
+)
+
+// Hypertable describes how a hypertable is compressed
+type Hypertable struct {
+	Name      string
+	SegmentBy string // Columns compressed chunks are segmented by
+	OrderBy   string // Must cover the primary key columns not in SegmentBy
+}
+
+// Hypertables lists the hypertables managed by retention and compression
+var Hypertables = []Hypertable{
+	{Name: "klines", SegmentBy: "symbol, interval", OrderBy: "open_time DESC"},
+	{Name: "tickers", SegmentBy: "symbol", OrderBy: "timestamp DESC"},
+	{Name: "trades", SegmentBy: "symbol", OrderBy: "timestamp DESC, trade_id"},
+	{Name: "depth_snapshots", SegmentBy: "symbol", OrderBy: "timestamp DESC"},
+	{Name: "bars", SegmentBy: "symbol, spec", OrderBy: "open_time DESC, first_trade_id"},
+}
+
+// HypertableStats is the storage and compression state of a hypertable
+type HypertableStats struct {
+	Name               string
+	Exists             bool
+	TotalBytes         int64
+	CompressionEnabled bool
+	CompressAfter      *int64 // Compression policy threshold in milliseconds, nil without a policy
+	TotalChunks        int64
+	CompressedChunks   int64
+	BeforeBytes        int64 // Size of the compressed chunks before compression
+	AfterBytes         int64 // Size of the compressed chunks after compression
+}
+
+// integerNowSQL defines the current time in milliseconds for the BIGINT time columns
+const integerNowSQL = `
+CREATE OR REPLACE FUNCTION unix_now_ms() RETURNS BIGINT
+LANGUAGE SQL STABLE AS $$ SELECT (EXTRACT(EPOCH FROM NOW()) * 1000)::BIGINT $$
+`
+
+// setIntegerNowFunc lets TimescaleDB policies work with the millisecond time column of table
+func setIntegerNowFunc(ctx context.Context, database *database.Database, table string) error {
+	if _, err := database.Pool.Exec(ctx, integerNowSQL); err != nil {
+		return fmt.Errorf("failed to create integer now function: %w", err)
+	}
+
+	_, err := database.Pool.Exec(ctx,
+		`SELECT set_integer_now_func($1::regclass, 'unix_now_ms', replace_if_exists => TRUE)`, table)
+	if err != nil {
+		return fmt.Errorf("failed to set integer now function for %s: %w", table, err)
+	}
+
+	return nil
+}
+
+// MaintenanceRepository handles retention and compression of the hypertables
+type MaintenanceRepository struct {
+	database *database.Database
+	queries  *db.Queries
+}
+
+// NewMaintenanceRepository creates a new maintenance repository
+func NewMaintenanceRepository(database *database.Database) *MaintenanceRepository {
+	return &MaintenanceRepository{
+		database: database,
+		queries:  db.New(database.Pool),
+	}
+}
+
+// Stats returns the storage and compression state of a hypertable.
+// Tables that were never created are reported with Exists unset.
+func (r *MaintenanceRepository) Stats(ctx context.Context, table Hypertable) (*HypertableStats, error) {
+	stats := &HypertableStats{Name: table.Name}
+
+	err := r.database.Pool.QueryRow(ctx,
+		`SELECT compression_enabled FROM timescaledb_information.hypertables WHERE hypertable_name = $1`,
+		table.Name,
+	).Scan(&stats.CompressionEnabled)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return stats, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get hypertable %s: %w", table.Name, err)
+	}
+	stats.Exists = true
+
+	if err := r.database.Pool.QueryRow(ctx,
+		`SELECT hypertable_size($1::regclass)`, table.Name,
+	).Scan(&stats.TotalBytes); err != nil {
+		return nil, fmt.Errorf("failed to get size of %s: %w", table.Name, err)
+	}
+
+	if err := r.database.Pool.QueryRow(ctx,
+		`SELECT count(*), count(*) FILTER (WHERE is_compressed)
+		FROM timescaledb_information.chunks WHERE hypertable_name = $1`, table.Name,
+	).Scan(&stats.TotalChunks, &stats.CompressedChunks); err != nil {
+		return nil, fmt.Errorf("failed to count chunks of %s: %w", table.Name, err)
+	}
+
+	if !stats.CompressionEnabled {
+		return stats, nil
+	}
+
+	var before, after *int64
+	if err := r.database.Pool.QueryRow(ctx,
+		`SELECT sum(before_compression_total_bytes)::BIGINT, sum(after_compression_total_bytes)::BIGINT
+		FROM hypertable_compression_stats($1::regclass)`, table.Name,
+	).Scan(&before, &after); err != nil {
+		return nil, fmt.Errorf("failed to get compression stats of %s: %w", table.Name, err)
+	}
+	if before != nil && after != nil {
+		stats.BeforeBytes = *before
+		stats.AfterBytes = *after
+	}
+
+	var compressAfter string
+	err = r.database.Pool.QueryRow(ctx,
+		`SELECT config->>'compress_after' FROM timescaledb_information.jobs
+		WHERE proc_name = 'policy_compression' AND hypertable_name = $1`, table.Name,
+	).Scan(&compressAfter)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return stats, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get compression policy of %s: %w", table.Name, err)
+	}
+
+	threshold, err := strconv.ParseInt(compressAfter, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("unexpected compression policy of %s: %q", table.Name, compressAfter)
+	}
+	stats.CompressAfter = &threshold
+
+	return stats, nil
+}
+
+// EnableCompression turns on native compression for a hypertable. TimescaleDB rejects
+// new settings once chunks are compressed, so only call it while compression is off.
+func (r *MaintenanceRepository) EnableCompression(ctx context.Context, table Hypertable) error {
+	alterSQL := fmt.Sprintf(
+		`ALTER TABLE %s SET (timescaledb.compress, timescaledb.compress_segmentby = %s, timescaledb.compress_orderby = %s)`,
+		pgx.Identifier{table.Name}.Sanitize(), quoteLiteral(table.SegmentBy), quoteLiteral(table.OrderBy),
+	)
+	if _, err := r.database.Pool.Exec(ctx, alterSQL); err != nil {
+		return fmt.Errorf("failed to enable compression on %s: %w", table.Name, err)
+	}
+
+	return nil
+}
+
+// SetCompressionPolicy replaces the compression policy of a hypertable so that chunks
+// older than compressAfter milliseconds are compressed. Zero removes the policy;
+// chunks that are already compressed stay compressed.
+func (r *MaintenanceRepository) SetCompressionPolicy(ctx context.Context, table Hypertable, compressAfter int64) error {
+	if err := setIntegerNowFunc(ctx, r.database, table.Name); err != nil {
+		return err
+	}
+
+	if _, err := r.database.Pool.Exec(ctx,
+		`SELECT remove_compression_policy($1::regclass, if_exists => TRUE)`, table.Name,
+	); err != nil {
+		return fmt.Errorf("failed to remove compression policy of %s: %w", table.Name, err)
+	}
+
+	if compressAfter <= 0 {
+		return nil
+	}
+
+	if _, err := r.database.Pool.Exec(ctx,
+		`SELECT add_compression_policy($1::regclass, compress_after => $2::BIGINT)`, table.Name, compressAfter,
+	); err != nil {
+		return fmt.Errorf("failed to add compression policy to %s: %w", table.Name, err)
+	}
+
+	return nil
+}
+
+// DeleteOldKlines deletes klines of interval opened before the cutoff and returns the
+// number of deleted rows
+func (r *MaintenanceRepository) DeleteOldKlines(ctx context.Context, interval string, before int64) (int64, error) {
+	deleted, err := r.queries.DeleteOldKlinesByInterval(ctx, db.DeleteOldKlinesByIntervalParams{
+		Interval: interval,
+		OpenTime: before,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete old %s klines: %w", interval, err)
+	}
+
+	return deleted, nil
+}
+
+// DeleteOldRows deletes the rows of a hypertable other than klines that are older than
+// the cutoff and returns the number of deleted rows
+func (r *MaintenanceRepository) DeleteOldRows(ctx context.Context, table string, before int64) (int64, error) {
+	var (
+		deleted int64
+		err     error
+	)
+
+	switch table {
+	case "tickers":
+		deleted, err = r.queries.DeleteOldTickers(ctx, before)
+	case "trades":
+		deleted, err = r.queries.DeleteOldTrades(ctx, before)
+	case "depth_snapshots":
+		deleted, err = r.queries.DeleteOldDepthSnapshots(ctx, before)
+	case "bars":
+		deleted, err = r.queries.DeleteOldBars(ctx, before)
+	default:
+		return 0, fmt.Errorf("no retention query for table %q", table)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete old %s: %w", table, err)
+	}
+
+	return deleted, nil
+}
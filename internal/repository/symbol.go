@@ -48,6 +48,30 @@ func (r *SymbolRepository) GetActiveSymbols(ctx context.Context) ([]models.Symbo
 	return symbols, nil
 }
 
+// GetAllSymbols retrieves every trading symbol, active or not, ordered by name
+func (r *SymbolRepository) GetAllSymbols(ctx context.Context) ([]models.Symbol, error) {
+	dbSymbols, err := r.queries.GetAllSymbols(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query symbols: %w", err)
+	}
+
+	symbols := make([]models.Symbol, 0, len(dbSymbols))
+	for _, dbSymbol := range dbSymbols {
+		symbols = append(symbols, models.Symbol{
+			ID:         int(dbSymbol.ID),
+			Symbol:     dbSymbol.Symbol,
+			BaseAsset:  dbSymbol.BaseAsset,
+			QuoteAsset: dbSymbol.QuoteAsset,
+			Status:     dbSymbol.Status,
+			IsActive:   dbSymbol.IsActive,
+			CreatedAt:  dbSymbol.CreatedAt,
+			UpdatedAt:  dbSymbol.UpdatedAt,
+		})
+	}
+
+	return symbols, nil
+}
+
 // GetSymbolByName retrieves a symbol by its name
 func (r *SymbolRepository) GetSymbolByName(ctx context.Context, symbol string) (*models.Symbol, error) {
 	dbSymbol, err := r.queries.GetSymbolByName(ctx, symbol)
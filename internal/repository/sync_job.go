@@ -0,0 +1,230 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/binance-live/internal/database"
+	"github.com/binance-live/internal/db"
+	"github.com/binance-live/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// ErrSyncJobLockLost is returned when a job is no longer running under the worker that
+// claimed it, typically because it was requeued as stale and claimed by another worker
+var ErrSyncJobLockLost = errors.New("sync job lock lost")
+
+// SyncJobRepository handles the durable sync job queue
+type SyncJobRepository struct {
+	database *database.Database
+	queries  *db.Queries
+}
+
+// NewSyncJobRepository creates a new sync job repository
+func NewSyncJobRepository(database *database.Database) *SyncJobRepository {
+	return &SyncJobRepository{
+		database: database,
+		queries:  db.New(database.Pool),
+	}
+}
+
+// Enqueue adds a pending job unless the stream already has a pending or running one.
+// It reports whether a new job was created.
+func (r *SyncJobRepository) Enqueue(
+	ctx context.Context,
+	symbol, dataType, interval string,
+	maxAttempts int,
+) (bool, error) {
+	created, err := r.queries.EnqueueSyncJob(ctx, db.EnqueueSyncJobParams{
+		Symbol:      symbol,
+		DataType:    dataType,
+		Interval:    interval,
+		MaxAttempts: int32(maxAttempts),
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to enqueue sync job: %w", err)
+	}
+
+	return created > 0, nil
+}
+
+// ClaimNext locks the next runnable job for worker using FOR UPDATE SKIP LOCKED,
+// so concurrent workers never claim the same job. It returns nil when nothing is runnable.
+func (r *SyncJobRepository) ClaimNext(ctx context.Context, worker string) (*models.SyncJob, error) {
+	dbJob, err := r.queries.ClaimSyncJob(ctx, sql.NullString{String: worker, Valid: true})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil // Nothing to claim
+		}
+		return nil, fmt.Errorf("failed to claim sync job: %w", err)
+	}
+
+	job := convertSyncJob(dbJob)
+	return &job, nil
+}
+
+// UpdateProgress records job progress and refreshes its lock. It returns
+// ErrSyncJobLockLost when the job is no longer held by the worker that claimed it.
+func (r *SyncJobRepository) UpdateProgress(ctx context.Context, job *models.SyncJob) error {
+	updated, err := r.queries.UpdateSyncJobProgress(ctx, db.UpdateSyncJobProgressParams{
+		ID:            job.ID,
+		KlinesFetched: job.KlinesFetched,
+		LastDataTime:  job.LastDataTime,
+		LockedBy:      lockedBy(job),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update sync job progress: %w", err)
+	}
+	if updated == 0 {
+		return ErrSyncJobLockLost
+	}
+
+	return nil
+}
+
+// Heartbeat refreshes the lock of a running job, so it is not requeued as stale while a
+// slow page is still being fetched. It returns ErrSyncJobLockLost when the job is no
+// longer held by the worker that claimed it.
+func (r *SyncJobRepository) Heartbeat(ctx context.Context, job *models.SyncJob) error {
+	updated, err := r.queries.HeartbeatSyncJob(ctx, db.HeartbeatSyncJobParams{
+		ID:       job.ID,
+		LockedBy: lockedBy(job),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to refresh sync job lock: %w", err)
+	}
+	if updated == 0 {
+		return ErrSyncJobLockLost
+	}
+
+	return nil
+}
+
+// Complete marks a job as completed. It returns ErrSyncJobLockLost, leaving the job as
+// it is, when the job is no longer held by the worker that claimed it.
+func (r *SyncJobRepository) Complete(ctx context.Context, job *models.SyncJob) error {
+	completed, err := r.queries.CompleteSyncJob(ctx, db.CompleteSyncJobParams{
+		ID:       job.ID,
+		LockedBy: lockedBy(job),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to complete sync job: %w", err)
+	}
+	if completed == 0 {
+		return ErrSyncJobLockLost
+	}
+
+	job.State = "completed"
+	job.LastError = nil
+	return nil
+}
+
+// Fail records a job failure. The job goes back to pending after retryAfter until it has
+// used all its attempts, then it is marked failed. job.State is updated accordingly.
+// It returns ErrSyncJobLockLost, leaving the job as it is, when the job is no longer
+// held by the worker that claimed it.
+func (r *SyncJobRepository) Fail(
+	ctx context.Context,
+	job *models.SyncJob,
+	errorMessage string,
+	retryAfter time.Duration,
+) error {
+	runAfter := time.Now().Add(retryAfter).UnixMilli()
+	state, err := r.queries.FailSyncJob(ctx, db.FailSyncJobParams{
+		ID:        job.ID,
+		LastError: sql.NullString{String: errorMessage, Valid: true},
+		RunAfter:  runAfter,
+		LockedBy:  lockedBy(job),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrSyncJobLockLost
+		}
+		return fmt.Errorf("failed to record sync job failure: %w", err)
+	}
+
+	job.State = state
+	job.LastError = &errorMessage
+	job.RunAfter = runAfter
+	return nil
+}
+
+// RequeueStale releases running jobs whose lock has not been refreshed since lockedBefore.
+// It returns the number of released jobs.
+func (r *SyncJobRepository) RequeueStale(ctx context.Context, lockedBefore int64) (int64, error) {
+	count, err := r.queries.RequeueStaleSyncJobs(ctx, sql.NullInt64{Int64: lockedBefore, Valid: true})
+	if err != nil {
+		return 0, fmt.Errorf("failed to requeue stale sync jobs: %w", err)
+	}
+
+	return count, nil
+}
+
+// CountOpen counts pending and running jobs
+func (r *SyncJobRepository) CountOpen(ctx context.Context) (int64, error) {
+	count, err := r.queries.CountPendingSyncJobs(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count sync jobs: %w", err)
+	}
+
+	return count, nil
+}
+
+// List returns the most recently updated jobs, optionally filtered by state
+func (r *SyncJobRepository) List(ctx context.Context, state string, limit int) ([]models.SyncJob, error) {
+	dbJobs, err := r.queries.ListSyncJobs(ctx, db.ListSyncJobsParams{
+		State:    state,
+		RowLimit: int32(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sync jobs: %w", err)
+	}
+
+	jobs := make([]models.SyncJob, 0, len(dbJobs))
+	for _, dbJob := range dbJobs {
+		jobs = append(jobs, convertSyncJob(dbJob))
+	}
+
+	return jobs, nil
+}
+
+// lockedBy returns the worker holding job, as claimed by ClaimNext
+func lockedBy(job *models.SyncJob) sql.NullString {
+	if job.LockedBy == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: *job.LockedBy, Valid: true}
+}
+
+// convertSyncJob converts a database sync job to a model
+func convertSyncJob(dbJob db.SyncJob) models.SyncJob {
+	job := models.SyncJob{
+		ID:            dbJob.ID,
+		Symbol:        dbJob.Symbol,
+		DataType:      dbJob.DataType,
+		Interval:      dbJob.Interval,
+		State:         dbJob.State,
+		Attempts:      int(dbJob.Attempts),
+		MaxAttempts:   int(dbJob.MaxAttempts),
+		KlinesFetched: dbJob.KlinesFetched,
+		LastDataTime:  dbJob.LastDataTime,
+		RunAfter:      dbJob.RunAfter,
+		CreatedAt:     dbJob.CreatedAt,
+		UpdatedAt:     dbJob.UpdatedAt,
+	}
+
+	if dbJob.LastError.Valid {
+		job.LastError = &dbJob.LastError.String
+	}
+	if dbJob.LockedBy.Valid {
+		job.LockedBy = &dbJob.LockedBy.String
+	}
+	if dbJob.LockedAt.Valid {
+		job.LockedAt = &dbJob.LockedAt.Int64
+	}
+
+	return job
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/binance-live/internal/models"
+)
+
+const syncJobTestSymbol = "TESTJOBUSDT"
+
+// newSyncJobTestRepository returns a repository with one pending job of the test
+// symbol, whose rows are removed before and after the test
+func newSyncJobTestRepository(t *testing.T) *SyncJobRepository {
+	t.Helper()
+
+	db := testDatabase(t)
+	repo := NewSyncJobRepository(db)
+	cleanup := func() {
+		if _, err := db.Pool.Exec(context.Background(), `DELETE FROM sync_jobs WHERE symbol = $1`, syncJobTestSymbol); err != nil {
+			t.Fatalf("failed to delete test sync jobs: %v", err)
+		}
+	}
+	cleanup()
+	t.Cleanup(cleanup)
+
+	created, err := repo.Enqueue(context.Background(), syncJobTestSymbol, "kline", "1m", 3)
+	if err != nil || !created {
+		t.Fatalf("failed to enqueue test job: created %v, %v", created, err)
+	}
+
+	return repo
+}
+
+// claimTestJob claims the test job for worker
+func claimTestJob(t *testing.T, repo *SyncJobRepository, worker string) *models.SyncJob {
+	t.Helper()
+
+	job, err := repo.ClaimNext(context.Background(), worker)
+	if err != nil {
+		t.Fatalf("failed to claim job: %v", err)
+	}
+	if job == nil || job.Symbol != syncJobTestSymbol {
+		t.Fatalf("claimed %v, want the %s job; the test database has other pending jobs", job, syncJobTestSymbol)
+	}
+	return job
+}
+
+func TestSyncJobLockLostToAnotherWorker(t *testing.T) {
+	ctx := context.Background()
+	repo := newSyncJobTestRepository(t)
+
+	first := claimTestJob(t, repo, "worker-1")
+	if err := repo.Heartbeat(ctx, first); err != nil {
+		t.Fatalf("failed to refresh lock: %v", err)
+	}
+
+	// The first worker looks stale, and the job is handed to a second one
+	if _, err := repo.RequeueStale(ctx, time.Now().Add(time.Minute).UnixMilli()); err != nil {
+		t.Fatalf("failed to requeue stale jobs: %v", err)
+	}
+	second := claimTestJob(t, repo, "worker-2")
+	if second.ID != first.ID || second.Attempts != 2 {
+		t.Fatalf("claimed job %d attempt %d, want job %d attempt 2", second.ID, second.Attempts, first.ID)
+	}
+
+	// The first worker can no longer touch the job
+	if err := repo.Heartbeat(ctx, first); !errors.Is(err, ErrSyncJobLockLost) {
+		t.Errorf("got heartbeat error %v, want ErrSyncJobLockLost", err)
+	}
+	if err := repo.UpdateProgress(ctx, first); !errors.Is(err, ErrSyncJobLockLost) {
+		t.Errorf("got progress error %v, want ErrSyncJobLockLost", err)
+	}
+	if err := repo.Fail(ctx, first, "first worker failed", 0); !errors.Is(err, ErrSyncJobLockLost) {
+		t.Errorf("got fail error %v, want ErrSyncJobLockLost", err)
+	}
+	if err := repo.Complete(ctx, first); !errors.Is(err, ErrSyncJobLockLost) {
+		t.Errorf("got complete error %v, want ErrSyncJobLockLost", err)
+	}
+
+	// The second worker still holds it
+	second.KlinesFetched = 10
+	if err := repo.UpdateProgress(ctx, second); err != nil {
+		t.Errorf("failed to update progress: %v", err)
+	}
+	if err := repo.Complete(ctx, second); err != nil {
+		t.Fatalf("failed to complete job: %v", err)
+	}
+	if second.State != "completed" {
+		t.Errorf("got state %q, want completed", second.State)
+	}
+
+	// A finished job is not running any more
+	if err := repo.Complete(ctx, second); !errors.Is(err, ErrSyncJobLockLost) {
+		t.Errorf("got error %v completing twice, want ErrSyncJobLockLost", err)
+	}
+}
+
+func TestSyncJobFailRequeues(t *testing.T) {
+	ctx := context.Background()
+	repo := newSyncJobTestRepository(t)
+
+	job := claimTestJob(t, repo, "worker-1")
+	if err := repo.Fail(ctx, job, "page failed", 0); err != nil {
+		t.Fatalf("failed to record failure: %v", err)
+	}
+	if job.State != "pending" || job.LastError == nil || *job.LastError != "page failed" {
+		t.Errorf("got state %q with error %v, want pending with the failure", job.State, job.LastError)
+	}
+}
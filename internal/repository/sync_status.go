@@ -11,6 +11,15 @@ import (
 	"github.com/jackc/pgx/v5"
 )
 
+// SyncStatusFilter selects sync statuses. Empty fields match every value.
+type SyncStatusFilter struct {
+	Market     string
+	Symbol     string
+	DataType   string
+	Interval   string
+	ActiveOnly bool // List only streams of active symbols
+}
+
 // SyncStatusRepository handles sync status operations
 type SyncStatusRepository struct {
 	database *database.Database
@@ -25,21 +34,13 @@ func NewSyncStatusRepository(database *database.Database) *SyncStatusRepository
 	}
 }
 
-// GetSyncStatus retrieves the sync status for a symbol and data type
-func (r *SyncStatusRepository) GetSyncStatus(
-	ctx context.Context,
-	symbol, dataType string,
-	interval *string,
-) (*models.SyncStatus, error) {
-	var intervalParam sql.NullString
-	if interval != nil {
-		intervalParam = sql.NullString{String: *interval, Valid: true}
-	}
-
+// GetSyncStatus retrieves the sync status of a stream, or nil if it has none
+func (r *SyncStatusRepository) GetSyncStatus(ctx context.Context, key models.StreamKey) (*models.SyncStatus, error) {
 	dbStatus, err := r.queries.GetSyncStatus(ctx, db.GetSyncStatusParams{
-		Symbol:   symbol,
-		DataType: dataType,
-		Interval: intervalParam,
+		Market:   key.Market,
+		Symbol:   key.Symbol,
+		DataType: key.DataType,
+		Interval: key.Interval,
 	})
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -48,41 +49,22 @@ func (r *SyncStatusRepository) GetSyncStatus(
 		return nil, fmt.Errorf("failed to get sync status: %w", err)
 	}
 
-	status := &models.SyncStatus{
-		Symbol:       dbStatus.Symbol,
-		DataType:     dbStatus.DataType,
-		LastSyncTime: dbStatus.LastSyncTime,
-		LastDataTime: dbStatus.LastDataTime,
-		Status:       dbStatus.Status,
-		UpdatedAt:    dbStatus.UpdatedAt,
-	}
-
-	if dbStatus.Interval.Valid {
-		status.Interval = &dbStatus.Interval.String
-	}
-	if dbStatus.ErrorMessage.Valid {
-		status.ErrorMessage = &dbStatus.ErrorMessage.String
-	}
-
-	return status, nil
+	status := convertSyncStatus(dbStatus)
+	return &status, nil
 }
 
 // UpsertSyncStatus inserts or updates sync status
 func (r *SyncStatusRepository) UpsertSyncStatus(ctx context.Context, status *models.SyncStatus) error {
-	var intervalParam sql.NullString
-	if status.Interval != nil {
-		intervalParam = sql.NullString{String: *status.Interval, Valid: true}
-	}
-
 	var errorMessageParam sql.NullString
 	if status.ErrorMessage != nil {
 		errorMessageParam = sql.NullString{String: *status.ErrorMessage, Valid: true}
 	}
 
 	err := r.queries.UpsertSyncStatus(ctx, db.UpsertSyncStatusParams{
+		Market:       status.Market,
 		Symbol:       status.Symbol,
 		DataType:     status.DataType,
-		Interval:     intervalParam,
+		Interval:     status.Interval,
 		LastSyncTime: status.LastSyncTime,
 		LastDataTime: status.LastDataTime,
 		Status:       status.Status,
@@ -96,58 +78,126 @@ func (r *SyncStatusRepository) UpsertSyncStatus(ctx context.Context, status *mod
 	return nil
 }
 
-// UpdateLastDataTime updates the last data time for a sync status
-func (r *SyncStatusRepository) UpdateLastDataTime(
-	ctx context.Context,
-	symbol, dataType string,
-	interval *string,
-	lastDataTime int64,
-) error {
-	var intervalParam sql.NullString
-	if interval != nil {
-		intervalParam = sql.NullString{String: *interval, Valid: true}
-	}
-
-	err := r.queries.UpdateLastDataTime(ctx, db.UpdateLastDataTimeParams{
-		Symbol:       symbol,
-		DataType:     dataType,
-		Interval:     intervalParam,
+// RecordProgress records that a stream has data up to lastDataTime, creating it as
+// active. The stored data time never moves backwards, so late or out-of-order events are
+// harmless. The status and error of an existing stream are left alone: they belong to the
+// REST sync, whose errors on the same key must stay visible until it succeeds again.
+func (r *SyncStatusRepository) RecordProgress(ctx context.Context, key models.StreamKey, lastDataTime int64) error {
+	err := r.queries.RecordSyncProgress(ctx, db.RecordSyncProgressParams{
+		Market:       key.Market,
+		Symbol:       key.Symbol,
+		DataType:     key.DataType,
+		Interval:     key.Interval,
 		LastDataTime: lastDataTime,
 	})
 	if err != nil {
-		return fmt.Errorf("failed to update last data time: %w", err)
+		return fmt.Errorf("failed to record sync progress of %s: %w", key, err)
 	}
 
 	return nil
 }
 
-// GetAllSyncStatuses retrieves all sync statuses for active symbols
-func (r *SyncStatusRepository) GetAllSyncStatuses(ctx context.Context) ([]models.SyncStatus, error) {
-	dbStatuses, err := r.queries.GetAllSyncStatuses(ctx)
+// List retrieves the sync statuses matching filter, ordered by stream key
+func (r *SyncStatusRepository) List(ctx context.Context, filter SyncStatusFilter) ([]models.SyncStatus, error) {
+	dbStatuses, err := r.queries.ListSyncStatuses(ctx, db.ListSyncStatusesParams{
+		Market:     filter.Market,
+		Symbol:     filter.Symbol,
+		DataType:   filter.DataType,
+		Interval:   filter.Interval,
+		ActiveOnly: filter.ActiveOnly,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to query sync statuses: %w", err)
 	}
 
 	statuses := make([]models.SyncStatus, 0, len(dbStatuses))
 	for _, dbStatus := range dbStatuses {
-		status := models.SyncStatus{
-			Symbol:       dbStatus.Symbol,
-			DataType:     dbStatus.DataType,
-			LastSyncTime: dbStatus.LastSyncTime,
-			LastDataTime: dbStatus.LastDataTime,
-			Status:       dbStatus.Status,
-			UpdatedAt:    dbStatus.UpdatedAt,
-		}
+		statuses = append(statuses, convertSyncStatus(dbStatus))
+	}
 
-		if dbStatus.Interval.Valid {
-			status.Interval = &dbStatus.Interval.String
-		}
-		if dbStatus.ErrorMessage.Valid {
-			status.ErrorMessage = &dbStatus.ErrorMessage.String
-		}
+	return statuses, nil
+}
 
-		statuses = append(statuses, status)
+// Reset clears the progress and errors of the streams matching filter, so the next sync
+// starts from the configured look-back window. It returns the number of streams reset.
+func (r *SyncStatusRepository) Reset(ctx context.Context, filter SyncStatusFilter) (int64, error) {
+	reset, err := r.queries.ResetSyncStatuses(ctx, db.ResetSyncStatusesParams{
+		Market:   filter.Market,
+		Symbol:   filter.Symbol,
+		DataType: filter.DataType,
+		Interval: filter.Interval,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to reset sync statuses: %w", err)
 	}
 
-	return statuses, nil
+	return reset, nil
+}
+
+// Delete removes the sync statuses matching filter and returns the number removed
+func (r *SyncStatusRepository) Delete(ctx context.Context, filter SyncStatusFilter) (int64, error) {
+	deleted, err := r.queries.DeleteSyncStatuses(ctx, db.DeleteSyncStatusesParams{
+		Market:   filter.Market,
+		Symbol:   filter.Symbol,
+		DataType: filter.DataType,
+		Interval: filter.Interval,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete sync statuses: %w", err)
+	}
+
+	return deleted, nil
+}
+
+// SetError marks a stream as failed with the given reason
+func (r *SyncStatusRepository) SetError(ctx context.Context, key models.StreamKey, errorMessage string) error {
+	err := r.queries.SetSyncStatusError(ctx, db.SetSyncStatusErrorParams{
+		Market:       key.Market,
+		Symbol:       key.Symbol,
+		DataType:     key.DataType,
+		Interval:     key.Interval,
+		ErrorMessage: sql.NullString{String: errorMessage, Valid: true},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set sync status error: %w", err)
+	}
+
+	return nil
+}
+
+// ClearError returns a failed stream to active after a successful sync
+func (r *SyncStatusRepository) ClearError(ctx context.Context, key models.StreamKey) error {
+	err := r.queries.ClearSyncStatusError(ctx, db.ClearSyncStatusErrorParams{
+		Market:   key.Market,
+		Symbol:   key.Symbol,
+		DataType: key.DataType,
+		Interval: key.Interval,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to clear sync status error: %w", err)
+	}
+
+	return nil
+}
+
+// convertSyncStatus converts a sync_status row to the model
+func convertSyncStatus(dbStatus db.SyncStatus) models.SyncStatus {
+	status := models.SyncStatus{
+		StreamKey: models.StreamKey{
+			Market:   dbStatus.Market,
+			Symbol:   dbStatus.Symbol,
+			DataType: dbStatus.DataType,
+			Interval: dbStatus.Interval,
+		},
+		LastSyncTime: dbStatus.LastSyncTime,
+		LastDataTime: dbStatus.LastDataTime,
+		Status:       dbStatus.Status,
+		UpdatedAt:    dbStatus.UpdatedAt,
+	}
+
+	if dbStatus.ErrorMessage.Valid {
+		status.ErrorMessage = &dbStatus.ErrorMessage.String
+	}
+
+	return status
 }
@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/binance-live/internal/models"
+)
+
+// syncStatusTestFilter selects the rows written by the sync status tests
+var syncStatusTestFilter = SyncStatusFilter{Market: models.MarketSpot, Symbol: "TESTSYNCUSDT"}
+
+// newSyncStatusTestRepository returns a repository whose test rows are removed before
+// and after the test
+func newSyncStatusTestRepository(t *testing.T) *SyncStatusRepository {
+	t.Helper()
+
+	repo := NewSyncStatusRepository(testDatabase(t))
+	cleanup := func() {
+		if _, err := repo.Delete(context.Background(), syncStatusTestFilter); err != nil {
+			t.Fatalf("failed to delete test sync statuses: %v", err)
+		}
+	}
+	cleanup()
+	t.Cleanup(cleanup)
+
+	return repo
+}
+
+func getSyncStatus(t *testing.T, repo *SyncStatusRepository, key models.StreamKey) *models.SyncStatus {
+	t.Helper()
+
+	status, err := repo.GetSyncStatus(context.Background(), key)
+	if err != nil {
+		t.Fatalf("failed to get sync status: %v", err)
+	}
+	if status == nil {
+		t.Fatalf("no sync status for %s", key)
+	}
+	return status
+}
+
+func TestSyncStatusRecordProgress(t *testing.T) {
+	ctx := context.Background()
+	repo := newSyncStatusTestRepository(t)
+	key := models.KlineStreamKey("TESTSYNCUSDT", "1m")
+
+	if err := repo.RecordProgress(ctx, key, 2000); err != nil {
+		t.Fatalf("failed to record progress: %v", err)
+	}
+	status := getSyncStatus(t, repo, key)
+	if status.Status != "active" || status.LastDataTime != 2000 {
+		t.Errorf("got status %q with data time %d, want active with 2000", status.Status, status.LastDataTime)
+	}
+
+	// Late events never move the data time backwards
+	if err := repo.RecordProgress(ctx, key, 1000); err != nil {
+		t.Fatalf("failed to record progress: %v", err)
+	}
+	if status := getSyncStatus(t, repo, key); status.LastDataTime != 2000 {
+		t.Errorf("got data time %d after a late event, want 2000", status.LastDataTime)
+	}
+
+	if err := repo.RecordProgress(ctx, key, 3000); err != nil {
+		t.Fatalf("failed to record progress: %v", err)
+	}
+	if status := getSyncStatus(t, repo, key); status.LastDataTime != 3000 {
+		t.Errorf("got data time %d, want 3000", status.LastDataTime)
+	}
+}
+
+func TestSyncStatusRecordProgressKeepsError(t *testing.T) {
+	ctx := context.Background()
+	repo := newSyncStatusTestRepository(t)
+	key := models.KlineStreamKey("TESTSYNCUSDT", "1m")
+
+	if err := repo.RecordProgress(ctx, key, 1000); err != nil {
+		t.Fatalf("failed to record progress: %v", err)
+	}
+	if err := repo.SetError(ctx, key, "rest sync failed"); err != nil {
+		t.Fatalf("failed to set error: %v", err)
+	}
+
+	// Live progress on the same key must not hide the REST sync error
+	if err := repo.RecordProgress(ctx, key, 2000); err != nil {
+		t.Fatalf("failed to record progress: %v", err)
+	}
+	status := getSyncStatus(t, repo, key)
+	if status.Status != "error" || status.ErrorMessage == nil || *status.ErrorMessage != "rest sync failed" {
+		t.Errorf("got status %q with error %v, want the REST sync error", status.Status, status.ErrorMessage)
+	}
+	if status.LastDataTime != 2000 {
+		t.Errorf("got data time %d, want 2000", status.LastDataTime)
+	}
+
+	if err := repo.ClearError(ctx, key); err != nil {
+		t.Fatalf("failed to clear error: %v", err)
+	}
+	if status := getSyncStatus(t, repo, key); status.Status != "active" || status.ErrorMessage != nil {
+		t.Errorf("got status %q with error %v after clearing, want active", status.Status, status.ErrorMessage)
+	}
+}
+
+func TestSyncStatusListResetDelete(t *testing.T) {
+	ctx := context.Background()
+	repo := newSyncStatusTestRepository(t)
+
+	keys := []models.StreamKey{
+		models.KlineStreamKey("TESTSYNCUSDT", "1h"),
+		models.KlineStreamKey("TESTSYNCUSDT", "1m"),
+		{Market: models.MarketSpot, Symbol: "TESTSYNCUSDT", DataType: models.DataTypeTrade},
+	}
+	for i, key := range keys {
+		if err := repo.RecordProgress(ctx, key, int64(1000*(i+1))); err != nil {
+			t.Fatalf("failed to record progress: %v", err)
+		}
+	}
+	if err := repo.SetError(ctx, keys[1], "failed"); err != nil {
+		t.Fatalf("failed to set error: %v", err)
+	}
+
+	statuses, err := repo.List(ctx, syncStatusTestFilter)
+	if err != nil {
+		t.Fatalf("failed to list sync statuses: %v", err)
+	}
+	if len(statuses) != len(keys) {
+		t.Fatalf("listed %d statuses, want %d", len(statuses), len(keys))
+	}
+	for i, status := range statuses {
+		if status.StreamKey != keys[i] {
+			t.Errorf("status %d is %s, want %s in stream key order", i, status.StreamKey, keys[i])
+		}
+	}
+
+	klineFilter := syncStatusTestFilter
+	klineFilter.DataType = models.DataTypeKline
+	klines, err := repo.List(ctx, klineFilter)
+	if err != nil {
+		t.Fatalf("failed to list sync statuses: %v", err)
+	}
+	if len(klines) != 2 {
+		t.Errorf("listed %d kline statuses, want 2", len(klines))
+	}
+
+	// Reset clears progress and errors of the matching streams only
+	minuteFilter := klineFilter
+	minuteFilter.Interval = "1m"
+	reset, err := repo.Reset(ctx, minuteFilter)
+	if err != nil {
+		t.Fatalf("failed to reset sync statuses: %v", err)
+	}
+	if reset != 1 {
+		t.Errorf("reset %d statuses, want 1", reset)
+	}
+	status := getSyncStatus(t, repo, keys[1])
+	if status.LastDataTime != 0 || status.Status != "active" || status.ErrorMessage != nil {
+		t.Errorf("got data time %d, status %q and error %v after reset, want 0, active and none",
+			status.LastDataTime, status.Status, status.ErrorMessage)
+	}
+	if status := getSyncStatus(t, repo, keys[0]); status.LastDataTime != 1000 {
+		t.Errorf("reset also changed %s", keys[0])
+	}
+
+	deleted, err := repo.Delete(ctx, klineFilter)
+	if err != nil {
+		t.Fatalf("failed to delete sync statuses: %v", err)
+	}
+	if deleted != 2 {
+		t.Errorf("deleted %d statuses, want 2", deleted)
+	}
+	remaining, err := repo.List(ctx, syncStatusTestFilter)
+	if err != nil {
+		t.Fatalf("failed to list sync statuses: %v", err)
+	}
+	if len(remaining) != 1 || remaining[0].StreamKey != keys[2] {
+		t.Errorf("got %v after deleting klines, want only %s", remaining, keys[2])
+	}
+}
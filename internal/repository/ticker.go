@@ -9,6 +9,7 @@ import (
 	"github.com/binance-live/internal/database"
 	"github.com/binance-live/internal/db"
 	"github.com/binance-live/internal/models"
+	"github.com/jackc/pgx/v5"
 )
 
 // TickerRepository handles ticker data operations
@@ -224,3 +225,79 @@ func (r *TickerRepository) executeBatchInsert(ctx context.Context, tickers []mod
 	committed = true
 	return nil
 }
+
+// GetLatestTicker retrieves the newest stored ticker of a symbol, or nil if it has none
+func (r *TickerRepository) GetLatestTicker(ctx context.Context, symbol string) (*models.Ticker, error) {
+	dbTicker, err := r.queries.GetLatestTicker(ctx, symbol)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil // No data found
+		}
+		return nil, fmt.Errorf("failed to get latest ticker: %w", err)
+	}
+
+	ticker := convertTicker(dbTicker)
+	return &ticker, nil
+}
+
+// GetAllLatestTickers retrieves the newest stored ticker of every symbol, ordered by symbol
+func (r *TickerRepository) GetAllLatestTickers(ctx context.Context) ([]models.Ticker, error) {
+	dbTickers, err := r.queries.GetAllLatestTickers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query latest tickers: %w", err)
+	}
+
+	tickers := make([]models.Ticker, 0, len(dbTickers))
+	for _, dbTicker := range dbTickers {
+		tickers = append(tickers, convertTicker(dbTicker))
+	}
+
+	return tickers, nil
+}
+
+// convertTicker converts a tickers row to the model
+func convertTicker(dbTicker db.Ticker) models.Ticker {
+	ticker := models.Ticker{
+		Symbol:    dbTicker.Symbol,
+		Timestamp: dbTicker.Timestamp,
+		Price:     dbTicker.Price,
+		CreatedAt: dbTicker.CreatedAt,
+	}
+
+	if dbTicker.BidPrice.Valid {
+		ticker.BidPrice = &dbTicker.BidPrice.Float64
+	}
+	if dbTicker.BidQty.Valid {
+		ticker.BidQty = &dbTicker.BidQty.Float64
+	}
+	if dbTicker.AskPrice.Valid {
+		ticker.AskPrice = &dbTicker.AskPrice.Float64
+	}
+	if dbTicker.AskQty.Valid {
+		ticker.AskQty = &dbTicker.AskQty.Float64
+	}
+	if dbTicker.Volume24h.Valid {
+		ticker.Volume24h = &dbTicker.Volume24h.Float64
+	}
+	if dbTicker.QuoteVolume24h.Valid {
+		ticker.QuoteVolume24h = &dbTicker.QuoteVolume24h.Float64
+	}
+	if dbTicker.PriceChange24h.Valid {
+		ticker.PriceChange24h = &dbTicker.PriceChange24h.Float64
+	}
+	if dbTicker.PriceChangePercent24h.Valid {
+		ticker.PriceChangePercent24h = &dbTicker.PriceChangePercent24h.Float64
+	}
+	if dbTicker.High24h.Valid {
+		ticker.High24h = &dbTicker.High24h.Float64
+	}
+	if dbTicker.Low24h.Valid {
+		ticker.Low24h = &dbTicker.Low24h.Float64
+	}
+	if dbTicker.TradesCount24h.Valid {
+		tradesCount24h := int(dbTicker.TradesCount24h.Int32)
+		ticker.TradesCount24h = &tradesCount24h
+	}
+
+	return ticker
+}
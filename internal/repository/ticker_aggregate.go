@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+)
+
+// Ticker1mView is the continuous aggregate summarizing stored tickers per minute
+const Ticker1mView = "ticker_1m"
+
+// ticker1mViewSQL creates the per-minute summary of the stored tickers: OHLC of the last
+// price and statistics of the bid/ask spread, in quote units and in basis points of the
+// mid price. Real-time aggregation keeps the current minute visible before materialization.
+const ticker1mViewSQL = `
+CREATE MATERIALIZED VIEW IF NOT EXISTS ticker_1m
+WITH (timescaledb.continuous, timescaledb.materialized_only = false) AS
+SELECT symbol,
+       time_bucket(60000::BIGINT, timestamp) AS bucket,
+       first(price, timestamp) AS open_price,
+       max(price) AS high_price,
+       min(price) AS low_price,
+       last(price, timestamp) AS close_price,
+       avg(ask_price - bid_price) AS avg_spread,
+       min(ask_price - bid_price) AS min_spread,
+       max(ask_price - bid_price) AS max_spread,
+       avg((ask_price - bid_price) / NULLIF((ask_price + bid_price) / 2, 0) * 10000) AS avg_spread_bps,
+       last(volume_24h, timestamp) AS volume_24h,
+       last(quote_volume_24h, timestamp) AS quote_volume_24h,
+       count(*) AS sample_count
+FROM tickers
+GROUP BY symbol, time_bucket(60000::BIGINT, timestamp)
+WITH NO DATA
+`
+
+// EnsureTicker1mView creates the ticker_1m continuous aggregate and its refresh policy
+func (r *TickerRepository) EnsureTicker1mView(ctx context.Context) error {
+	if err := setIntegerNowFunc(ctx, r.database, "tickers"); err != nil {
+		return err
+	}
+
+	if _, err := r.database.Pool.Exec(ctx, ticker1mViewSQL); err != nil {
+		return fmt.Errorf("failed to create continuous aggregate %s: %w", Ticker1mView, err)
+	}
+
+	// Refresh every minute, looking back an hour and leaving the open minute
+	// to real-time aggregation
+	_, err := r.database.Pool.Exec(ctx,
+		`SELECT add_continuous_aggregate_policy($1::regclass,
+			start_offset => 3600000::BIGINT,
+			end_offset => 60000::BIGINT,
+			schedule_interval => INTERVAL '1 minute',
+			if_not_exists => TRUE)`,
+		Ticker1mView,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to add refresh policy for %s: %w", Ticker1mView, err)
+	}
+
+	return nil
+}
+
+// RefreshTicker1mView materializes ticker_1m for timestamps in [startTime, endTime)
+func (r *TickerRepository) RefreshTicker1mView(ctx context.Context, startTime, endTime int64) error {
+	_, err := r.database.Pool.Exec(ctx,
+		`CALL refresh_continuous_aggregate($1::regclass, $2::BIGINT, $3::BIGINT)`,
+		Ticker1mView, startTime, endTime,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to refresh %s: %w", Ticker1mView, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/binance-live/internal/database"
+	"github.com/binance-live/internal/db"
+	"github.com/binance-live/internal/models"
+)
+
+// TradeRepository handles aggregated trade data operations
+type TradeRepository struct {
+	database *database.Database
+	queries  *db.Queries
+}
+
+// NewTradeRepository creates a new trade repository
+func NewTradeRepository(database *database.Database) *TradeRepository {
+	return &TradeRepository{
+		database: database,
+		queries:  db.New(database.Pool),
+	}
+}
+
+// Insert inserts a single trade record
+func (r *TradeRepository) Insert(ctx context.Context, trade *models.Trade) error {
+	row, err := r.queries.InsertTrade(ctx, db.InsertTradeParams{
+		Symbol:        trade.Symbol,
+		TradeID:       trade.TradeID,
+		Timestamp:     trade.Timestamp,
+		Price:         trade.Price,
+		Quantity:      trade.Quantity,
+		QuoteQuantity: trade.QuoteQuantity,
+		IsBuyerMaker:  trade.IsBuyerMaker,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to insert trade: %w", err)
+	}
+
+	trade.ID = row.ID
+	trade.CreatedAt = row.CreatedAt
+	return nil
+}
+
+// tradeColumns lists the trades columns written by BulkInsert
+var tradeColumns = []string{
+	"symbol", "trade_id", "timestamp", "price", "quantity", "quote_quantity", "is_buyer_maker",
+}
+
+// BulkInsert loads trades with COPY, skipping trades that are already stored.
+// It returns the number of new rows.
+func (r *TradeRepository) BulkInsert(ctx context.Context, trades []models.Trade) (int64, error) {
+	rows := make([][]any, 0, len(trades))
+	for _, trade := range trades {
+		rows = append(rows, []any{
+			trade.Symbol,
+			trade.TradeID,
+			trade.Timestamp,
+			trade.Price,
+			trade.Quantity,
+			trade.QuoteQuantity,
+			trade.IsBuyerMaker,
+		})
+	}
+
+	inserted, err := bulkInsert(ctx, r.database, "trades", tradeColumns, "symbol, trade_id, timestamp", rows)
+	if err != nil {
+		return 0, fmt.Errorf("failed to bulk insert trades: %w", err)
+	}
+
+	return inserted, nil
+}
+
+// GetTradesPage reads at most limit trades with timestamps before endTime that follow
+// the trade (afterTime, afterTradeID), ordered by timestamp and trade ID. Start with
+// afterTradeID -1 to include trades at afterTime; continue from the last trade returned.
+func (r *TradeRepository) GetTradesPage(
+	ctx context.Context,
+	symbol string,
+	afterTime, afterTradeID, endTime int64,
+	limit int,
+) ([]models.Trade, error) {
+	dbTrades, err := r.queries.GetTradesPage(ctx, db.GetTradesPageParams{
+		Symbol:       symbol,
+		AfterTime:    afterTime,
+		EndTime:      endTime,
+		AfterTradeID: afterTradeID,
+		RowLimit:     int32(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query trades page: %w", err)
+	}
+
+	trades := make([]models.Trade, 0, len(dbTrades))
+	for _, dbTrade := range dbTrades {
+		trades = append(trades, models.Trade{
+			ID:            dbTrade.ID,
+			Symbol:        dbTrade.Symbol,
+			TradeID:       dbTrade.TradeID,
+			Timestamp:     dbTrade.Timestamp,
+			Price:         dbTrade.Price,
+			Quantity:      dbTrade.Quantity,
+			QuoteQuantity: dbTrade.QuoteQuantity,
+			IsBuyerMaker:  dbTrade.IsBuyerMaker,
+			CreatedAt:     dbTrade.CreatedAt,
+		})
+	}
+
+	return trades, nil
+}
@@ -0,0 +1,389 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/binance-live/internal/binance"
+	"github.com/binance-live/internal/models"
+	"go.uber.org/zap"
+)
+
+// archiveBatchSize is the number of archive rows bulk loaded per COPY
+const archiveBatchSize = 10000
+
+// ArchiveImportOptions selects which public data archive files to import
+type ArchiveImportOptions struct {
+	Source   string              // Local directory or base URL such as binance.DefaultArchiveURL
+	Symbol   string              // Required for URL sources, optional filter for directories
+	Kind     binance.ArchiveKind // klines or aggTrades
+	Interval binance.Interval    // Required for klines from URL sources, optional filter for directories
+	From     time.Time           // Months from this one are imported (zero: no lower bound for directories)
+	To       time.Time           // Months before this one are imported (zero: up to now)
+	SyncTail bool                // Fetch klines newer than the imported files through REST
+}
+
+// ArchiveImportResult summarizes an archive import
+type ArchiveImportResult struct {
+	Files    int   // Archive files imported
+	Skipped  int   // Files not published yet
+	Rows     int64 // CSV rows read
+	Inserted int64 // Rows that were not already stored
+}
+
+// archiveSeries identifies the kline series a file belongs to, for the REST tail handoff
+type archiveSeries struct {
+	symbol   string
+	interval binance.Interval
+}
+
+// ImportArchives bulk loads klines or aggTrades from Binance public data archives
+// (data.binance.vision). Every file is verified against its .CHECKSUM before loading.
+// For kline imports with SyncTail set, sync_status is advanced to the newest imported
+// candle and the remaining tail is fetched through the regular REST sync.
+func (s *DataSyncService) ImportArchives(
+	ctx context.Context,
+	opts ArchiveImportOptions,
+	onFile func(file *binance.ArchiveFile, rows, inserted int64),
+) (*ArchiveImportResult, error) {
+	if opts.Kind != binance.ArchiveKindKlines && opts.Kind != binance.ArchiveKindAggTrades {
+		return nil, fmt.Errorf("unsupported archive kind: %q", opts.Kind)
+	}
+	if opts.To.IsZero() {
+		opts.To = time.Now().UTC()
+	}
+
+	result := &ArchiveImportResult{}
+	latest := make(map[archiveSeries]int64)
+
+	importFile := func(file *binance.ArchiveFile, path string, checksum []byte) error {
+		if err := binance.VerifyArchiveChecksum(path, checksum); err != nil {
+			return err
+		}
+
+		rows, inserted, lastOpenTime, err := s.importArchiveFile(ctx, file, path)
+		if err != nil {
+			return fmt.Errorf("failed to import %s: %w", file.Name, err)
+		}
+
+		result.Files++
+		result.Rows += rows
+		result.Inserted += inserted
+
+		if file.Kind == binance.ArchiveKindKlines {
+			series := archiveSeries{symbol: file.Symbol, interval: file.Interval}
+			if lastOpenTime > latest[series] {
+				latest[series] = lastOpenTime
+			}
+		}
+
+		s.logger.Info("Imported archive file",
+			zap.String("file", file.Name),
+			zap.Int64("rows", rows),
+			zap.Int64("inserted", inserted),
+		)
+		if onFile != nil {
+			onFile(file, rows, inserted)
+		}
+
+		return nil
+	}
+
+	var err error
+	if strings.HasPrefix(opts.Source, "http://") || strings.HasPrefix(opts.Source, "https://") {
+		err = s.importArchivesFromURL(ctx, opts, result, importFile)
+	} else {
+		err = s.importArchivesFromDir(opts, importFile)
+	}
+	if err != nil {
+		return result, err
+	}
+
+	if opts.SyncTail && opts.Kind == binance.ArchiveKindKlines {
+		for series, lastOpenTime := range latest {
+			if err := s.handOffToREST(ctx, series.symbol, series.interval, lastOpenTime); err != nil {
+				return result, err
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// importArchivesFromDir imports the matching *.zip files of a local directory in period order.
+// Each file needs a <name>.zip.CHECKSUM next to it.
+func (s *DataSyncService) importArchivesFromDir(
+	opts ArchiveImportOptions,
+	importFile func(file *binance.ArchiveFile, path string, checksum []byte) error,
+) error {
+	paths, err := filepath.Glob(filepath.Join(opts.Source, "*.zip"))
+	if err != nil {
+		return fmt.Errorf("failed to list archives: %w", err)
+	}
+
+	files := make([]*binance.ArchiveFile, 0, len(paths))
+	for _, path := range paths {
+		file, err := binance.ParseArchiveFileName(path)
+		if err != nil {
+			s.logger.Warn("Skipping unrecognized file", zap.String("path", path), zap.Error(err))
+			continue
+		}
+
+		if !archiveFileMatches(file, opts) {
+			continue
+		}
+		files = append(files, file)
+	}
+
+	if len(files) == 0 {
+		return fmt.Errorf("no matching %s archives in %s", opts.Kind, opts.Source)
+	}
+
+	// Periods sort chronologically as strings
+	sort.Slice(files, func(i, j int) bool {
+		if files[i].Symbol != files[j].Symbol {
+			return files[i].Symbol < files[j].Symbol
+		}
+		if files[i].Interval != files[j].Interval {
+			return files[i].Interval < files[j].Interval
+		}
+		return files[i].Period < files[j].Period
+	})
+
+	for _, file := range files {
+		path := filepath.Join(opts.Source, file.Name)
+		checksum, err := os.ReadFile(path + ".CHECKSUM")
+		if err != nil {
+			return fmt.Errorf("failed to read checksum for %s: %w", file.Name, err)
+		}
+
+		if err := importFile(file, path, checksum); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// importArchivesFromURL downloads and imports the monthly files between opts.From and opts.To.
+// Months without a published monthly file (such as the current one) fall back to daily files.
+func (s *DataSyncService) importArchivesFromURL(
+	ctx context.Context,
+	opts ArchiveImportOptions,
+	result *ArchiveImportResult,
+	importFile func(file *binance.ArchiveFile, path string, checksum []byte) error,
+) error {
+	if opts.Symbol == "" {
+		return fmt.Errorf("symbol is required for URL sources")
+	}
+	if opts.Kind == binance.ArchiveKindKlines && !opts.Interval.IsValid() {
+		return fmt.Errorf("interval is required for kline archives")
+	}
+	if opts.From.IsZero() {
+		return fmt.Errorf("from is required for URL sources")
+	}
+
+	dir, err := os.MkdirTemp("", "binance-archive-")
+	if err != nil {
+		return fmt.Errorf("failed to create download directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	client := &http.Client{Timeout: 30 * time.Minute}
+
+	download := func(period string) (bool, error) {
+		url := binance.ArchiveFileURL(opts.Source, opts.Symbol, opts.Kind, opts.Interval, period)
+		path, checksum, err := binance.DownloadArchive(ctx, client, url, dir)
+		if errors.Is(err, binance.ErrArchiveNotFound) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		defer os.Remove(path)
+
+		file, err := binance.ParseArchiveFileName(path)
+		if err != nil {
+			return false, err
+		}
+
+		return true, importFile(file, path, checksum)
+	}
+
+	from := binance.Interval1M.Truncate(opts.From)
+	for month := from; month.Before(opts.To); month = binance.Interval1M.Add(month, 1) {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		found, err := download(month.Format("2006-01"))
+		if err != nil {
+			return err
+		}
+		if found {
+			continue
+		}
+
+		s.logger.Info("Monthly archive not published, trying daily files",
+			zap.String("symbol", opts.Symbol),
+			zap.String("month", month.Format("2006-01")),
+		)
+
+		nextMonth := binance.Interval1M.Add(month, 1)
+		for day := month; day.Before(nextMonth) && day.Before(opts.To); day = day.AddDate(0, 0, 1) {
+			found, err := download(day.Format("2006-01-02"))
+			if err != nil {
+				return err
+			}
+			if !found {
+				result.Skipped++
+			}
+		}
+	}
+
+	return nil
+}
+
+// archiveFileMatches reports whether a directory file passes the import filters
+func archiveFileMatches(file *binance.ArchiveFile, opts ArchiveImportOptions) bool {
+	if file.Kind != opts.Kind {
+		return false
+	}
+	if opts.Symbol != "" && file.Symbol != opts.Symbol {
+		return false
+	}
+	if opts.Interval != "" && file.Interval != opts.Interval {
+		return false
+	}
+
+	layout := "2006-01"
+	if file.IsDaily() {
+		layout = "2006-01-02"
+	}
+	periodStart, err := time.Parse(layout, file.Period)
+	if err != nil {
+		return false
+	}
+
+	if !opts.From.IsZero() && periodStart.Before(binance.Interval1M.Truncate(opts.From)) {
+		return false
+	}
+
+	return periodStart.Before(opts.To)
+}
+
+// importArchiveFile streams one verified archive into the database.
+// It returns rows read, rows inserted and, for klines, the newest open time.
+func (s *DataSyncService) importArchiveFile(
+	ctx context.Context,
+	file *binance.ArchiveFile,
+	path string,
+) (int64, int64, int64, error) {
+	var rows, inserted, lastOpenTime int64
+
+	if file.Kind == binance.ArchiveKindAggTrades {
+		err := binance.ReadArchiveAggTrades(path, archiveBatchSize, func(batch []binance.AggTradeResponse) error {
+			trades := make([]models.Trade, 0, len(batch))
+			for _, t := range batch {
+				trades = append(trades, convertArchiveTrade(file.Symbol, &t))
+			}
+
+			n, err := s.tradeRepo.BulkInsert(ctx, trades)
+			if err != nil {
+				return err
+			}
+
+			rows += int64(len(batch))
+			inserted += n
+			return nil
+		})
+		return rows, inserted, 0, err
+	}
+
+	intervalName := file.Interval.String()
+	err := binance.ReadArchiveKlines(path, archiveBatchSize, func(batch []binance.KlineData) error {
+		klines := make([]models.Kline, 0, len(batch))
+		for i := range batch {
+			kline, err := s.convertToModelKline(file.Symbol, intervalName, &batch[i])
+			if err != nil {
+				return err
+			}
+			klines = append(klines, *kline)
+
+			if kline.OpenTime > lastOpenTime {
+				lastOpenTime = kline.OpenTime
+			}
+		}
+
+		n, err := s.klineRepo.BulkInsert(ctx, klines)
+		if err != nil {
+			return err
+		}
+
+		rows += int64(len(batch))
+		inserted += n
+		return nil
+	})
+
+	return rows, inserted, lastOpenTime, err
+}
+
+// handOffToREST advances sync_status to the newest imported candle, unless it is
+// already further ahead, and fetches the remaining tail through the REST sync
+func (s *DataSyncService) handOffToREST(ctx context.Context, symbol string, interval binance.Interval, lastOpenTime int64) error {
+	intervalName := interval.String()
+
+	key := models.KlineStreamKey(symbol, intervalName)
+	syncStatus, err := s.syncStatusRepo.GetSyncStatus(ctx, key)
+	if err != nil {
+		return fmt.Errorf("failed to get sync status: %w", err)
+	}
+
+	if syncStatus == nil || syncStatus.LastDataTime < lastOpenTime {
+		if err := s.syncStatusRepo.UpsertSyncStatus(ctx, &models.SyncStatus{
+			StreamKey:    key,
+			LastSyncTime: time.Now().UnixMilli(),
+			LastDataTime: lastOpenTime,
+			Status:       "active",
+			ErrorMessage: nil,
+			UpdatedAt:    time.Now().UnixMilli(),
+		}); err != nil {
+			return fmt.Errorf("failed to update sync status: %w", err)
+		}
+	}
+
+	s.logger.Info("Handing off to REST sync",
+		zap.String("symbol", symbol),
+		zap.String("interval", intervalName),
+		zap.Int64("from", lastOpenTime),
+	)
+
+	return s.syncKlinesForSymbol(ctx, symbol, interval, s.defaultSyncOptions())
+}
+
+// convertArchiveTrade converts an archived aggregate trade to a model trade
+func convertArchiveTrade(symbol string, t *binance.AggTradeResponse) models.Trade {
+	price, _ := strconv.ParseFloat(t.Price, 64)
+	quantity, _ := strconv.ParseFloat(t.Quantity, 64)
+
+	return models.Trade{
+		Symbol:        symbol,
+		TradeID:       t.AggTradeID,
+		Timestamp:     t.Timestamp,
+		Price:         price,
+		Quantity:      quantity,
+		QuoteQuantity: price * quantity,
+		IsBuyerMaker:  t.IsBuyerMaker,
+		CreatedAt:     time.Now().UnixMilli(),
+	}
+}
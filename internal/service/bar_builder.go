@@ -0,0 +1,281 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/binance-live/internal/binance"
+	"github.com/binance-live/internal/models"
+)
+
+// barMaxEmptyBars caps the empty time bars emitted for a quiet period; longer
+// periods without trades are skipped
+const barMaxEmptyBars = 1000
+
+// BarType selects what closes a bar
+type BarType string
+
+const (
+	BarTypeTime   BarType = "time"   // Fixed duration, aligned to the Unix epoch
+	BarTypeVolume BarType = "volume" // Base asset volume threshold
+	BarTypeTick   BarType = "tick"   // Number of trades threshold
+	BarTypeDollar BarType = "dollar" // Quote asset volume threshold
+)
+
+// BarSpec describes one kind of bar built from aggregated trades
+type BarSpec struct {
+	Type      BarType
+	Duration  time.Duration // Time bars only
+	Threshold float64       // Volume, tick and dollar bars only
+	value     string        // Duration or threshold as configured
+}
+
+// ParseBarSpec parses a bar spec such as "time:2m", "volume:100", "tick:500" or "dollar:1000000".
+// Durations take a s, m, h, d or w unit.
+func ParseBarSpec(value string) (BarSpec, error) {
+	kind, arg, ok := strings.Cut(strings.TrimSpace(value), ":")
+	if !ok || arg == "" {
+		return BarSpec{}, fmt.Errorf("invalid bar spec %q: expected <type>:<value>", value)
+	}
+
+	spec := BarSpec{Type: BarType(strings.ToLower(kind)), value: arg}
+	switch spec.Type {
+	case BarTypeTime:
+		d, err := parseBarDuration(arg)
+		if err != nil {
+			return BarSpec{}, fmt.Errorf("invalid bar spec %q: %w", value, err)
+		}
+		spec.Duration = d
+	case BarTypeVolume, BarTypeTick, BarTypeDollar:
+		threshold, err := strconv.ParseFloat(arg, 64)
+		if err != nil || threshold <= 0 || math.IsInf(threshold, 0) {
+			return BarSpec{}, fmt.Errorf("invalid bar spec %q: threshold must be a positive number", value)
+		}
+		spec.Threshold = threshold
+	default:
+		return BarSpec{}, fmt.Errorf("invalid bar spec %q: unknown bar type %q", value, kind)
+	}
+
+	// Names are stored in bars.spec VARCHAR(32)
+	if len(spec.Name()) > 32 {
+		return BarSpec{}, fmt.Errorf("invalid bar spec %q: name %q is too long", value, spec.Name())
+	}
+
+	return spec, nil
+}
+
+// ParseBarSpecs parses a list of bar specs, rejecting duplicate names
+func ParseBarSpecs(values []string) ([]BarSpec, error) {
+	specs := make([]BarSpec, 0, len(values))
+	seen := make(map[string]bool, len(values))
+	for _, value := range values {
+		spec, err := ParseBarSpec(value)
+		if err != nil {
+			return nil, err
+		}
+
+		if seen[spec.Name()] {
+			return nil, fmt.Errorf("duplicate bar spec %q", spec.Name())
+		}
+		seen[spec.Name()] = true
+		specs = append(specs, spec)
+	}
+
+	return specs, nil
+}
+
+// Name returns the name used in Redis channels and the bars table, e.g. 2m, vol100,
+// tick500 or dollar1000000
+func (s BarSpec) Name() string {
+	switch s.Type {
+	case BarTypeVolume:
+		return "vol" + s.value
+	case BarTypeTick:
+		return "tick" + s.value
+	case BarTypeDollar:
+		return "dollar" + s.value
+	default:
+		return s.value
+	}
+}
+
+// parseBarDuration parses a whole number of seconds, minutes, hours, days or weeks
+func parseBarDuration(value string) (time.Duration, error) {
+	units := map[byte]time.Duration{
+		's': time.Second,
+		'm': time.Minute,
+		'h': time.Hour,
+		'd': 24 * time.Hour,
+		'w': 7 * 24 * time.Hour,
+	}
+
+	unit, ok := units[value[len(value)-1]]
+	if !ok {
+		return 0, fmt.Errorf("duration %q needs a s, m, h, d or w unit", value)
+	}
+
+	n, err := strconv.Atoi(value[:len(value)-1])
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("duration %q must be a positive whole number of units", value)
+	}
+
+	return time.Duration(n) * unit, nil
+}
+
+// BarBuilder builds the bars of one spec for one symbol from aggregated trades.
+// It is not safe for concurrent use.
+type BarBuilder struct {
+	symbol    string
+	spec      BarSpec
+	name      string
+	since     int64       // Time bars opening before this missed trades and are dropped
+	bar       *models.Bar // Bar in progress
+	lastClose float64     // Price of the last trade, opens empty time bars
+}
+
+// NewBarBuilder creates a bar builder. Trades are expected from since onwards, so a
+// time bar that opened earlier is incomplete and never emitted.
+func NewBarBuilder(symbol string, spec BarSpec, since int64) *BarBuilder {
+	return &BarBuilder{
+		symbol: symbol,
+		spec:   spec,
+		name:   spec.Name(),
+		since:  since,
+	}
+}
+
+// Add applies an aggregated trade and returns the bars it closed, oldest first.
+// Trades older than the time bar in progress arrived too late and are ignored.
+func (b *BarBuilder) Add(trade *binance.WSAggTradeEvent) ([]models.Bar, error) {
+	price, err := strconv.ParseFloat(trade.Price, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid trade price %q: %w", trade.Price, err)
+	}
+	quantity, err := strconv.ParseFloat(trade.Quantity, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid trade quantity %q: %w", trade.Quantity, err)
+	}
+
+	var closed []models.Bar
+	if b.spec.Type == BarTypeTime {
+		closed = b.CloseUntil(trade.TradeTime)
+		if b.bar == nil {
+			b.bar = b.newTimeBar(b.bucket(trade.TradeTime))
+		}
+		if trade.TradeTime < b.bar.OpenTime {
+			return closed, nil
+		}
+	} else if b.bar == nil {
+		b.bar = &models.Bar{
+			Symbol:    b.symbol,
+			Spec:      b.name,
+			OpenTime:  trade.TradeTime,
+			CreatedAt: time.Now().UnixMilli(),
+		}
+	}
+
+	b.apply(trade, price, quantity)
+
+	if b.spec.Type != BarTypeTime && b.thresholdReached() {
+		closed = append(closed, *b.bar)
+		b.bar = nil
+	}
+
+	return closed, nil
+}
+
+// CloseUntil closes the time bars that ended before t (Unix milliseconds) and returns
+// them, oldest first. Quiet periods produce empty bars at the last price, as Binance
+// klines do. Calling it on the wall clock closes bars without waiting for the next trade.
+func (b *BarBuilder) CloseUntil(t int64) []models.Bar {
+	if b.spec.Type != BarTypeTime {
+		return nil
+	}
+
+	var closed []models.Bar
+	for b.bar != nil && b.bar.CloseTime < t {
+		if b.bar.OpenTime >= b.since {
+			closed = append(closed, *b.bar)
+		}
+
+		next := b.bar.CloseTime + 1
+		if (t-next)/b.spec.Duration.Milliseconds() > barMaxEmptyBars {
+			next = b.bucket(t)
+		}
+		b.bar = b.newTimeBar(next)
+	}
+
+	return closed
+}
+
+// bucket returns the open time of the time bar containing t
+func (b *BarBuilder) bucket(t int64) int64 {
+	width := b.spec.Duration.Milliseconds()
+	bucket := t - t%width
+	if t < 0 && t%width != 0 {
+		bucket -= width
+	}
+	return bucket
+}
+
+// newTimeBar opens an empty time bar at the last traded price
+func (b *BarBuilder) newTimeBar(openTime int64) *models.Bar {
+	return &models.Bar{
+		Symbol:     b.symbol,
+		Spec:       b.name,
+		OpenTime:   openTime,
+		CloseTime:  openTime + b.spec.Duration.Milliseconds() - 1,
+		OpenPrice:  b.lastClose,
+		HighPrice:  b.lastClose,
+		LowPrice:   b.lastClose,
+		ClosePrice: b.lastClose,
+		CreatedAt:  time.Now().UnixMilli(),
+	}
+}
+
+// apply adds a trade to the bar in progress
+func (b *BarBuilder) apply(trade *binance.WSAggTradeEvent, price, quantity float64) {
+	bar := b.bar
+	if bar.TradesCount == 0 {
+		bar.OpenPrice = price
+		bar.HighPrice = price
+		bar.LowPrice = price
+		bar.FirstTradeID = trade.AggTradeID
+	}
+
+	bar.HighPrice = math.Max(bar.HighPrice, price)
+	bar.LowPrice = math.Min(bar.LowPrice, price)
+	bar.ClosePrice = price
+	bar.Volume += quantity
+	bar.QuoteVolume += price * quantity
+	bar.TradesCount += int(trade.LastTradeID - trade.FirstTradeID + 1)
+	bar.LastTradeID = trade.AggTradeID
+
+	// The buyer is the taker unless it is the maker
+	if !trade.IsBuyerMaker {
+		bar.TakerBuyVolume += quantity
+		bar.TakerBuyQuoteVolume += price * quantity
+	}
+
+	if b.spec.Type != BarTypeTime {
+		bar.CloseTime = trade.TradeTime
+	}
+	b.lastClose = price
+}
+
+// thresholdReached reports whether the volume, tick or dollar bar in progress is full
+func (b *BarBuilder) thresholdReached() bool {
+	switch b.spec.Type {
+	case BarTypeVolume:
+		return b.bar.Volume >= b.spec.Threshold
+	case BarTypeTick:
+		return float64(b.bar.TradesCount) >= b.spec.Threshold
+	case BarTypeDollar:
+		return b.bar.QuoteVolume >= b.spec.Threshold
+	default:
+		return false
+	}
+}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/binance-live/internal/binance"
+	"github.com/binance-live/internal/models"
+)
+
+// The fixtures are synthetic, not captured from Binance: five minutes of BTCUSDT
+// aggregated trades written by hand in the format of the REST aggTrades endpoint, and
+// the 1m klines Binance would report for them, computed by hand in the format of the
+// klines endpoint. They pin down Binance's kline rules the builder has to follow: trades
+// on the first and last millisecond of a minute, aggregated trades sharing a
+// millisecond, aggregated trades of several trades, taker buy volume and a minute
+// without trades. Comparing with real Binance data is what binance-cli bars check does.
+const (
+	barFixtureSymbol = "BTCUSDT"
+	barFixtureStart  = int64(1704067200000) // 2024-01-01T00:00:00Z
+	barFixtureEnd    = int64(1704067500000) // Five minutes later
+)
+
+// readJSONFixture decodes testdata/bars/name into v
+func readJSONFixture(t *testing.T, name string, v any) {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("testdata", "bars", name))
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		t.Fatalf("failed to decode fixture %s: %v", name, err)
+	}
+}
+
+// fixtureKlines reads the 1m klines of the fixture trades
+func fixtureKlines(t *testing.T) []models.Kline {
+	t.Helper()
+
+	var responses []binance.KlineResponse
+	readJSONFixture(t, "synthetic-1m-klines.json", &responses)
+
+	s := &DataSyncService{}
+	klines := make([]models.Kline, 0, len(responses))
+	for _, response := range responses {
+		data, err := binance.ParseKlineResponse(response)
+		if err != nil {
+			t.Fatalf("failed to parse kline: %v", err)
+		}
+		kline, err := s.convertToModelKline(barFixtureSymbol, "1m", data)
+		if err != nil {
+			t.Fatalf("failed to convert kline: %v", err)
+		}
+		klines = append(klines, *kline)
+	}
+
+	return klines
+}
+
+// fixtureBars replays the fixture trades through a 1m time bar builder
+func fixtureBars(t *testing.T) []models.Bar {
+	t.Helper()
+
+	var trades []binance.AggTradeResponse
+	readJSONFixture(t, "synthetic-aggTrades.json", &trades)
+
+	spec, err := ParseBarSpec("time:1m")
+	if err != nil {
+		t.Fatalf("failed to parse bar spec: %v", err)
+	}
+
+	builder := NewBarBuilder(barFixtureSymbol, spec, barFixtureStart)
+
+	var bars []models.Bar
+	for i := range trades {
+		closed, err := builder.Add(aggTradeEvent(barFixtureSymbol, &trades[i]))
+		if err != nil {
+			t.Fatalf("failed to add trade %d: %v", trades[i].AggTradeID, err)
+		}
+		bars = append(bars, closed...)
+	}
+
+	return append(bars, builder.CloseUntil(barFixtureEnd)...)
+}
+
+func TestBarBuilderMatchesKlines(t *testing.T) {
+	klines := fixtureKlines(t)
+	bars := fixtureBars(t)
+
+	if len(bars) != len(klines) {
+		t.Fatalf("got %d bars, want %d", len(bars), len(klines))
+	}
+
+	report := &BarCheckReport{Symbol: barFixtureSymbol}
+	compareTradeBars(report, bars, klines)
+
+	if report.MissingBars != 0 || report.MissingKlines != 0 {
+		t.Errorf("got %d missing bars and %d missing klines, want none", report.MissingBars, report.MissingKlines)
+	}
+	if report.Compared != len(klines) || report.Matched != report.Compared {
+		t.Errorf("matched %d of %d compared bars, want all %d", report.Matched, report.Compared, len(klines))
+	}
+	for _, m := range report.Mismatches {
+		t.Errorf("bar %d: %s is %v, kline has %v", m.OpenTime, m.Field, m.Derived, m.Native)
+	}
+}
+
+func TestBarBuilderTimeBarBoundaries(t *testing.T) {
+	klines := fixtureKlines(t)
+	bars := fixtureBars(t)
+
+	for i, bar := range bars {
+		if i >= len(klines) {
+			break
+		}
+		kline := klines[i]
+
+		if bar.OpenTime != kline.OpenTime || bar.CloseTime != kline.CloseTime {
+			t.Errorf("bar %d covers [%d, %d], kline covers [%d, %d]",
+				i, bar.OpenTime, bar.CloseTime, kline.OpenTime, kline.CloseTime)
+		}
+		if bar.Spec != "1m" {
+			t.Errorf("bar %d has spec %q, want 1m", i, bar.Spec)
+		}
+	}
+
+	// The minute without trades is an empty bar at the previous close, as on Binance
+	empty := bars[2]
+	if empty.TradesCount != 0 || empty.Volume != 0 {
+		t.Errorf("empty bar has %d trades and volume %v, want none", empty.TradesCount, empty.Volume)
+	}
+	if empty.OpenPrice != bars[1].ClosePrice || empty.ClosePrice != bars[1].ClosePrice {
+		t.Errorf("empty bar opens at %v and closes at %v, want the previous close %v",
+			empty.OpenPrice, empty.ClosePrice, bars[1].ClosePrice)
+	}
+}
+
+func TestBarBuilderDetectsMismatch(t *testing.T) {
+	klines := fixtureKlines(t)
+	bars := fixtureBars(t)
+
+	klines[0].TradesCount++
+	klines[1].TakerBuyVolume *= 2
+
+	report := &BarCheckReport{Symbol: barFixtureSymbol}
+	compareTradeBars(report, bars, klines)
+
+	if report.Matched != len(klines)-2 {
+		t.Errorf("matched %d bars, want %d", report.Matched, len(klines)-2)
+	}
+
+	fields := map[string]bool{}
+	for _, m := range report.Mismatches {
+		fields[m.Field] = true
+	}
+	if !fields["trades"] || !fields["taker_buy_volume"] {
+		t.Errorf("got mismatches %v, want trades and taker_buy_volume", report.Mismatches)
+	}
+}
@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/binance-live/internal/binance"
+	"github.com/binance-live/internal/models"
+)
+
+// aggTradesMaxWindow is the widest startTime/endTime window Binance accepts for aggTrades
+const aggTradesMaxWindow = time.Hour
+
+// aggTradesLimit is the maximum number of aggTrades returned per request
+const aggTradesLimit = 1000
+
+// BarCheckReport compares 1m bars rebuilt from aggregated trades with Binance's 1m klines
+type BarCheckReport struct {
+	Symbol        string
+	Trades        int // Aggregated trades replayed
+	Compared      int // Candles present on both sides
+	Matched       int // Compared candles with no mismatching field
+	MissingBars   int // Klines without a rebuilt bar
+	MissingKlines int // Rebuilt bars Binance did not return a kline for
+	Mismatches    []DerivedKlineMismatch
+}
+
+// CheckTradeBars replays the aggregated trades of symbol in [start, end) through a
+// 1m time bar builder and compares the bars with the 1m klines of the Binance REST API.
+// It verifies the bar builder end to end; the minute still in progress is never compared.
+func (s *DataSyncService) CheckTradeBars(ctx context.Context, symbol string, start, end time.Time) (*BarCheckReport, error) {
+	interval := binance.Interval1m
+	if current := interval.Truncate(time.Now()); end.After(current) {
+		end = current
+	}
+	start = interval.Truncate(start)
+	if !start.Before(end) {
+		return nil, fmt.Errorf("invalid range: from %s is not before to %s", start, end)
+	}
+
+	spec, err := ParseBarSpec("time:1m")
+	if err != nil {
+		return nil, err
+	}
+
+	report := &BarCheckReport{Symbol: symbol}
+	builder := NewBarBuilder(symbol, spec, start.UnixMilli())
+
+	var bars []models.Bar
+	err = s.fetchAggTrades(ctx, symbol, start, end, func(trade *binance.WSAggTradeEvent) error {
+		closed, err := builder.Add(trade)
+		if err != nil {
+			return err
+		}
+
+		report.Trades++
+		bars = append(bars, closed...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	bars = append(bars, builder.CloseUntil(end.UnixMilli())...)
+
+	klines, err := s.fetchKlines(ctx, symbol, interval, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	compareTradeBars(report, bars, klines)
+	return report, nil
+}
+
+// compareTradeBars compares rebuilt 1m bars with 1m klines field by field and adds the
+// result to report
+func compareTradeBars(report *BarCheckReport, bars []models.Bar, klines []models.Kline) {
+	barsByOpenTime := make(map[int64]models.Bar, len(bars))
+	for _, bar := range bars {
+		barsByOpenTime[bar.OpenTime] = bar
+	}
+
+	for _, kline := range klines {
+		bar, ok := barsByOpenTime[kline.OpenTime]
+		if !ok {
+			report.MissingBars++
+			continue
+		}
+		delete(barsByOpenTime, kline.OpenTime)
+
+		rebuilt := models.Kline{
+			OpenPrice:           bar.OpenPrice,
+			HighPrice:           bar.HighPrice,
+			LowPrice:            bar.LowPrice,
+			ClosePrice:          bar.ClosePrice,
+			Volume:              bar.Volume,
+			QuoteVolume:         bar.QuoteVolume,
+			TradesCount:         bar.TradesCount,
+			TakerBuyVolume:      bar.TakerBuyVolume,
+			TakerBuyQuoteVolume: bar.TakerBuyQuoteVolume,
+		}
+
+		report.Compared++
+		mismatches := compareDerivedKline(&rebuilt, &kline)
+		if len(mismatches) == 0 {
+			report.Matched++
+			continue
+		}
+		report.Mismatches = append(report.Mismatches, mismatches...)
+	}
+	report.MissingKlines = len(barsByOpenTime)
+}
+
+// fetchAggTrades reads the aggregated trades of symbol in [start, end) from the Binance
+// REST API in trade order without storing them
+func (s *DataSyncService) fetchAggTrades(
+	ctx context.Context,
+	symbol string,
+	start, end time.Time,
+	onTrade func(trade *binance.WSAggTradeEvent) error,
+) error {
+	cursor := start
+	lastID := int64(-1)
+
+	for cursor.Before(end) {
+		windowEnd := cursor.Add(aggTradesMaxWindow)
+		if windowEnd.After(end) {
+			windowEnd = end
+		}
+		requestEnd := windowEnd.Add(-time.Millisecond)
+
+		page, err := s.binanceClient.REST.GetAggTrades(ctx, symbol, &cursor, &requestEnd, aggTradesLimit)
+		if err != nil {
+			return fmt.Errorf("failed to fetch aggTrades: %w", err)
+		}
+
+		for i := range page {
+			t := &page[i]
+			if t.AggTradeID <= lastID {
+				continue
+			}
+			lastID = t.AggTradeID
+
+			if err := onTrade(aggTradeEvent(symbol, t)); err != nil {
+				return err
+			}
+		}
+
+		if len(page) < aggTradesLimit {
+			cursor = windowEnd
+			continue
+		}
+
+		// A full page may stop inside a millisecond, so resume at the last trade's
+		// time and skip the trades already replayed
+		next := time.UnixMilli(page[len(page)-1].Timestamp)
+		if !next.After(cursor) {
+			return fmt.Errorf("more than %d aggTrades at %d", aggTradesLimit, cursor.UnixMilli())
+		}
+		cursor = next
+	}
+
+	return nil
+}
+
+// aggTradeEvent converts an aggregated trade of the REST API to the stream event the bar
+// builder takes
+func aggTradeEvent(symbol string, t *binance.AggTradeResponse) *binance.WSAggTradeEvent {
+	return &binance.WSAggTradeEvent{
+		Symbol:       symbol,
+		AggTradeID:   t.AggTradeID,
+		Price:        t.Price,
+		Quantity:     t.Quantity,
+		FirstTradeID: t.FirstTradeID,
+		LastTradeID:  t.LastTradeID,
+		TradeTime:    t.Timestamp,
+		IsBuyerMaker: t.IsBuyerMaker,
+	}
+}
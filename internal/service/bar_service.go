@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/binance-live/internal/binance"
+	"github.com/binance-live/internal/models"
+	"github.com/binance-live/internal/publisher"
+	"github.com/binance-live/internal/repository"
+	"go.uber.org/zap"
+)
+
+// barCloseGrace keeps time bars open past their end for trades that arrive late
+const barCloseGrace = 2 * time.Second
+
+// barQueueSize bounds the groups of closed bars waiting to be stored and published;
+// when it is full, the stream waits for storage to catch up
+const barQueueSize = 1024
+
+// BarService builds bars from the aggTrade stream, publishes every closed bar to Redis
+// and optionally stores it
+type BarService struct {
+	specs     []BarSpec
+	publisher publisher.Publisher
+	barRepo   *repository.BarRepository // nil: bars are only published
+	logger    *zap.Logger
+	since     int64
+
+	mu       sync.Mutex
+	builders map[string][]*BarBuilder // By symbol
+	closed   chan []models.Bar        // Closed bars, in order, for Run to store and publish
+}
+
+// NewBarService creates a new bar service. Pass a nil barRepo to skip storage.
+func NewBarService(
+	specs []BarSpec,
+	pub publisher.Publisher,
+	barRepo *repository.BarRepository,
+	logger *zap.Logger,
+) *BarService {
+	return &BarService{
+		specs:     specs,
+		publisher: pub,
+		barRepo:   barRepo,
+		logger:    logger,
+		since:     time.Now().UnixMilli(),
+		builders:  make(map[string][]*BarBuilder),
+		closed:    make(chan []models.Bar, barQueueSize),
+	}
+}
+
+// HandleAggTrade feeds an aggregated trade to the builders of its symbol
+func (s *BarService) HandleAggTrade(ctx context.Context, event *binance.WSAggTradeEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	builders, ok := s.builders[event.Symbol]
+	if !ok {
+		builders = make([]*BarBuilder, len(s.specs))
+		for i, spec := range s.specs {
+			builders[i] = NewBarBuilder(event.Symbol, spec, s.since)
+		}
+		s.builders[event.Symbol] = builders
+	}
+
+	for _, builder := range builders {
+		bars, err := builder.Add(event)
+		if err != nil {
+			return err
+		}
+		s.queue(ctx, bars)
+	}
+
+	return nil
+}
+
+// Run stores and publishes closed bars, and closes time bars on the wall clock so quiet
+// symbols still get their bars, until ctx is cancelled
+func (s *BarService) Run(ctx context.Context) {
+	go s.emitClosed(ctx)
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			until := now.Add(-barCloseGrace).UnixMilli()
+
+			s.mu.Lock()
+			for _, builders := range s.builders {
+				for _, builder := range builders {
+					s.queue(ctx, builder.CloseUntil(until))
+				}
+			}
+			s.mu.Unlock()
+		}
+	}
+}
+
+// queue hands closed bars to emitClosed. It is called with mu held so the bars of a
+// channel keep their order, and only waits when the queue is full.
+func (s *BarService) queue(ctx context.Context, bars []models.Bar) {
+	if len(bars) == 0 {
+		return
+	}
+
+	select {
+	case s.closed <- bars:
+	case <-ctx.Done():
+	}
+}
+
+// emitClosed stores and publishes the queued bars until ctx is cancelled, so a slow
+// database or Redis never holds up the trades of other symbols
+func (s *BarService) emitClosed(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case bars := <-s.closed:
+			s.emit(ctx, bars)
+		}
+	}
+}
+
+// emit stores and publishes closed bars
+func (s *BarService) emit(ctx context.Context, bars []models.Bar) {
+	for i := range bars {
+		bar := &bars[i]
+
+		if s.barRepo != nil {
+			if err := s.barRepo.Insert(ctx, bar); err != nil {
+				s.logger.Error("Failed to insert bar",
+					zap.String("symbol", bar.Symbol),
+					zap.String("spec", bar.Spec),
+					zap.Error(err),
+				)
+			}
+		}
+
+		if err := s.publisher.PublishBar(ctx, bar); err != nil {
+			s.logger.Error("Failed to publish bar", zap.Error(err))
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/binance-live/internal/binance"
+	"github.com/binance-live/internal/models"
+	"github.com/binance-live/internal/publisher"
+	"go.uber.org/zap"
+)
+
+// blockingPublisher records published bars, holding each until release is closed
+type blockingPublisher struct {
+	publisher.Publisher
+	release chan struct{}
+	bars    chan models.Bar
+}
+
+func (p *blockingPublisher) PublishBar(ctx context.Context, bar *models.Bar) error {
+	select {
+	case <-p.release:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	p.bars <- *bar
+	return nil
+}
+
+func TestBarServiceSlowPublisherDoesNotBlockTrades(t *testing.T) {
+	spec, err := ParseBarSpec("tick:1")
+	if err != nil {
+		t.Fatalf("failed to parse bar spec: %v", err)
+	}
+
+	pub := &blockingPublisher{release: make(chan struct{}), bars: make(chan models.Bar, 16)}
+	s := NewBarService([]BarSpec{spec}, pub, nil, zap.NewNop())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.Run(ctx)
+
+	// Every trade closes a tick bar; none can be published until release
+	done := make(chan error)
+	go func() {
+		for i, symbol := range []string{"BTCUSDT", "ETHUSDT", "BTCUSDT"} {
+			err := s.HandleAggTrade(ctx, &binance.WSAggTradeEvent{
+				Symbol:       symbol,
+				AggTradeID:   int64(i),
+				Price:        "100",
+				Quantity:     "1",
+				FirstTradeID: int64(i),
+				LastTradeID:  int64(i),
+				TradeTime:    int64(i),
+			})
+			if err != nil {
+				done <- err
+				return
+			}
+		}
+		done <- nil
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("failed to handle trade: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("trades blocked on the publisher")
+	}
+
+	close(pub.release)
+
+	// Bars are published in the order they closed
+	for i, want := range []string{"BTCUSDT", "ETHUSDT", "BTCUSDT"} {
+		select {
+		case bar := <-pub.bars:
+			if bar.Symbol != want || bar.FirstTradeID != int64(i) {
+				t.Errorf("bar %d is %s trade %d, want %s trade %d", i, bar.Symbol, bar.FirstTradeID, want, i)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("bar %d was not published", i)
+		}
+	}
+}
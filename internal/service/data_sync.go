@@ -2,9 +2,9 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
-	"sync"
 	"time"
 
 	"github.com/binance-live/internal/binance"
@@ -19,8 +19,12 @@ type DataSyncService struct {
 	binanceClient  *binance.Client
 	symbolRepo     *repository.SymbolRepository
 	klineRepo      *repository.KlineRepository
+	klineGapRepo   *repository.KlineGapRepository
+	backfillRepo   *repository.BackfillJobRepository
 	tickerRepo     *repository.TickerRepository
+	tradeRepo      *repository.TradeRepository
 	syncStatusRepo *repository.SyncStatusRepository
+	syncJobRepo    *repository.SyncJobRepository
 	config         *config.SyncConfig
 	binanceConfig  *config.BinanceConfig
 	logger         *zap.Logger
@@ -31,8 +35,12 @@ func NewDataSyncService(
 	binanceClient *binance.Client,
 	symbolRepo *repository.SymbolRepository,
 	klineRepo *repository.KlineRepository,
+	klineGapRepo *repository.KlineGapRepository,
+	backfillRepo *repository.BackfillJobRepository,
 	tickerRepo *repository.TickerRepository,
+	tradeRepo *repository.TradeRepository,
 	syncStatusRepo *repository.SyncStatusRepository,
+	syncJobRepo *repository.SyncJobRepository,
 	cfg *config.SyncConfig,
 	binanceCfg *config.BinanceConfig,
 	logger *zap.Logger,
@@ -41,8 +49,12 @@ func NewDataSyncService(
 		binanceClient:  binanceClient,
 		symbolRepo:     symbolRepo,
 		klineRepo:      klineRepo,
+		klineGapRepo:   klineGapRepo,
+		backfillRepo:   backfillRepo,
 		tickerRepo:     tickerRepo,
+		tradeRepo:      tradeRepo,
 		syncStatusRepo: syncStatusRepo,
+		syncJobRepo:    syncJobRepo,
 		config:         cfg,
 		binanceConfig:  binanceCfg,
 		logger:         logger,
@@ -71,74 +83,126 @@ func (s *DataSyncService) SyncMissingData(ctx context.Context) error {
 
 	s.logger.Info("Found active symbols", zap.Int("count", len(symbols)))
 
-	// Create worker pool
-	var wg sync.WaitGroup
-	semaphore := make(chan struct{}, s.config.Workers)
-	errChan := make(chan error, len(symbols)*len(s.binanceConfig.KlineIntervals))
+	intervals, err := binance.ParseIntervals(s.binanceConfig.NativeKlineIntervals())
+	if err != nil {
+		return fmt.Errorf("invalid kline intervals: %w", err)
+	}
 
-	// Sync klines for each symbol and interval with sequential processing
-	// Process symbols sequentially to minimize database connection pressure
+	// Queue one job per symbol and interval; streams that already have an open job
+	// (for example claimed by another process) are not queued twice
+	enqueued := 0
 	for _, symbol := range symbols {
-		for _, interval := range s.binanceConfig.KlineIntervals {
-			wg.Add(1)
-
-			go func(sym models.Symbol, intv string) {
-				defer wg.Done()
-
-				// Acquire semaphore
-				semaphore <- struct{}{}
-				defer func() { <-semaphore }()
-
-				// Add small delay before starting each operation
-				select {
-				case <-ctx.Done():
-					return
-				case <-time.After(50 * time.Millisecond):
-				}
-
-				if err := s.syncKlinesForSymbol(ctx, sym.Symbol, intv); err != nil {
-					s.logger.Error("Failed to sync klines",
-						zap.String("symbol", sym.Symbol),
-						zap.String("interval", intv),
-						zap.Error(err),
-					)
-					errChan <- err
-				}
-			}(symbol, interval)
+		for _, interval := range intervals {
+			created, err := s.syncJobRepo.Enqueue(ctx, symbol.Symbol, "kline", interval.String(), s.config.JobMaxAttempts)
+			if err != nil {
+				return err
+			}
+			if created {
+				enqueued++
+			}
 		}
 	}
 
-	// Wait for all workers to complete
-	wg.Wait()
-	close(errChan)
+	s.logger.Info("Enqueued sync jobs", zap.Int("count", enqueued))
 
-	// Check for errors
-	var syncErrors []error
-	for err := range errChan {
-		syncErrors = append(syncErrors, err)
+	result, err := s.RunSyncJobs(ctx, s.config.Workers)
+	if err != nil {
+		return err
 	}
 
-	if len(syncErrors) > 0 {
+	if result.Failed > 0 {
 		s.logger.Warn("Data synchronization completed with errors",
-			zap.Int("error_count", len(syncErrors)),
+			zap.Int("completed", result.Completed),
+			zap.Int("failed", result.Failed),
 		)
-	} else {
-		s.logger.Info("Data synchronization completed successfully")
+		return fmt.Errorf("%d sync jobs failed", result.Failed)
 	}
 
+	s.logger.Info("Data synchronization completed successfully",
+		zap.Int("completed", result.Completed),
+		zap.Int("retried", result.Retried),
+	)
+
 	return nil
 }
 
+// SyncOptions controls a single-symbol sync. Zero values fall back to the service config.
+type SyncOptions struct {
+	BatchSize     int  // Klines requested per REST call
+	MaxSyncHours  int  // How far back to start when the symbol has no sync status
+	ForceInactive bool // Also sync symbols that are not marked active
+
+	// Progress, if set, is called after every stored page with the klines fetched so far
+	// and the open time of the newest one
+	Progress func(klinesFetched, lastDataTime int64)
+}
+
+// defaultSyncOptions returns the sync options configured for the service
+func (s *DataSyncService) defaultSyncOptions() SyncOptions {
+	return SyncOptions{
+		BatchSize:    s.config.BatchSize,
+		MaxSyncHours: s.config.MaxSyncHours,
+	}
+}
+
+// SyncSymbol synchronizes klines for a single symbol and the given intervals.
+// Intervals are synced one after another; a failing interval does not stop the others,
+// and all failures are returned together. Inactive symbols are rejected unless
+// opts.ForceInactive is set.
+func (s *DataSyncService) SyncSymbol(
+	ctx context.Context,
+	symbol string,
+	intervals []binance.Interval,
+	opts SyncOptions,
+) error {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = s.config.BatchSize
+	}
+	if opts.MaxSyncHours <= 0 {
+		opts.MaxSyncHours = s.config.MaxSyncHours
+	}
+
+	symbolData, err := s.symbolRepo.GetSymbolByName(ctx, symbol)
+	if err != nil {
+		return err
+	}
+
+	if !symbolData.IsActive && !opts.ForceInactive {
+		return fmt.Errorf("symbol %s is not active (use force inactive to sync it anyway)", symbol)
+	}
+
+	var syncErrors []error
+	for _, interval := range intervals {
+		if err := s.syncKlinesForSymbol(ctx, symbol, interval, opts); err != nil {
+			s.logger.Error("Failed to sync klines",
+				zap.String("symbol", symbol),
+				zap.String("interval", interval.String()),
+				zap.Error(err),
+			)
+			syncErrors = append(syncErrors, fmt.Errorf("%s %s: %w", symbol, interval, err))
+		}
+
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	return errors.Join(syncErrors...)
+}
+
 // syncKlinesForSymbol synchronizes kline data for a specific symbol and interval
-func (s *DataSyncService) syncKlinesForSymbol(ctx context.Context, symbol, interval string) error {
+func (s *DataSyncService) syncKlinesForSymbol(ctx context.Context, symbol string, interval binance.Interval, opts SyncOptions) error {
+
+	intervalName := interval.String()
 
 	s.logger.Info("Syncing klines",
 		zap.String("symbol", symbol),
-		zap.String("interval", interval),
+		zap.String("interval", intervalName),
 	)
 
 	// Get sync status
-	syncStatus, err := s.syncStatusRepo.GetSyncStatus(ctx, symbol, "kline", &interval)
+	key := models.KlineStreamKey(symbol, intervalName)
+	syncStatus, err := s.syncStatusRepo.GetSyncStatus(ctx, key)
 	if err != nil {
 
 		return fmt.Errorf("failed to get sync status: %w", err)
@@ -152,36 +216,121 @@ func (s *DataSyncService) syncKlinesForSymbol(ctx context.Context, symbol, inter
 	} else {
 
 		// Start from max sync hours ago
-		startTime = time.Now().Add(-time.Duration(s.config.MaxSyncHours) * time.Hour)
+		startTime = time.Now().Add(-time.Duration(opts.MaxSyncHours) * time.Hour)
 	}
 
 	endTime := time.Now()
 
-	// Fetch and store klines in batches
-	currentTime := startTime
-	totalKlines := 0
-
-	for currentTime.Before(endTime) {
+	var fetched int64
+	result, err := s.backfillKlines(ctx, symbol, interval, startTime, endTime, opts.BatchSize, func(page []models.Kline) error {
 
+		// Update sync status with additional delay
+		lastKline := page[len(page)-1]
 		select {
 		case <-ctx.Done():
-
 			return ctx.Err()
-		default:
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		if err := s.syncStatusRepo.UpsertSyncStatus(ctx, &models.SyncStatus{
+			StreamKey:    key,
+			LastSyncTime: time.Now().UnixMilli(),
+			LastDataTime: lastKline.OpenTime,
+			Status:       "active",
+			ErrorMessage: nil,
+			UpdatedAt:    time.Now().UnixMilli(),
+		}); err != nil {
+
+			s.logger.Warn("Failed to update sync status", zap.Error(err))
 		}
 
-		// Calculate batch end time
-		batchEndTime := currentTime.Add(time.Duration(s.config.BatchSize) * getIntervalDuration(interval))
-		if batchEndTime.After(endTime) {
+		fetched += int64(len(page))
+		if opts.Progress != nil {
 
-			batchEndTime = endTime
+			opts.Progress(fetched, lastKline.OpenTime)
+		}
+
+		return nil
+	})
+	if err != nil {
+
+		s.recordSyncError(ctx, symbol, intervalName, err)
+		return err
+	}
+
+	if err := s.syncStatusRepo.ClearError(ctx, key); err != nil {
+
+		s.logger.Warn("Failed to clear sync status error", zap.Error(err))
+	}
+
+	s.logger.Info("Klines synced successfully",
+		zap.String("symbol", symbol),
+		zap.String("interval", intervalName),
+		zap.Int64("total_klines", result.Received),
+		zap.Int64("missing_klines", result.Missing),
+	)
+
+	return nil
+}
+
+// recordSyncError marks the kline stream as failed in sync_status with the failure reason
+func (s *DataSyncService) recordSyncError(ctx context.Context, symbol, intervalName string, syncErr error) {
+
+	// Record the failure even when it was caused by cancellation
+	ctx = context.WithoutCancel(ctx)
+	if err := s.syncStatusRepo.SetError(ctx, models.KlineStreamKey(symbol, intervalName), syncErr.Error()); err != nil {
+
+		s.logger.Warn("Failed to record sync status error", zap.Error(err))
+	}
+}
+
+// klineBackfillResult summarizes a cursor-based kline backfill
+type klineBackfillResult struct {
+	Received     int64 // Klines stored
+	Expected     int64 // Candle open times covered by the returned pages
+	Missing      int64 // Expected minus received
+	LastOpenTime int64 // Open time of the newest stored kline (0 if none)
+}
+
+// backfillKlines fetches and stores klines in [start, end) page by page.
+// The cursor advances from the last returned CloseTime+1 rather than by a fixed window, so
+// nothing is skipped when Binance returns fewer rows than requested. The loop stops once the
+// exchange returns an empty page. limit is capped at binance.MaxKlinesLimit.
+// onPage, if set, is called after each page is stored.
+func (s *DataSyncService) backfillKlines(
+	ctx context.Context,
+	symbol string,
+	interval binance.Interval,
+	start, end time.Time,
+	limit int,
+	onPage func(page []models.Kline) error,
+) (*klineBackfillResult, error) {
+
+	if limit <= 0 || limit > binance.MaxKlinesLimit {
+
+		limit = binance.MaxKlinesLimit
+	}
+
+	intervalName := interval.String()
+	requestEnd := end.Add(-time.Millisecond) // endTime is inclusive on Binance
+	cursor := interval.Truncate(start)
+	result := &klineBackfillResult{}
+
+	for cursor.Before(end) {
+
+		select {
+		case <-ctx.Done():
+
+			return result, ctx.Err()
+		default:
 		}
 
 		// Fetch klines from Binance
-		klines, err := s.binanceClient.REST.GetKlines(ctx, symbol, interval, &currentTime, &batchEndTime, s.config.BatchSize)
+		pageStart := cursor
+		klines, err := s.binanceClient.REST.GetKlines(ctx, symbol, interval, &pageStart, &requestEnd, limit)
 		if err != nil {
 
-			return fmt.Errorf("failed to fetch klines: %w", err)
+			return result, fmt.Errorf("failed to fetch klines: %w", err)
 		}
 
 		if len(klines) == 0 {
@@ -189,7 +338,7 @@ func (s *DataSyncService) syncKlinesForSymbol(ctx context.Context, symbol, inter
 			break
 		}
 
-		// Convert and store klines
+		// Convert klines
 		modelKlines := make([]models.Kline, 0, len(klines))
 		for _, k := range klines {
 
@@ -200,7 +349,7 @@ func (s *DataSyncService) syncKlinesForSymbol(ctx context.Context, symbol, inter
 				continue
 			}
 
-			modelKline, err := s.convertToModelKline(symbol, interval, klineData)
+			modelKline, err := s.convertToModelKline(symbol, intervalName, klineData)
 			if err != nil {
 
 				s.logger.Warn("Failed to convert kline", zap.Error(err))
@@ -210,50 +359,58 @@ func (s *DataSyncService) syncKlinesForSymbol(ctx context.Context, symbol, inter
 			modelKlines = append(modelKlines, *modelKline)
 		}
 
+		if len(modelKlines) == 0 {
+
+			return result, fmt.Errorf("no valid klines in page starting at %d", pageStart.UnixMilli())
+		}
+
 		// Batch insert klines with retry logic and rate limiting
-		if len(modelKlines) > 0 {
+		if err := s.klineRepo.BatchInsert(ctx, modelKlines); err != nil {
 
-			if err := s.klineRepo.BatchInsert(ctx, modelKlines); err != nil {
+			return result, fmt.Errorf("failed to insert klines: %w", err)
+		}
 
-				return fmt.Errorf("failed to insert klines: %w", err)
-			}
+		lastKline := modelKlines[len(modelKlines)-1]
+
+		// Verify the page is complete: every open time between the cursor and the
+		// last returned candle should be present
+		expected := interval.Count(pageStart, time.UnixMilli(lastKline.OpenTime+1))
+		received := int64(len(modelKlines))
+		result.Expected += expected
+		result.Received += received
+		result.LastOpenTime = lastKline.OpenTime
+
+		if received < expected {
+
+			result.Missing += expected - received
+			s.logger.Warn("Kline page is incomplete",
+				zap.String("symbol", symbol),
+				zap.String("interval", intervalName),
+				zap.Int64("from", pageStart.UnixMilli()),
+				zap.Int64("to", lastKline.OpenTime),
+				zap.Int64("expected", expected),
+				zap.Int64("received", received),
+			)
+		}
 
-			totalKlines += len(modelKlines)
+		if onPage != nil {
 
-			// Update sync status with additional delay
-			lastKline := modelKlines[len(modelKlines)-1]
-			select {
-			case <-ctx.Done():
-				return ctx.Err()
-			case <-time.After(50 * time.Millisecond):
-			}
+			if err := onPage(modelKlines); err != nil {
 
-			if err := s.syncStatusRepo.UpsertSyncStatus(ctx, &models.SyncStatus{
-				Symbol:       symbol,
-				DataType:     "kline",
-				Interval:     &interval,
-				LastSyncTime: time.Now().UnixMilli(),
-				LastDataTime: lastKline.OpenTime,
-				Status:       "active",
-				ErrorMessage: nil,
-				UpdatedAt:    time.Now().UnixMilli(),
-			}); err != nil {
-				
-				s.logger.Warn("Failed to update sync status", zap.Error(err))
+				return result, err
 			}
 		}
 
-		// Move to next batch
-		currentTime = batchEndTime
-	}
+		// Move the cursor past the last returned candle
+		next := time.UnixMilli(lastKline.CloseTime + 1)
+		if !next.After(cursor) {
 
-	s.logger.Info("Klines synced successfully",
-		zap.String("symbol", symbol),
-		zap.String("interval", interval),
-		zap.Int("total_klines", totalKlines),
-	)
+			return result, fmt.Errorf("kline cursor did not advance at %d", cursor.UnixMilli())
+		}
+		cursor = next
+	}
 
-	return nil
+	return result, nil
 }
 
 // convertToModelKline converts Binance kline data to model
@@ -284,41 +441,3 @@ func (s *DataSyncService) convertToModelKline(symbol, interval string, data *bin
 		CreatedAt:           time.Now().UnixMilli(),
 	}, nil
 }
-
-// getIntervalDuration converts interval string to duration
-func getIntervalDuration(interval string) time.Duration {
-	switch interval {
-	case "1m":
-		return time.Minute
-	case "3m":
-		return 3 * time.Minute
-	case "5m":
-		return 5 * time.Minute
-	case "15m":
-		return 15 * time.Minute
-	case "30m":
-		return 30 * time.Minute
-	case "1h":
-		return time.Hour
-	case "2h":
-		return 2 * time.Hour
-	case "4h":
-		return 4 * time.Hour
-	case "6h":
-		return 6 * time.Hour
-	case "8h":
-		return 8 * time.Hour
-	case "12h":
-		return 12 * time.Hour
-	case "1d":
-		return 24 * time.Hour
-	case "3d":
-		return 3 * 24 * time.Hour
-	case "1w":
-		return 7 * 24 * time.Hour
-	case "1M":
-		return 30 * 24 * time.Hour
-	default:
-		return time.Hour
-	}
-}
@@ -0,0 +1,311 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/binance-live/internal/binance"
+	"github.com/binance-live/internal/config"
+	"github.com/binance-live/internal/models"
+	"github.com/binance-live/internal/repository"
+	"go.uber.org/zap"
+)
+
+// derivedPriceTolerance absorbs rounding below the eight decimals of Binance prices
+const derivedPriceTolerance = 1e-8
+
+// derivedVolumeTolerance is the relative tolerance for summed volumes
+const derivedVolumeTolerance = 1e-9
+
+// DerivedKlineMismatch is a field that differs between a derived and a native candle
+type DerivedKlineMismatch struct {
+	OpenTime int64
+	Field    string
+	Derived  float64
+	Native   float64
+}
+
+// DerivedKlineReport compares candles derived by a continuous aggregate with Binance's own
+type DerivedKlineReport struct {
+	Symbol         string
+	Interval       string
+	BaseInterval   string
+	Compared       int // Candles present on both sides
+	Matched        int // Compared candles with no mismatching field
+	MissingDerived int // Native candles without a derived counterpart
+	MissingNative  int // Derived candles Binance did not return
+	Mismatches     []DerivedKlineMismatch
+}
+
+// DerivedKlineViews builds the continuous aggregate definitions for the configured
+// derived intervals. Every derived interval must be a whole multiple of the base interval;
+// calendar months cannot be derived.
+func (s *DataSyncService) DerivedKlineViews() ([]repository.DerivedKlineView, error) {
+	return BuildDerivedKlineViews(s.binanceConfig)
+}
+
+// BuildDerivedKlineViews builds the continuous aggregate definitions of the derived
+// intervals in cfg, for processes that read klines without a DataSyncService
+func BuildDerivedKlineViews(cfg *config.BinanceConfig) ([]repository.DerivedKlineView, error) {
+	if len(cfg.DerivedIntervals) == 0 {
+		return nil, nil
+	}
+
+	base, err := binance.ParseInterval(cfg.DerivedBaseInterval)
+	if err != nil {
+		return nil, fmt.Errorf("invalid derived base interval: %w", err)
+	}
+
+	intervals, err := binance.ParseIntervals(cfg.DerivedIntervals)
+	if err != nil {
+		return nil, fmt.Errorf("invalid derived intervals: %w", err)
+	}
+
+	views := make([]repository.DerivedKlineView, 0, len(intervals))
+	for _, interval := range intervals {
+		view, err := NewDerivedKlineView(base, interval)
+		if err != nil {
+			return nil, err
+		}
+		views = append(views, view)
+	}
+
+	return views, nil
+}
+
+// NewDerivedKlineView describes interval as buckets of base candles. It defines the
+// continuous aggregates and is what KlineRepository.ResampleKlines takes to resample
+// stored candles on read. interval must be a whole multiple of base; calendar months
+// can be neither.
+func NewDerivedKlineView(base, interval binance.Interval) (repository.DerivedKlineView, error) {
+	if base == binance.Interval1M {
+		return repository.DerivedKlineView{}, fmt.Errorf("derived base interval cannot be %s", base)
+	}
+	if interval == binance.Interval1M {
+		return repository.DerivedKlineView{}, fmt.Errorf("interval %s follows calendar months and cannot be derived", interval)
+	}
+
+	width := interval.Duration().Milliseconds()
+	baseWidth := base.Duration().Milliseconds()
+	if width <= baseWidth || width%baseWidth != 0 {
+		return repository.DerivedKlineView{}, fmt.Errorf("interval %s is not a multiple of base interval %s", interval, base)
+	}
+
+	return repository.DerivedKlineView{
+		Interval:     interval.String(),
+		BaseInterval: base.String(),
+		Width:        width,
+		BaseWidth:    baseWidth,
+		Offset:       interval.Offset().Milliseconds(), // Weekly candles open on Monday
+	}, nil
+}
+
+// EnsureDerivedIntervals creates the continuous aggregates for the configured derived
+// intervals and serves those intervals from them. It returns the number of aggregates.
+func (s *DataSyncService) EnsureDerivedIntervals(ctx context.Context) (int, error) {
+	views, err := s.DerivedKlineViews()
+	if err != nil {
+		return 0, err
+	}
+
+	for _, view := range views {
+		if err := s.klineRepo.EnsureDerivedView(ctx, view); err != nil {
+			return 0, err
+		}
+
+		s.logger.Info("Derived kline interval ready",
+			zap.String("view", view.Name()),
+			zap.String("interval", view.Interval),
+			zap.String("base_interval", view.BaseInterval),
+		)
+	}
+
+	s.klineRepo.UseDerivedViews(views)
+	return len(views), nil
+}
+
+// RefreshDerivedIntervals materializes every derived interval over [start, end),
+// typically after backfilling base candles older than the refresh policy window
+func (s *DataSyncService) RefreshDerivedIntervals(ctx context.Context, start, end time.Time) error {
+	views, err := s.DerivedKlineViews()
+	if err != nil {
+		return err
+	}
+
+	for _, view := range views {
+		if err := s.klineRepo.RefreshDerivedView(ctx, view, start.UnixMilli(), end.UnixMilli()); err != nil {
+			return err
+		}
+
+		s.logger.Info("Refreshed derived kline interval",
+			zap.String("view", view.Name()),
+			zap.Time("from", start),
+			zap.Time("to", end),
+		)
+	}
+
+	return nil
+}
+
+// CheckDerivedKlines compares the derived candles of symbol and interval in [start, end)
+// with the native candles returned by the Binance REST API. The candle still in progress
+// is never compared.
+func (s *DataSyncService) CheckDerivedKlines(
+	ctx context.Context,
+	symbol string,
+	interval binance.Interval,
+	start, end time.Time,
+) (*DerivedKlineReport, error) {
+	views, err := s.DerivedKlineViews()
+	if err != nil {
+		return nil, err
+	}
+
+	var view *repository.DerivedKlineView
+	for i := range views {
+		if views[i].Interval == interval.String() {
+			view = &views[i]
+		}
+	}
+	if view == nil {
+		return nil, fmt.Errorf("interval %s is not configured as a derived interval", interval)
+	}
+	s.klineRepo.UseDerivedViews(views)
+
+	if current := interval.Truncate(time.Now()); end.After(current) {
+		end = current
+	}
+	start = interval.Truncate(start)
+
+	derived, err := s.klineRepo.GetKlinesByTimeRange(ctx, symbol, interval.String(), start.UnixMilli(), end.UnixMilli())
+	if err != nil {
+		return nil, err
+	}
+
+	native, err := s.fetchKlines(ctx, symbol, interval, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &DerivedKlineReport{
+		Symbol:       symbol,
+		Interval:     view.Interval,
+		BaseInterval: view.BaseInterval,
+	}
+
+	derivedByOpenTime := make(map[int64]models.Kline, len(derived))
+	for _, kline := range derived {
+		derivedByOpenTime[kline.OpenTime] = kline
+	}
+
+	for _, n := range native {
+		d, ok := derivedByOpenTime[n.OpenTime]
+		if !ok {
+			report.MissingDerived++
+			continue
+		}
+		delete(derivedByOpenTime, n.OpenTime)
+
+		report.Compared++
+		mismatches := compareDerivedKline(&d, &n)
+		if len(mismatches) == 0 {
+			report.Matched++
+			continue
+		}
+		report.Mismatches = append(report.Mismatches, mismatches...)
+	}
+	report.MissingNative = len(derivedByOpenTime)
+
+	return report, nil
+}
+
+// fetchKlines reads klines in [start, end) from the Binance REST API without storing them
+func (s *DataSyncService) fetchKlines(
+	ctx context.Context,
+	symbol string,
+	interval binance.Interval,
+	start, end time.Time,
+) ([]models.Kline, error) {
+	intervalName := interval.String()
+	requestEnd := end.Add(-time.Millisecond)
+	cursor := start
+
+	var klines []models.Kline
+	for cursor.Before(end) {
+		page, err := s.binanceClient.REST.GetKlines(ctx, symbol, interval, &cursor, &requestEnd, binance.MaxKlinesLimit)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch klines: %w", err)
+		}
+
+		if len(page) == 0 {
+			break
+		}
+
+		var lastCloseTime int64
+		for _, k := range page {
+			klineData, err := binance.ParseKlineResponse(k)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse kline: %w", err)
+			}
+
+			kline, err := s.convertToModelKline(symbol, intervalName, klineData)
+			if err != nil {
+				return nil, err
+			}
+
+			klines = append(klines, *kline)
+			lastCloseTime = kline.CloseTime
+		}
+
+		next := time.UnixMilli(lastCloseTime + 1)
+		if !next.After(cursor) {
+			return nil, fmt.Errorf("kline cursor did not advance at %d", cursor.UnixMilli())
+		}
+		cursor = next
+	}
+
+	return klines, nil
+}
+
+// compareDerivedKline returns the fields where a derived candle differs from the native one
+func compareDerivedKline(derived, native *models.Kline) []DerivedKlineMismatch {
+	var mismatches []DerivedKlineMismatch
+
+	check := func(field string, d, n float64, equal func(a, b float64) bool) {
+		if !equal(d, n) {
+			mismatches = append(mismatches, DerivedKlineMismatch{
+				OpenTime: native.OpenTime,
+				Field:    field,
+				Derived:  d,
+				Native:   n,
+			})
+		}
+	}
+
+	check("open", derived.OpenPrice, native.OpenPrice, priceEqual)
+	check("high", derived.HighPrice, native.HighPrice, priceEqual)
+	check("low", derived.LowPrice, native.LowPrice, priceEqual)
+	check("close", derived.ClosePrice, native.ClosePrice, priceEqual)
+	check("volume", derived.Volume, native.Volume, volumeEqual)
+	check("quote_volume", derived.QuoteVolume, native.QuoteVolume, volumeEqual)
+	check("taker_buy_volume", derived.TakerBuyVolume, native.TakerBuyVolume, volumeEqual)
+	check("taker_buy_quote_volume", derived.TakerBuyQuoteVolume, native.TakerBuyQuoteVolume, volumeEqual)
+	check("trades", float64(derived.TradesCount), float64(native.TradesCount), func(a, b float64) bool {
+		return a == b
+	})
+
+	return mismatches
+}
+
+// priceEqual compares prices stored with eight decimals
+func priceEqual(a, b float64) bool {
+	return math.Abs(a-b) <= derivedPriceTolerance
+}
+
+// volumeEqual compares summed volumes with a relative tolerance
+func volumeEqual(a, b float64) bool {
+	diff := math.Abs(a - b)
+	return diff <= derivedPriceTolerance || diff <= derivedVolumeTolerance*math.Max(math.Abs(a), math.Abs(b))
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/binance-live/internal/binance"
+)
+
+// timeBucket is TimescaleDB's integer time_bucket(width, ts, offset), which the derived
+// views and ResampleKlines group base candles with
+func timeBucket(width, ts, offset int64) int64 {
+	bucket := (ts - offset) / width * width
+	if (ts-offset)%width < 0 {
+		bucket -= width
+	}
+	return bucket + offset
+}
+
+func TestDerivedKlineViewMatchesBinanceOpenTimes(t *testing.T) {
+	tests := []struct {
+		base     binance.Interval
+		interval binance.Interval
+		openTime int64 // Of a Binance candle
+	}{
+		{binance.Interval1m, binance.Interval1w, 1704672000000}, // Monday 2024-01-08
+		{binance.Interval1d, binance.Interval1w, 1704672000000},
+		{binance.Interval1h, binance.Interval3d, 1704758400000}, // 2024-01-09
+		{binance.Interval1m, binance.Interval4h, 1704859200000}, // 2024-01-10T04:00Z
+		{binance.Interval1m, binance.Interval1w, -259200000},    // Monday 1969-12-29
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.interval)+" from "+string(tt.base), func(t *testing.T) {
+			view, err := NewDerivedKlineView(tt.base, tt.interval)
+			if err != nil {
+				t.Fatalf("failed to create derived view: %v", err)
+			}
+
+			// Every base candle of the Binance candle falls into the bucket opening with it
+			baseWidth := tt.base.Duration().Milliseconds()
+			for ts := tt.openTime; ts < tt.openTime+view.Width; ts += baseWidth {
+				if bucket := timeBucket(view.Width, ts, view.Offset); bucket != tt.openTime {
+					t.Fatalf("base candle %d is in bucket %d, want %d", ts, bucket, tt.openTime)
+				}
+			}
+			if bucket := timeBucket(view.Width, tt.openTime-baseWidth, view.Offset); bucket == tt.openTime {
+				t.Errorf("base candle before the open time is in bucket %d", bucket)
+			}
+
+			// And the bucket is where the service looks for the candle
+			if truncated := tt.interval.Truncate(time.UnixMilli(tt.openTime + view.Width/2)); truncated.UnixMilli() != tt.openTime {
+				t.Errorf("truncated to %d, want %d", truncated.UnixMilli(), tt.openTime)
+			}
+		})
+	}
+}
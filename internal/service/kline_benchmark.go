@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"time"
+
+	"github.com/binance-live/internal/models"
+	"github.com/binance-live/internal/repository"
+	"go.uber.org/zap"
+)
+
+// benchmarkHighSupplySymbol is the synthetic token whose volumes exceed DECIMAL(20, 8)
+const benchmarkHighSupplySymbol = "MEMEUSDT"
+
+// KlineBenchmarkOptions sizes the kline storage benchmark
+type KlineBenchmarkOptions struct {
+	Symbols   int // Regular symbols; one high-supply token is always added
+	Candles   int // 1m candles per symbol
+	BatchSize int // Inserts per batch
+	Passes    int // Full reads of every symbol
+	Compress  bool
+}
+
+// BenchmarkKlineStorage loads the same synthetic klines into a scratch hypertable per
+// kline layout (DECIMAL, DOUBLE PRECISION and scaled BIGINT) and reports insert and
+// query throughput and storage size. It needs a TimescaleDB that is not under load.
+func (s *MaintenanceService) BenchmarkKlineStorage(
+	ctx context.Context,
+	opts KlineBenchmarkOptions,
+) ([]repository.KlineLayoutResult, error) {
+	if opts.Symbols < 0 || opts.Candles <= 0 || opts.BatchSize <= 0 || opts.Passes <= 0 {
+		return nil, fmt.Errorf("symbols must not be negative; candles, batch size and passes must be positive")
+	}
+
+	klines := benchmarkKlines(opts.Symbols, opts.Candles)
+
+	results := make([]repository.KlineLayoutResult, 0, len(repository.KlineLayouts))
+	for _, layout := range repository.KlineLayouts {
+		s.logger.Info("Benchmarking kline layout",
+			zap.String("layout", layout.Name),
+			zap.String("column_type", layout.ColumnType),
+			zap.Int("klines", len(klines)),
+		)
+
+		result, err := s.maintenanceRepo.BenchmarkKlineLayout(ctx, layout, klines, opts.BatchSize, opts.Passes, opts.Compress)
+		if err != nil {
+			return results, err
+		}
+		results = append(results, *result)
+	}
+
+	return results, nil
+}
+
+// benchmarkKlines generates deterministic 1m candles: random walks for the regular
+// symbols and a sub-cent token traded in the trillions, like the largest meme coins
+func benchmarkKlines(symbols, candles int) []models.Kline {
+	rng := rand.New(rand.NewPCG(1, 2))
+	start := time.Now().Truncate(24*time.Hour).AddDate(0, 0, -1).UnixMilli() - int64(candles)*60000
+
+	klines := make([]models.Kline, 0, (symbols+1)*candles)
+	for i := 0; i <= symbols; i++ {
+		symbol := fmt.Sprintf("BENCH%dUSDT", i)
+		price, volumeScale := 100*float64(i+1), 1000.0
+		if i == symbols {
+			symbol, price, volumeScale = benchmarkHighSupplySymbol, 0.00001, 1e11
+		}
+
+		for c := 0; c < candles; c++ {
+			open := price
+			price *= math.Exp(rng.NormFloat64() * 0.001)
+			high := math.Max(open, price) * (1 + rng.Float64()*0.0005)
+			low := math.Min(open, price) * (1 - rng.Float64()*0.0005)
+
+			// Log-uniform volume over about two orders of magnitude
+			volume := volumeScale * math.Exp(rng.Float64()*4)
+			takerBuy := volume * rng.Float64()
+			openTime := start + int64(c)*60000
+
+			klines = append(klines, models.Kline{
+				Symbol:              symbol,
+				Interval:            "1m",
+				OpenTime:            openTime,
+				CloseTime:           openTime + 59999,
+				OpenPrice:           roundTo8(open),
+				HighPrice:           roundTo8(high),
+				LowPrice:            roundTo8(low),
+				ClosePrice:          roundTo8(price),
+				Volume:              roundTo8(volume),
+				QuoteVolume:         roundTo8(volume * price),
+				TradesCount:         1 + rng.IntN(5000),
+				TakerBuyVolume:      roundTo8(takerBuy),
+				TakerBuyQuoteVolume: roundTo8(takerBuy * price),
+			})
+		}
+	}
+
+	return klines
+}
+
+// roundTo8 rounds to the eight decimals Binance quotes prices and quantities with
+func roundTo8(value float64) float64 {
+	return math.Round(value*1e8) / 1e8
+}
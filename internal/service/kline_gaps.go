@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/binance-live/internal/binance"
+	"github.com/binance-live/internal/models"
+	"go.uber.org/zap"
+)
+
+// ScanKlineGaps detects missing klines for a symbol and interval with open times
+// in [start, end) and records them in kline_gaps. It returns the number of gaps found.
+func (s *DataSyncService) ScanKlineGaps(ctx context.Context, symbol string, interval binance.Interval, start, end time.Time) (int, error) {
+	gaps, err := s.klineGapRepo.FindGaps(ctx, symbol, interval.String(), start.UnixMilli(), end.UnixMilli())
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range gaps {
+		gap := &gaps[i]
+		gap.MissingCount = int(interval.Count(time.UnixMilli(gap.GapStart), time.UnixMilli(gap.GapEnd)))
+
+		if err := s.klineGapRepo.Upsert(ctx, gap); err != nil {
+			return 0, err
+		}
+
+		s.logger.Info("Kline gap detected",
+			zap.String("symbol", symbol),
+			zap.String("interval", interval.String()),
+			zap.Int64("gap_start", gap.GapStart),
+			zap.Int64("gap_end", gap.GapEnd),
+			zap.Int("missing", gap.MissingCount),
+		)
+	}
+
+	return len(gaps), nil
+}
+
+// ScanAllKlineGaps scans every active symbol and configured interval over the
+// last MaxSyncHours. It returns the total number of gaps found.
+func (s *DataSyncService) ScanAllKlineGaps(ctx context.Context) (int, error) {
+	symbols, err := s.symbolRepo.GetActiveSymbols(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get active symbols: %w", err)
+	}
+
+	intervals, err := binance.ParseIntervals(s.binanceConfig.NativeKlineIntervals())
+	if err != nil {
+		return 0, fmt.Errorf("invalid kline intervals: %w", err)
+	}
+
+	end := time.Now()
+	start := end.Add(-time.Duration(s.config.MaxSyncHours) * time.Hour)
+
+	total := 0
+	for _, symbol := range symbols {
+		for _, interval := range intervals {
+			found, err := s.ScanKlineGaps(ctx, symbol.Symbol, interval, start, end)
+			if err != nil {
+				return total, fmt.Errorf("failed to scan %s %s: %w", symbol.Symbol, interval, err)
+			}
+			total += found
+		}
+	}
+
+	s.logger.Info("Kline gap scan completed", zap.Int("gaps_found", total))
+	return total, nil
+}
+
+// RepairKlineGaps refetches up to limit open gaps through the REST API.
+// Gaps the exchange cannot fill are retried until sync.gap_max_attempts is reached.
+// It returns the number of gaps repaired.
+func (s *DataSyncService) RepairKlineGaps(ctx context.Context, limit int) (int, error) {
+	gaps, err := s.klineGapRepo.GetOpenGaps(ctx, s.config.GapMaxAttempts, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	repaired := 0
+	for _, gap := range gaps {
+		select {
+		case <-ctx.Done():
+			return repaired, ctx.Err()
+		default:
+		}
+
+		if err := s.repairKlineGap(ctx, &gap); err != nil {
+			s.logger.Warn("Failed to repair kline gap",
+				zap.Int64("id", gap.ID),
+				zap.String("symbol", gap.Symbol),
+				zap.String("interval", gap.Interval),
+				zap.Error(err),
+			)
+
+			if markErr := s.klineGapRepo.MarkFailed(ctx, gap.ID, err.Error()); markErr != nil {
+				return repaired, markErr
+			}
+			continue
+		}
+
+		if err := s.klineGapRepo.MarkRepaired(ctx, gap.ID); err != nil {
+			return repaired, err
+		}
+		repaired++
+	}
+
+	s.logger.Info("Kline gap repair completed",
+		zap.Int("attempted", len(gaps)),
+		zap.Int("repaired", repaired),
+	)
+
+	return repaired, nil
+}
+
+// repairKlineGap refetches exactly the missing range of a single gap
+func (s *DataSyncService) repairKlineGap(ctx context.Context, gap *models.KlineGap) error {
+	interval, err := binance.ParseInterval(gap.Interval)
+	if err != nil {
+		return err
+	}
+
+	result, err := s.backfillKlines(ctx, gap.Symbol, interval, time.UnixMilli(gap.GapStart), time.UnixMilli(gap.GapEnd), s.config.BatchSize, nil)
+	if err != nil {
+		return err
+	}
+
+	if result.Received < int64(gap.MissingCount) {
+		return fmt.Errorf("exchange returned %d of %d missing klines", result.Received, gap.MissingCount)
+	}
+
+	return nil
+}
@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/binance-live/internal/config"
+	"github.com/binance-live/internal/repository"
+	"go.uber.org/zap"
+)
+
+// RetentionRule is the retention of one table, or of one kline interval
+type RetentionRule struct {
+	Table    string
+	Interval string // Klines only
+	Days     int    // 0 keeps data forever
+}
+
+// RetentionResult reports the rows deleted by one retention rule
+type RetentionResult struct {
+	Rule    RetentionRule
+	Cutoff  time.Time
+	Deleted int64
+}
+
+// MaintenanceService applies the configured retention and compression policies
+// to the hypertables
+type MaintenanceService struct {
+	maintenanceRepo *repository.MaintenanceRepository
+	config          *config.RetentionConfig
+	binanceConfig   *config.BinanceConfig
+	logger          *zap.Logger
+}
+
+// NewMaintenanceService creates a new maintenance service
+func NewMaintenanceService(
+	maintenanceRepo *repository.MaintenanceRepository,
+	cfg *config.RetentionConfig,
+	binanceCfg *config.BinanceConfig,
+	logger *zap.Logger,
+) *MaintenanceService {
+	return &MaintenanceService{
+		maintenanceRepo: maintenanceRepo,
+		config:          cfg,
+		binanceConfig:   binanceCfg,
+		logger:          logger,
+	}
+}
+
+// RetentionRules returns the retention of every stored kline interval followed by the
+// other tables. Derived intervals are not listed; they follow their base interval.
+func (s *MaintenanceService) RetentionRules() []RetentionRule {
+	var rules []RetentionRule
+	seen := make(map[string]bool)
+
+	addInterval := func(interval string) {
+		if seen[interval] {
+			return
+		}
+		seen[interval] = true
+		rules = append(rules, RetentionRule{
+			Table:    "klines",
+			Interval: interval,
+			Days:     s.config.Klines.DaysFor(interval),
+		})
+	}
+
+	for _, interval := range s.binanceConfig.NativeKlineIntervals() {
+		addInterval(interval)
+	}
+	for _, r := range s.config.Klines.Intervals {
+		addInterval(r.Interval)
+	}
+
+	return append(rules,
+		RetentionRule{Table: "tickers", Days: s.config.TickersDays},
+		RetentionRule{Table: "trades", Days: s.config.TradesDays},
+		RetentionRule{Table: "depth_snapshots", Days: s.config.DepthSnapshotsDays},
+		RetentionRule{Table: "bars", Days: s.config.BarsDays},
+	)
+}
+
+// ApplyRetention deletes the rows older than their retention period. Tables that
+// do not exist are skipped.
+func (s *MaintenanceService) ApplyRetention(ctx context.Context) ([]RetentionResult, error) {
+	existing, err := s.existingTables(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []RetentionResult
+	for _, rule := range s.RetentionRules() {
+		if rule.Days <= 0 || !existing[rule.Table] {
+			continue
+		}
+
+		cutoff := time.Now().Add(-time.Duration(rule.Days) * 24 * time.Hour)
+
+		var deleted int64
+		if rule.Table == "klines" {
+			deleted, err = s.maintenanceRepo.DeleteOldKlines(ctx, rule.Interval, cutoff.UnixMilli())
+		} else {
+			deleted, err = s.maintenanceRepo.DeleteOldRows(ctx, rule.Table, cutoff.UnixMilli())
+		}
+		if err != nil {
+			return results, err
+		}
+
+		s.logger.Info("Applied retention",
+			zap.String("table", rule.Table),
+			zap.String("interval", rule.Interval),
+			zap.Time("cutoff", cutoff),
+			zap.Int64("deleted", deleted),
+		)
+		results = append(results, RetentionResult{Rule: rule, Cutoff: cutoff, Deleted: deleted})
+	}
+
+	return results, nil
+}
+
+// ApplyCompression enables compression on every existing hypertable and sets its
+// compression policy to compress_after_days. A zero setting removes the policies.
+func (s *MaintenanceService) ApplyCompression(ctx context.Context) error {
+	compressAfter := (time.Duration(s.config.CompressAfterDays) * 24 * time.Hour).Milliseconds()
+
+	for _, table := range repository.Hypertables {
+		stats, err := s.maintenanceRepo.Stats(ctx, table)
+		if err != nil {
+			return err
+		}
+		if !stats.Exists {
+			continue
+		}
+
+		if compressAfter > 0 && !stats.CompressionEnabled {
+			if err := s.maintenanceRepo.EnableCompression(ctx, table); err != nil {
+				return err
+			}
+			stats.CompressionEnabled = true
+		}
+
+		if !stats.CompressionEnabled {
+			continue
+		}
+		if stats.CompressAfter != nil && *stats.CompressAfter == compressAfter {
+			continue
+		}
+		if stats.CompressAfter == nil && compressAfter == 0 {
+			continue
+		}
+
+		if err := s.maintenanceRepo.SetCompressionPolicy(ctx, table, compressAfter); err != nil {
+			return err
+		}
+
+		s.logger.Info("Set compression policy",
+			zap.String("table", table.Name),
+			zap.String("segment_by", table.SegmentBy),
+			zap.Int("compress_after_days", s.config.CompressAfterDays),
+		)
+	}
+
+	return nil
+}
+
+// Inspect returns the storage and compression state of every managed hypertable
+func (s *MaintenanceService) Inspect(ctx context.Context) ([]repository.HypertableStats, error) {
+	stats := make([]repository.HypertableStats, 0, len(repository.Hypertables))
+	for _, table := range repository.Hypertables {
+		tableStats, err := s.maintenanceRepo.Stats(ctx, table)
+		if err != nil {
+			return nil, err
+		}
+		stats = append(stats, *tableStats)
+	}
+
+	return stats, nil
+}
+
+// existingTables returns the managed hypertables present in the database
+func (s *MaintenanceService) existingTables(ctx context.Context) (map[string]bool, error) {
+	stats, err := s.Inspect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	existing := make(map[string]bool, len(stats))
+	for _, table := range stats {
+		existing[table.Name] = table.Exists
+	}
+
+	return existing, nil
+}
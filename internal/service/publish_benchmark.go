@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/binance-live/internal/config"
+	"github.com/binance-live/internal/models"
+	"github.com/binance-live/internal/publisher"
+	"github.com/binance-live/internal/redis"
+	"go.uber.org/zap"
+)
+
+// PublishBenchmarkOptions sizes the Redis publishing benchmark
+type PublishBenchmarkOptions struct {
+	Symbols       int // Synthetic symbols the klines are spread over
+	Messages      int // Klines published per strategy
+	BatchSize     int // Messages per pipeline of the batched strategy
+	FlushInterval int // Milliseconds between flushes of the batched strategy
+}
+
+// PublishBenchmarkResult is the throughput of one publishing strategy
+type PublishBenchmarkResult struct {
+	Strategy   string
+	Messages   int
+	RoundTrips int // Network round trips, the fewest possible when batched
+	Errors     int
+	Duration   time.Duration
+}
+
+// BenchmarkPublishing publishes the same synthetic klines (PUBLISH plus SET of the
+// latest value each) with separate commands, one pipeline per message and batched
+// pipelines, and reports the throughput of each. It writes to the BENCH*USDT channels
+// and deletes their cached keys afterwards; run it against a local Redis.
+func BenchmarkPublishing(
+	ctx context.Context,
+	redisClient *redis.Client,
+	opts PublishBenchmarkOptions,
+	logger *zap.Logger,
+) ([]PublishBenchmarkResult, error) {
+	if opts.Symbols <= 0 || opts.Messages <= 0 || opts.BatchSize <= 0 || opts.FlushInterval <= 0 {
+		return nil, fmt.Errorf("symbols, messages, batch size and flush interval must be positive")
+	}
+
+	klines := make([]models.Kline, opts.Messages)
+	for i := range klines {
+		klines[i] = models.Kline{
+			Symbol:     fmt.Sprintf("BENCH%dUSDT", i%opts.Symbols),
+			Interval:   "1m",
+			OpenTime:   int64(i/opts.Symbols) * 60000,
+			ClosePrice: float64(i),
+		}
+	}
+
+	defer func() {
+		keys := make([]string, 0, opts.Symbols)
+		for i := 0; i < opts.Symbols && i < len(klines); i++ {
+			keys = append(keys, publisher.LatestKey(publisher.KlineChannel(klines[i].Symbol, "1m")))
+		}
+		if err := redisClient.Delete(context.WithoutCancel(ctx), keys...); err != nil {
+			logger.Warn("Failed to delete benchmark keys", zap.Error(err))
+		}
+	}()
+
+	results := make([]PublishBenchmarkResult, 0, 3)
+
+	// Separate commands: a PUBLISH and a SET round trip per message
+	logger.Info("Benchmarking separate commands", zap.Int("messages", len(klines)))
+	result := PublishBenchmarkResult{Strategy: "separate", Messages: len(klines), RoundTrips: 2 * len(klines)}
+	start := time.Now()
+	for i := range klines {
+		liveData := publisher.KlineLiveData(&klines[i])
+		channel := publisher.KlineChannel(klines[i].Symbol, klines[i].Interval)
+		if err := redisClient.PublishProtobuf(ctx, channel, liveData); err != nil {
+			result.Errors++
+		}
+		if err := redisClient.SetProtobuf(ctx, publisher.LatestKey(channel), liveData, 0); err != nil {
+			result.Errors++
+		}
+	}
+	result.Duration = time.Since(start)
+	results = append(results, result)
+
+	// One pipeline per message
+	logger.Info("Benchmarking pipelined messages", zap.Int("messages", len(klines)))
+	pipelined := publisher.NewProtobufPublisher(redisClient, logger)
+	result = PublishBenchmarkResult{Strategy: "pipelined", Messages: len(klines), RoundTrips: len(klines)}
+	start = time.Now()
+	for i := range klines {
+		if err := pipelined.PublishKline(ctx, &klines[i]); err != nil {
+			result.Errors++
+		}
+	}
+	result.Duration = time.Since(start)
+	results = append(results, result)
+
+	// Batched pipelines, flushed by size or timer; failed messages are logged
+	logger.Info("Benchmarking batched messages",
+		zap.Int("messages", len(klines)),
+		zap.Int("batch_size", opts.BatchSize),
+		zap.Int("flush_interval_ms", opts.FlushInterval),
+	)
+	batched, err := publisher.New(redisClient, &config.PublisherConfig{
+		Mode:          string(publisher.PublishPubSub),
+		FlushInterval: opts.FlushInterval,
+		BatchSize:     opts.BatchSize,
+	}, logger)
+	if err != nil {
+		return nil, err
+	}
+	result = PublishBenchmarkResult{
+		Strategy:   "batched",
+		Messages:   len(klines),
+		RoundTrips: (len(klines) + opts.BatchSize - 1) / opts.BatchSize,
+	}
+	start = time.Now()
+	for i := range klines {
+		if err := batched.PublishKline(ctx, &klines[i]); err != nil {
+			result.Errors++
+		}
+	}
+	if err := batched.Close(); err != nil {
+		return nil, err
+	}
+	result.Duration = time.Since(start)
+	results = append(results, result)
+
+	return results, nil
+}
@@ -21,6 +21,9 @@ type StreamService struct {
 	tickerRepo     *repository.TickerRepository
 	syncStatusRepo *repository.SyncStatusRepository
 	publisher      publisher.Publisher
+	barService     *BarService    // nil: no bars are built
+	tickerSampler  *TickerSampler // nil: every ticker is stored
+	progress       *streamProgress
 	logger         *zap.Logger
 }
 
@@ -31,6 +34,8 @@ func NewStreamService(
 	tickerRepo *repository.TickerRepository,
 	syncStatusRepo *repository.SyncStatusRepository,
 	pub *publisher.Publisher,
+	barService *BarService,
+	tickerSampler *TickerSampler,
 	logger *zap.Logger,
 ) *StreamService {
 	return &StreamService{
@@ -39,6 +44,9 @@ func NewStreamService(
 		tickerRepo:     tickerRepo,
 		syncStatusRepo: syncStatusRepo,
 		publisher:      *pub,
+		barService:     barService,
+		tickerSampler:  tickerSampler,
+		progress:       newStreamProgress(),
 		logger:         logger,
 	}
 }
@@ -55,7 +63,12 @@ func (s *StreamService) Start(ctx context.Context, symbols []models.Symbol) erro
 		symbolNames[i] = sym.Symbol
 	}
 
-	streams := binance.BuildStreamNames(symbolNames, s.binanceClient.Config.KlineIntervals)
+	intervals, err := binance.ParseIntervals(s.binanceClient.Config.NativeKlineIntervals())
+	if err != nil {
+		return fmt.Errorf("invalid kline intervals: %w", err)
+	}
+
+	streams := binance.BuildStreamNames(symbolNames, intervals)
 
 	s.logger.Info("Starting WebSocket streams",
 		zap.Int("symbol_count", len(symbolNames)),
@@ -74,6 +87,14 @@ func (s *StreamService) Start(ctx context.Context, symbols []models.Symbol) erro
 		}
 	}()
 
+	// Record stream progress in sync_status
+	go s.runProgressFlusher(ctx)
+
+	// Close time bars on the wall clock
+	if s.barService != nil {
+		go s.barService.Run(ctx)
+	}
+
 	s.logger.Info("WebSocket streams started successfully")
 	return nil
 }
@@ -133,18 +154,7 @@ func (s *StreamService) handleKlineEvent(message []byte, symbol, interval string
 	}
 
 	// Update sync status
-	if err := s.syncStatusRepo.UpsertSyncStatus(ctx, &models.SyncStatus{
-		Symbol:       symbol,
-		DataType:     "kline",
-		Interval:     &interval,
-		LastSyncTime: time.Now().UnixMilli(),
-		LastDataTime: kline.OpenTime,
-		Status:       "active",
-		ErrorMessage: nil,
-		UpdatedAt:    time.Now().UnixMilli(),
-	}); err != nil {
-		s.logger.Warn("Failed to update sync status", zap.Error(err))
-	}
+	s.progress.Record(models.KlineStreamKey(symbol, interval), kline.OpenTime)
 
 	return nil
 }
@@ -162,10 +172,12 @@ func (s *StreamService) handleTickerEvent(message []byte, symbol string) error {
 		return fmt.Errorf("failed to convert ticker: %w", err)
 	}
 
-	// Store in database
+	// Store in database, unless the sampler skips this event
 	ctx := context.Background()
-	if err := s.tickerRepo.Insert(ctx, ticker); err != nil {
-		s.logger.Error("Failed to insert ticker", zap.Error(err))
+	if s.tickerSampler == nil || s.tickerSampler.ShouldStore(ticker) {
+		if err := s.tickerRepo.Insert(ctx, ticker); err != nil {
+			s.logger.Error("Failed to insert ticker", zap.Error(err))
+		}
 	}
 
 	// Publish to Redis
@@ -173,6 +185,9 @@ func (s *StreamService) handleTickerEvent(message []byte, symbol string) error {
 		s.logger.Error("Failed to publish ticker", zap.Error(err))
 	}
 
+	// Update sync status
+	s.progress.Record(models.SpotStreamKey(symbol, models.DataTypeTicker), ticker.Timestamp)
+
 	return nil
 }
 
@@ -195,6 +210,9 @@ func (s *StreamService) handleDepthEvent(message []byte, symbol string) error {
 		s.logger.Error("Failed to publish depth", zap.Error(err))
 	}
 
+	// Update sync status
+	s.progress.Record(models.SpotStreamKey(symbol, models.DataTypeDepth), depth.Timestamp)
+
 	return nil
 }
 
@@ -217,6 +235,16 @@ func (s *StreamService) handleTradeEvent(message []byte, symbol string) error {
 		s.logger.Error("Failed to publish trade", zap.Error(err))
 	}
 
+	// Build bars
+	if s.barService != nil {
+		if err := s.barService.HandleAggTrade(ctx, &event); err != nil {
+			s.logger.Error("Failed to build bars", zap.Error(err))
+		}
+	}
+
+	// Update sync status
+	s.progress.Record(models.SpotStreamKey(symbol, models.DataTypeTrade), trade.Timestamp)
+
 	return nil
 }
 
@@ -247,6 +275,8 @@ func (s *StreamService) convertWSKlineToModel(event *binance.WSKlineEvent, symbo
 		TakerBuyVolume:      takerBuyVolume,
 		TakerBuyQuoteVolume: takerBuyQuoteVolume,
 		CreatedAt:           time.Now().UnixMilli(),
+		IsClosed:            event.Kline.IsClosed,
+		EventTime:           event.EventTime,
 	}, nil
 }
 
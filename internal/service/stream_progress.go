@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/binance-live/internal/models"
+	"go.uber.org/zap"
+)
+
+// streamProgressFlushInterval is how often live stream progress is written to sync_status
+const streamProgressFlushInterval = 5 * time.Second
+
+// streamProgress collects the newest event time of every live stream between flushes,
+// so a busy trade stream costs one sync_status write per flush instead of one per event
+type streamProgress struct {
+	mu      sync.Mutex
+	pending map[models.StreamKey]int64
+}
+
+// newStreamProgress creates an empty progress tracker
+func newStreamProgress() *streamProgress {
+	return &streamProgress{pending: make(map[models.StreamKey]int64)}
+}
+
+// Record notes that the stream has data up to dataTime
+func (p *streamProgress) Record(key models.StreamKey, dataTime int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if dataTime > p.pending[key] {
+		p.pending[key] = dataTime
+	}
+}
+
+// take returns the progress recorded since the last call
+func (p *streamProgress) take() map[models.StreamKey]int64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	pending := p.pending
+	p.pending = make(map[models.StreamKey]int64, len(pending))
+	return pending
+}
+
+// runProgressFlusher writes the recorded stream progress to sync_status until ctx is
+// cancelled, then flushes once more
+func (s *StreamService) runProgressFlusher(ctx context.Context) {
+	ticker := time.NewTicker(streamProgressFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.flushProgress(context.WithoutCancel(ctx))
+			return
+		case <-ticker.C:
+			s.flushProgress(ctx)
+		}
+	}
+}
+
+// flushProgress writes the progress recorded since the last flush
+func (s *StreamService) flushProgress(ctx context.Context) {
+	for key, dataTime := range s.progress.take() {
+		if err := s.syncStatusRepo.RecordProgress(ctx, key, dataTime); err != nil {
+			s.logger.Warn("Failed to update sync status", zap.String("stream", key.String()), zap.Error(err))
+		}
+	}
+}
@@ -0,0 +1,270 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/binance-live/internal/binance"
+	"github.com/binance-live/internal/models"
+	"github.com/binance-live/internal/repository"
+	"go.uber.org/zap"
+)
+
+// syncJobPollInterval is how long an idle worker waits before polling the queue again
+const syncJobPollInterval = time.Second
+
+// syncJobLost is what runSyncJob returns for a job another worker took over
+const syncJobLost = "lost"
+
+// syncJobRetryDelay is multiplied by the attempt number to space out retries
+const syncJobRetryDelay = 10 * time.Second
+
+// SyncJobsResult summarizes a run of the sync job workers
+type SyncJobsResult struct {
+	Completed int // Jobs finished successfully
+	Retried   int // Failures that were queued for another attempt
+	Failed    int // Jobs that used all their attempts
+	Lost      int // Jobs requeued as stale and claimed by another worker while running
+}
+
+// RunSyncJobs processes queued sync jobs with the given number of workers until the queue
+// has no pending or running jobs left. Jobs are claimed with FOR UPDATE SKIP LOCKED, so
+// several processes can run workers against the same queue. Running jobs whose lock has
+// gone stale are handed back to the queue first.
+func (s *DataSyncService) RunSyncJobs(ctx context.Context, workers int) (*SyncJobsResult, error) {
+	if workers <= 0 {
+		workers = 1
+	}
+
+	if err := s.requeueStaleSyncJobs(ctx); err != nil {
+		return nil, err
+	}
+
+	hostname, _ := os.Hostname()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+		result   = &SyncJobsResult{}
+	)
+
+	for n := 0; n < workers; n++ {
+		wg.Add(1)
+
+		worker := fmt.Sprintf("%s-%d-%d", hostname, os.Getpid(), n)
+		go func() {
+			defer wg.Done()
+
+			if err := s.runSyncJobWorker(ctx, worker, result, &mu); err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	return result, firstErr
+}
+
+// runSyncJobWorker claims and runs jobs until the queue is drained or ctx is done
+func (s *DataSyncService) runSyncJobWorker(
+	ctx context.Context,
+	worker string,
+	result *SyncJobsResult,
+	mu *sync.Mutex,
+) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		job, err := s.syncJobRepo.ClaimNext(ctx, worker)
+		if err != nil {
+			return err
+		}
+
+		if job == nil {
+			// Nothing runnable right now; stop once no job is pending or running anywhere
+			open, err := s.syncJobRepo.CountOpen(ctx)
+			if err != nil {
+				return err
+			}
+			if open == 0 {
+				return nil
+			}
+
+			if err := s.requeueStaleSyncJobs(ctx); err != nil {
+				return err
+			}
+
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(syncJobPollInterval):
+			}
+			continue
+		}
+
+		state := s.runSyncJob(ctx, worker, job)
+
+		mu.Lock()
+		switch state {
+		case "completed":
+			result.Completed++
+		case "pending":
+			result.Retried++
+		case "failed":
+			result.Failed++
+		case syncJobLost:
+			result.Lost++
+		}
+		mu.Unlock()
+	}
+}
+
+// runSyncJob runs a claimed job and records its outcome. It returns the job's new state,
+// or syncJobLost when another worker took the job over.
+func (s *DataSyncService) runSyncJob(ctx context.Context, worker string, job *models.SyncJob) string {
+	logger := s.logger.With(
+		zap.Int64("job_id", job.ID),
+		zap.String("worker", worker),
+		zap.String("symbol", job.Symbol),
+		zap.String("interval", job.Interval),
+		zap.Int("attempt", job.Attempts),
+	)
+
+	jobCtx, cancel := context.WithCancelCause(ctx)
+	heartbeatDone := make(chan struct{})
+	go func() {
+		defer close(heartbeatDone)
+		s.heartbeatSyncJob(jobCtx, job, cancel)
+	}()
+
+	err := s.executeSyncJob(jobCtx, job, cancel)
+	lost := errors.Is(context.Cause(jobCtx), repository.ErrSyncJobLockLost)
+	cancel(nil)
+	<-heartbeatDone
+
+	if lost {
+		logger.Warn("Sync job was taken over by another worker, abandoning it", zap.Error(err))
+		return syncJobLost
+	}
+
+	// Bookkeeping must survive cancellation so the job is not left running
+	ctx = context.WithoutCancel(ctx)
+
+	if err == nil {
+		err := s.syncJobRepo.Complete(ctx, job)
+		if errors.Is(err, repository.ErrSyncJobLockLost) {
+			logger.Warn("Sync job was taken over by another worker before it completed")
+			return syncJobLost
+		}
+		if err != nil {
+			logger.Error("Failed to complete sync job", zap.Error(err))
+		}
+		return job.State
+	}
+
+	retryAfter := time.Duration(job.Attempts) * syncJobRetryDelay
+	if failErr := s.syncJobRepo.Fail(ctx, job, err.Error(), retryAfter); failErr != nil {
+		if errors.Is(failErr, repository.ErrSyncJobLockLost) {
+			logger.Warn("Sync job was taken over by another worker before it failed", zap.Error(err))
+			return syncJobLost
+		}
+		logger.Error("Failed to record sync job failure", zap.Error(failErr))
+		return "running"
+	}
+
+	logger.Error("Sync job failed",
+		zap.String("state", job.State),
+		zap.Error(err),
+	)
+
+	return job.State
+}
+
+// heartbeatSyncJob refreshes the lock of job until ctx is done, independently of page
+// progress, so a slow page does not make the job look stale. When another worker has
+// taken the job over it cancels the job with repository.ErrSyncJobLockLost.
+func (s *DataSyncService) heartbeatSyncJob(ctx context.Context, job *models.SyncJob, cancel context.CancelCauseFunc) {
+	staleAfter := time.Duration(s.config.JobStaleAfter) * time.Second
+	if staleAfter <= 0 {
+		return // Jobs are never requeued
+	}
+
+	ticker := time.NewTicker(max(staleAfter/3, time.Second))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := s.syncJobRepo.Heartbeat(ctx, job)
+			if errors.Is(err, repository.ErrSyncJobLockLost) {
+				cancel(err)
+				return
+			}
+			if err != nil && ctx.Err() == nil {
+				s.logger.Warn("Failed to refresh sync job lock", zap.Int64("job_id", job.ID), zap.Error(err))
+			}
+		}
+	}
+}
+
+// executeSyncJob performs the sync work of a job, saving progress after every page. It
+// cancels the job with repository.ErrSyncJobLockLost when another worker took it over.
+func (s *DataSyncService) executeSyncJob(ctx context.Context, job *models.SyncJob, cancel context.CancelCauseFunc) error {
+	if job.DataType != "kline" {
+		return fmt.Errorf("unsupported sync job data type: %q", job.DataType)
+	}
+
+	interval, err := binance.ParseInterval(job.Interval)
+	if err != nil {
+		return err
+	}
+
+	opts := s.defaultSyncOptions()
+	opts.Progress = func(klinesFetched, lastDataTime int64) {
+		job.KlinesFetched = klinesFetched
+		job.LastDataTime = lastDataTime
+		err := s.syncJobRepo.UpdateProgress(ctx, job)
+		if errors.Is(err, repository.ErrSyncJobLockLost) {
+			cancel(err)
+			return
+		}
+		if err != nil {
+			s.logger.Warn("Failed to update sync job progress", zap.Int64("job_id", job.ID), zap.Error(err))
+		}
+	}
+
+	return s.syncKlinesForSymbol(ctx, job.Symbol, interval, opts)
+}
+
+// requeueStaleSyncJobs releases running jobs whose worker stopped refreshing the lock
+func (s *DataSyncService) requeueStaleSyncJobs(ctx context.Context) error {
+	staleAfter := time.Duration(s.config.JobStaleAfter) * time.Second
+	if staleAfter <= 0 {
+		return nil
+	}
+
+	count, err := s.syncJobRepo.RequeueStale(ctx, time.Now().Add(-staleAfter).UnixMilli())
+	if err != nil {
+		return err
+	}
+
+	if count > 0 {
+		s.logger.Warn("Requeued stale sync jobs", zap.Int64("count", count))
+	}
+
+	return nil
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"sync"
+
+	"github.com/binance-live/internal/config"
+	"github.com/binance-live/internal/models"
+)
+
+// TickerStoreMode selects which ticker events are stored
+type TickerStoreMode string
+
+const (
+	TickerStoreAll    TickerStoreMode = "all"    // Every event
+	TickerStoreSample TickerStoreMode = "sample" // The first event of every sample interval
+	TickerStoreChange TickerStoreMode = "change" // Events that moved the price beyond the threshold
+)
+
+// TickerSampler decides which ticker events of the stream are worth storing.
+// Decisions are based on the event time, so they do not depend on delivery delays.
+type TickerSampler struct {
+	mode           TickerStoreMode
+	sampleInterval int64   // Milliseconds
+	threshold      float64 // Relative price change
+	maxInterval    int64   // Milliseconds, 0: no forced store
+
+	mu   sync.Mutex
+	last map[string]models.Ticker // Last stored ticker by symbol
+}
+
+// NewTickerSampler creates a ticker sampler from the tickers configuration
+func NewTickerSampler(cfg *config.TickersConfig) (*TickerSampler, error) {
+	s := &TickerSampler{
+		mode:           TickerStoreMode(cfg.StoreMode),
+		sampleInterval: int64(cfg.SampleInterval) * 1000,
+		threshold:      cfg.ChangeThreshold / 100,
+		maxInterval:    int64(cfg.MaxInterval) * 1000,
+		last:           make(map[string]models.Ticker),
+	}
+
+	switch s.mode {
+	case "":
+		s.mode = TickerStoreAll
+	case TickerStoreAll:
+	case TickerStoreSample:
+		if s.sampleInterval <= 0 {
+			return nil, fmt.Errorf("tickers.sample_interval must be positive in sample mode")
+		}
+	case TickerStoreChange:
+		if s.threshold <= 0 {
+			return nil, fmt.Errorf("tickers.change_threshold must be positive in change mode")
+		}
+		if s.maxInterval < 0 {
+			return nil, fmt.Errorf("tickers.max_interval must not be negative")
+		}
+	default:
+		return nil, fmt.Errorf("unknown tickers.store_mode %q (all, sample or change)", cfg.StoreMode)
+	}
+
+	return s, nil
+}
+
+// Mode returns the store mode
+func (s *TickerSampler) Mode() TickerStoreMode {
+	return s.mode
+}
+
+// ShouldStore reports whether ticker is stored, and if so remembers it as the last
+// stored ticker of its symbol
+func (s *TickerSampler) ShouldStore(ticker *models.Ticker) bool {
+	if s.mode == TickerStoreAll {
+		return true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	last, ok := s.last[ticker.Symbol]
+	if ok && !s.due(&last, ticker) {
+		return false
+	}
+
+	s.last[ticker.Symbol] = *ticker
+	return true
+}
+
+// due reports whether ticker is stored given the last stored ticker of its symbol
+func (s *TickerSampler) due(last, ticker *models.Ticker) bool {
+	if ticker.Timestamp <= last.Timestamp {
+		return false
+	}
+
+	if s.mode == TickerStoreSample {
+		return ticker.Timestamp/s.sampleInterval != last.Timestamp/s.sampleInterval
+	}
+
+	if s.maxInterval > 0 && ticker.Timestamp-last.Timestamp >= s.maxInterval {
+		return true
+	}
+	if last.Price == 0 {
+		return ticker.Price != 0
+	}
+	return math.Abs(ticker.Price-last.Price)/last.Price >= s.threshold
+}
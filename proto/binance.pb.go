@@ -4,6 +4,11 @@
 // 	protoc        v4.25.1
 // source: proto/binance.proto
 
+// Version 1 of the schema package. Changes within it must stay wire compatible: add
+// fields with new numbers, never renumber, retype or reuse a field (mark removed ones
+// reserved). Changes in meaning raise the schema version of published messages
+// (LiveData.version); incompatible changes go to a new package, binance.v2.
+
 package binance
 
 import (
@@ -81,8 +86,8 @@ func (DataType) EnumDescriptor() ([]byte, []int) {
 type KlineData struct {
 	state               protoimpl.MessageState `protogen:"open.v1"`
 	Interval            string                 `protobuf:"bytes,1,opt,name=interval,proto3" json:"interval,omitempty"`
-	OpenTime            int64                  `protobuf:"varint,2,opt,name=open_time,json=openTime,proto3" json:"open_time,omitempty"`    // Unix timestamp in seconds
-	CloseTime           int64                  `protobuf:"varint,3,opt,name=close_time,json=closeTime,proto3" json:"close_time,omitempty"` // Unix timestamp in seconds
+	OpenTime            int64                  `protobuf:"varint,2,opt,name=open_time,json=openTime,proto3" json:"open_time,omitempty"`    // Unix timestamp in milliseconds (seconds in schema version 1)
+	CloseTime           int64                  `protobuf:"varint,3,opt,name=close_time,json=closeTime,proto3" json:"close_time,omitempty"` // Unix timestamp in milliseconds (seconds in schema version 1)
 	OpenPrice           float64                `protobuf:"fixed64,4,opt,name=open_price,json=openPrice,proto3" json:"open_price,omitempty"`
 	HighPrice           float64                `protobuf:"fixed64,5,opt,name=high_price,json=highPrice,proto3" json:"high_price,omitempty"`
 	LowPrice            float64                `protobuf:"fixed64,6,opt,name=low_price,json=lowPrice,proto3" json:"low_price,omitempty"`
@@ -92,6 +97,7 @@ type KlineData struct {
 	TradesCount         int32                  `protobuf:"varint,10,opt,name=trades_count,json=tradesCount,proto3" json:"trades_count,omitempty"`
 	TakerBuyVolume      float64                `protobuf:"fixed64,11,opt,name=taker_buy_volume,json=takerBuyVolume,proto3" json:"taker_buy_volume,omitempty"`
 	TakerBuyQuoteVolume float64                `protobuf:"fixed64,12,opt,name=taker_buy_quote_volume,json=takerBuyQuoteVolume,proto3" json:"taker_buy_quote_volume,omitempty"`
+	IsClosed            bool                   `protobuf:"varint,13,opt,name=is_closed,json=isClosed,proto3" json:"is_closed,omitempty"` // Final update of the candle; bars are always closed
 	unknownFields       protoimpl.UnknownFields
 	sizeCache           protoimpl.SizeCache
 }
@@ -210,6 +216,13 @@ func (x *KlineData) GetTakerBuyQuoteVolume() float64 {
 	return 0
 }
 
+func (x *KlineData) GetIsClosed() bool {
+	if x != nil {
+		return x.IsClosed
+	}
+	return false
+}
+
 // Ticker data structure
 type TickerData struct {
 	state                  protoimpl.MessageState `protogen:"open.v1"`
@@ -537,7 +550,7 @@ func (x *TradeData) GetIsBuyerMaker() bool {
 // Main live data message
 type LiveData struct {
 	state     protoimpl.MessageState `protogen:"open.v1"`
-	Type      DataType               `protobuf:"varint,1,opt,name=type,proto3,enum=binance.DataType" json:"type,omitempty"`
+	Type      DataType               `protobuf:"varint,1,opt,name=type,proto3,enum=binance.v1.DataType" json:"type,omitempty"`
 	Symbol    string                 `protobuf:"bytes,2,opt,name=symbol,proto3" json:"symbol,omitempty"`
 	Timestamp int64                  `protobuf:"varint,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"` // Unix timestamp in milliseconds
 	// Types that are valid to be assigned to Data:
@@ -547,6 +560,8 @@ type LiveData struct {
 	//	*LiveData_Depth
 	//	*LiveData_Trade
 	Data          isLiveData_Data `protobuf_oneof:"data"`
+	Version       uint32          `protobuf:"varint,8,opt,name=version,proto3" json:"version,omitempty"` // Schema version, 0 for messages published before versioning (version 1)
+	Metadata      *Metadata       `protobuf:"bytes,9,opt,name=metadata,proto3" json:"metadata,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -645,6 +660,20 @@ func (x *LiveData) GetTrade() *TradeData {
 	return nil
 }
 
+func (x *LiveData) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *LiveData) GetMetadata() *Metadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
 type isLiveData_Data interface {
 	isLiveData_Data()
 }
@@ -673,6 +702,67 @@ func (*LiveData_Depth) isLiveData_Data() {}
 
 func (*LiveData_Trade) isLiveData_Data() {}
 
+// Delivery metadata of a live data message
+type Metadata struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sequence      uint64                 `protobuf:"varint,1,opt,name=sequence,proto3" json:"sequence,omitempty"`                          // Per channel and publisher, from 1 when it starts; 0 when not published
+	EventTime     int64                  `protobuf:"varint,2,opt,name=event_time,json=eventTime,proto3" json:"event_time,omitempty"`       // When the event happened at Binance, Unix timestamp in milliseconds
+	PublishTime   int64                  `protobuf:"varint,3,opt,name=publish_time,json=publishTime,proto3" json:"publish_time,omitempty"` // When the message was published, Unix timestamp in milliseconds
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Metadata) Reset() {
+	*x = Metadata{}
+	mi := &file_proto_binance_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Metadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Metadata) ProtoMessage() {}
+
+func (x *Metadata) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_binance_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Metadata.ProtoReflect.Descriptor instead.
+func (*Metadata) Descriptor() ([]byte, []int) {
+	return file_proto_binance_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Metadata) GetSequence() uint64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *Metadata) GetEventTime() int64 {
+	if x != nil {
+		return x.EventTime
+	}
+	return 0
+}
+
+func (x *Metadata) GetPublishTime() int64 {
+	if x != nil {
+		return x.PublishTime
+	}
+	return 0
+}
+
 // Symbol list message
 type SymbolList struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -684,7 +774,7 @@ type SymbolList struct {
 
 func (x *SymbolList) Reset() {
 	*x = SymbolList{}
-	mi := &file_proto_binance_proto_msgTypes[6]
+	mi := &file_proto_binance_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -696,7 +786,7 @@ func (x *SymbolList) String() string {
 func (*SymbolList) ProtoMessage() {}
 
 func (x *SymbolList) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_binance_proto_msgTypes[6]
+	mi := &file_proto_binance_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -709,7 +799,7 @@ func (x *SymbolList) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SymbolList.ProtoReflect.Descriptor instead.
 func (*SymbolList) Descriptor() ([]byte, []int) {
-	return file_proto_binance_proto_rawDescGZIP(), []int{6}
+	return file_proto_binance_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *SymbolList) GetSymbols() []string {
@@ -726,11 +816,662 @@ func (x *SymbolList) GetTimestamp() int64 {
 	return 0
 }
 
+// Live data with the channel it was published on, as sent by the WebSocket gateway
+type ChannelData struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Channel       string                 `protobuf:"bytes,1,opt,name=channel,proto3" json:"channel,omitempty"` // e.g. binance:kline:BTCUSDT:1m
+	Data          *LiveData              `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChannelData) Reset() {
+	*x = ChannelData{}
+	mi := &file_proto_binance_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChannelData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChannelData) ProtoMessage() {}
+
+func (x *ChannelData) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_binance_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChannelData.ProtoReflect.Descriptor instead.
+func (*ChannelData) Descriptor() ([]byte, []int) {
+	return file_proto_binance_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ChannelData) GetChannel() string {
+	if x != nil {
+		return x.Channel
+	}
+	return ""
+}
+
+func (x *ChannelData) GetData() *LiveData {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+// Page of live data messages returned by the HTTP and gRPC APIs
+type LiveDataList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Items         []*LiveData            `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	NextCursor    string                 `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"` // Pass as cursor for the next page, empty on the last page
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LiveDataList) Reset() {
+	*x = LiveDataList{}
+	mi := &file_proto_binance_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LiveDataList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LiveDataList) ProtoMessage() {}
+
+func (x *LiveDataList) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_binance_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LiveDataList.ProtoReflect.Descriptor instead.
+func (*LiveDataList) Descriptor() ([]byte, []int) {
+	return file_proto_binance_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *LiveDataList) GetItems() []*LiveData {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *LiveDataList) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+// Trading symbol details
+type SymbolInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	BaseAsset     string                 `protobuf:"bytes,2,opt,name=base_asset,json=baseAsset,proto3" json:"base_asset,omitempty"`
+	QuoteAsset    string                 `protobuf:"bytes,3,opt,name=quote_asset,json=quoteAsset,proto3" json:"quote_asset,omitempty"`
+	Status        string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	IsActive      bool                   `protobuf:"varint,5,opt,name=is_active,json=isActive,proto3" json:"is_active,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SymbolInfo) Reset() {
+	*x = SymbolInfo{}
+	mi := &file_proto_binance_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SymbolInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SymbolInfo) ProtoMessage() {}
+
+func (x *SymbolInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_binance_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SymbolInfo.ProtoReflect.Descriptor instead.
+func (*SymbolInfo) Descriptor() ([]byte, []int) {
+	return file_proto_binance_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *SymbolInfo) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *SymbolInfo) GetBaseAsset() string {
+	if x != nil {
+		return x.BaseAsset
+	}
+	return ""
+}
+
+func (x *SymbolInfo) GetQuoteAsset() string {
+	if x != nil {
+		return x.QuoteAsset
+	}
+	return ""
+}
+
+func (x *SymbolInfo) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *SymbolInfo) GetIsActive() bool {
+	if x != nil {
+		return x.IsActive
+	}
+	return false
+}
+
+// Symbol details list message
+type SymbolInfoList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbols       []*SymbolInfo          `protobuf:"bytes,1,rep,name=symbols,proto3" json:"symbols,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SymbolInfoList) Reset() {
+	*x = SymbolInfoList{}
+	mi := &file_proto_binance_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SymbolInfoList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SymbolInfoList) ProtoMessage() {}
+
+func (x *SymbolInfoList) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_binance_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SymbolInfoList.ProtoReflect.Descriptor instead.
+func (*SymbolInfoList) Descriptor() ([]byte, []int) {
+	return file_proto_binance_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *SymbolInfoList) GetSymbols() []*SymbolInfo {
+	if x != nil {
+		return x.Symbols
+	}
+	return nil
+}
+
+// Sync progress of one stream
+type SyncStatus struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Market        string                 `protobuf:"bytes,1,opt,name=market,proto3" json:"market,omitempty"`
+	Symbol        string                 `protobuf:"bytes,2,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	DataType      string                 `protobuf:"bytes,3,opt,name=data_type,json=dataType,proto3" json:"data_type,omitempty"`
+	Interval      string                 `protobuf:"bytes,4,opt,name=interval,proto3" json:"interval,omitempty"`                                // Empty for streams without an interval
+	LastSyncTime  int64                  `protobuf:"varint,5,opt,name=last_sync_time,json=lastSyncTime,proto3" json:"last_sync_time,omitempty"` // Unix timestamp in milliseconds
+	LastDataTime  int64                  `protobuf:"varint,6,opt,name=last_data_time,json=lastDataTime,proto3" json:"last_data_time,omitempty"` // Unix timestamp in milliseconds
+	Status        string                 `protobuf:"bytes,7,opt,name=status,proto3" json:"status,omitempty"`
+	ErrorMessage  *string                `protobuf:"bytes,8,opt,name=error_message,json=errorMessage,proto3,oneof" json:"error_message,omitempty"`
+	UpdatedAt     int64                  `protobuf:"varint,9,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"` // Unix timestamp in milliseconds
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SyncStatus) Reset() {
+	*x = SyncStatus{}
+	mi := &file_proto_binance_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyncStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncStatus) ProtoMessage() {}
+
+func (x *SyncStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_binance_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncStatus.ProtoReflect.Descriptor instead.
+func (*SyncStatus) Descriptor() ([]byte, []int) {
+	return file_proto_binance_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *SyncStatus) GetMarket() string {
+	if x != nil {
+		return x.Market
+	}
+	return ""
+}
+
+func (x *SyncStatus) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *SyncStatus) GetDataType() string {
+	if x != nil {
+		return x.DataType
+	}
+	return ""
+}
+
+func (x *SyncStatus) GetInterval() string {
+	if x != nil {
+		return x.Interval
+	}
+	return ""
+}
+
+func (x *SyncStatus) GetLastSyncTime() int64 {
+	if x != nil {
+		return x.LastSyncTime
+	}
+	return 0
+}
+
+func (x *SyncStatus) GetLastDataTime() int64 {
+	if x != nil {
+		return x.LastDataTime
+	}
+	return 0
+}
+
+func (x *SyncStatus) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *SyncStatus) GetErrorMessage() string {
+	if x != nil && x.ErrorMessage != nil {
+		return *x.ErrorMessage
+	}
+	return ""
+}
+
+func (x *SyncStatus) GetUpdatedAt() int64 {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return 0
+}
+
+// Sync status list message
+type SyncStatusList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Statuses      []*SyncStatus          `protobuf:"bytes,1,rep,name=statuses,proto3" json:"statuses,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SyncStatusList) Reset() {
+	*x = SyncStatusList{}
+	mi := &file_proto_binance_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyncStatusList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncStatusList) ProtoMessage() {}
+
+func (x *SyncStatusList) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_binance_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncStatusList.ProtoReflect.Descriptor instead.
+func (*SyncStatusList) Descriptor() ([]byte, []int) {
+	return file_proto_binance_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *SyncStatusList) GetStatuses() []*SyncStatus {
+	if x != nil {
+		return x.Statuses
+	}
+	return nil
+}
+
+// Klines with open times in [start_time, end_time), oldest first
+type GetKlinesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Interval      string                 `protobuf:"bytes,2,opt,name=interval,proto3" json:"interval,omitempty"`
+	StartTime     int64                  `protobuf:"varint,3,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"` // Unix timestamp in milliseconds
+	EndTime       int64                  `protobuf:"varint,4,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`       // Unix timestamp in milliseconds, 0 for no end
+	Limit         int32                  `protobuf:"varint,5,opt,name=limit,proto3" json:"limit,omitempty"`                          // Page size, 0 for the server default
+	Cursor        string                 `protobuf:"bytes,6,opt,name=cursor,proto3" json:"cursor,omitempty"`                         // next_cursor of the previous page
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetKlinesRequest) Reset() {
+	*x = GetKlinesRequest{}
+	mi := &file_proto_binance_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetKlinesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetKlinesRequest) ProtoMessage() {}
+
+func (x *GetKlinesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_binance_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetKlinesRequest.ProtoReflect.Descriptor instead.
+func (*GetKlinesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_binance_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GetKlinesRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *GetKlinesRequest) GetInterval() string {
+	if x != nil {
+		return x.Interval
+	}
+	return ""
+}
+
+func (x *GetKlinesRequest) GetStartTime() int64 {
+	if x != nil {
+		return x.StartTime
+	}
+	return 0
+}
+
+func (x *GetKlinesRequest) GetEndTime() int64 {
+	if x != nil {
+		return x.EndTime
+	}
+	return 0
+}
+
+func (x *GetKlinesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *GetKlinesRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+// Aggregated trades with timestamps in [start_time, end_time), in trade order
+type GetTradesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	StartTime     int64                  `protobuf:"varint,2,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"` // Unix timestamp in milliseconds
+	EndTime       int64                  `protobuf:"varint,3,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`       // Unix timestamp in milliseconds, 0 for no end
+	Limit         int32                  `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`                          // Page size, 0 for the server default
+	Cursor        string                 `protobuf:"bytes,5,opt,name=cursor,proto3" json:"cursor,omitempty"`                         // next_cursor of the previous page
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTradesRequest) Reset() {
+	*x = GetTradesRequest{}
+	mi := &file_proto_binance_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTradesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTradesRequest) ProtoMessage() {}
+
+func (x *GetTradesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_binance_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTradesRequest.ProtoReflect.Descriptor instead.
+func (*GetTradesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_binance_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GetTradesRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *GetTradesRequest) GetStartTime() int64 {
+	if x != nil {
+		return x.StartTime
+	}
+	return 0
+}
+
+func (x *GetTradesRequest) GetEndTime() int64 {
+	if x != nil {
+		return x.EndTime
+	}
+	return 0
+}
+
+func (x *GetTradesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *GetTradesRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+// Newest stored ticker of a symbol
+type GetLatestTickerRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLatestTickerRequest) Reset() {
+	*x = GetLatestTickerRequest{}
+	mi := &file_proto_binance_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLatestTickerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLatestTickerRequest) ProtoMessage() {}
+
+func (x *GetLatestTickerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_binance_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLatestTickerRequest.ProtoReflect.Descriptor instead.
+func (*GetLatestTickerRequest) Descriptor() ([]byte, []int) {
+	return file_proto_binance_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *GetLatestTickerRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+// Live data filter; an empty list matches everything
+type SubscribeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbols       []string               `protobuf:"bytes,1,rep,name=symbols,proto3" json:"symbols,omitempty"`
+	Types         []DataType             `protobuf:"varint,2,rep,packed,name=types,proto3,enum=binance.v1.DataType" json:"types,omitempty"`
+	Intervals     []string               `protobuf:"bytes,3,rep,name=intervals,proto3" json:"intervals,omitempty"` // Kline intervals or bar specs, ignored for other types
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscribeRequest) Reset() {
+	*x = SubscribeRequest{}
+	mi := &file_proto_binance_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscribeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeRequest) ProtoMessage() {}
+
+func (x *SubscribeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_binance_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeRequest.ProtoReflect.Descriptor instead.
+func (*SubscribeRequest) Descriptor() ([]byte, []int) {
+	return file_proto_binance_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *SubscribeRequest) GetSymbols() []string {
+	if x != nil {
+		return x.Symbols
+	}
+	return nil
+}
+
+func (x *SubscribeRequest) GetTypes() []DataType {
+	if x != nil {
+		return x.Types
+	}
+	return nil
+}
+
+func (x *SubscribeRequest) GetIntervals() []string {
+	if x != nil {
+		return x.Intervals
+	}
+	return nil
+}
+
 var File_proto_binance_proto protoreflect.FileDescriptor
 
 const file_proto_binance_proto_rawDesc = "" +
 	"\n" +
-	"\x13proto/binance.proto\x12\abinance\"\x9c\x03\n" +
+	"\x13proto/binance.proto\x12\n" +
+	"binance.v1\"\xb9\x03\n" +
 	"\tKlineData\x12\x1a\n" +
 	"\binterval\x18\x01 \x01(\tR\binterval\x12\x1b\n" +
 	"\topen_time\x18\x02 \x01(\x03R\bopenTime\x12\x1d\n" +
@@ -748,7 +1489,8 @@ const file_proto_binance_proto_rawDesc = "" +
 	"\ftrades_count\x18\n" +
 	" \x01(\x05R\vtradesCount\x12(\n" +
 	"\x10taker_buy_volume\x18\v \x01(\x01R\x0etakerBuyVolume\x123\n" +
-	"\x16taker_buy_quote_volume\x18\f \x01(\x01R\x13takerBuyQuoteVolume\"\x87\x05\n" +
+	"\x16taker_buy_quote_volume\x18\f \x01(\x01R\x13takerBuyQuoteVolume\x12\x1b\n" +
+	"\tis_closed\x18\r \x01(\bR\bisClosed\"\x87\x05\n" +
 	"\n" +
 	"TickerData\x12\x14\n" +
 	"\x05price\x18\x01 \x01(\x01R\x05price\x12 \n" +
@@ -781,11 +1523,11 @@ const file_proto_binance_proto_rawDesc = "" +
 	"\t_high_24hB\n" +
 	"\n" +
 	"\b_low_24hB\x13\n" +
-	"\x11_trades_count_24h\"\x83\x01\n" +
+	"\x11_trades_count_24h\"\x89\x01\n" +
 	"\tDepthData\x12$\n" +
-	"\x0elast_update_id\x18\x01 \x01(\x03R\flastUpdateId\x12'\n" +
-	"\x04bids\x18\x02 \x03(\v2\x13.binance.PriceLevelR\x04bids\x12'\n" +
-	"\x04asks\x18\x03 \x03(\v2\x13.binance.PriceLevelR\x04asks\">\n" +
+	"\x0elast_update_id\x18\x01 \x01(\x03R\flastUpdateId\x12*\n" +
+	"\x04bids\x18\x02 \x03(\v2\x16.binance.v1.PriceLevelR\x04bids\x12*\n" +
+	"\x04asks\x18\x03 \x03(\v2\x16.binance.v1.PriceLevelR\x04asks\">\n" +
 	"\n" +
 	"PriceLevel\x12\x14\n" +
 	"\x05price\x18\x01 \x01(\x01R\x05price\x12\x1a\n" +
@@ -795,26 +1537,92 @@ const file_proto_binance_proto_rawDesc = "" +
 	"\x05price\x18\x02 \x01(\x01R\x05price\x12\x1a\n" +
 	"\bquantity\x18\x03 \x01(\x01R\bquantity\x12%\n" +
 	"\x0equote_quantity\x18\x04 \x01(\x01R\rquoteQuantity\x12$\n" +
-	"\x0eis_buyer_maker\x18\x05 \x01(\bR\fisBuyerMaker\"\xa2\x02\n" +
-	"\bLiveData\x12%\n" +
-	"\x04type\x18\x01 \x01(\x0e2\x11.binance.DataTypeR\x04type\x12\x16\n" +
+	"\x0eis_buyer_maker\x18\x05 \x01(\bR\fisBuyerMaker\"\xfd\x02\n" +
+	"\bLiveData\x12(\n" +
+	"\x04type\x18\x01 \x01(\x0e2\x14.binance.v1.DataTypeR\x04type\x12\x16\n" +
 	"\x06symbol\x18\x02 \x01(\tR\x06symbol\x12\x1c\n" +
-	"\ttimestamp\x18\x03 \x01(\x03R\ttimestamp\x12*\n" +
-	"\x05kline\x18\x04 \x01(\v2\x12.binance.KlineDataH\x00R\x05kline\x12-\n" +
-	"\x06ticker\x18\x05 \x01(\v2\x13.binance.TickerDataH\x00R\x06ticker\x12*\n" +
-	"\x05depth\x18\x06 \x01(\v2\x12.binance.DepthDataH\x00R\x05depth\x12*\n" +
-	"\x05trade\x18\a \x01(\v2\x12.binance.TradeDataH\x00R\x05tradeB\x06\n" +
-	"\x04data\"D\n" +
+	"\ttimestamp\x18\x03 \x01(\x03R\ttimestamp\x12-\n" +
+	"\x05kline\x18\x04 \x01(\v2\x15.binance.v1.KlineDataH\x00R\x05kline\x120\n" +
+	"\x06ticker\x18\x05 \x01(\v2\x16.binance.v1.TickerDataH\x00R\x06ticker\x12-\n" +
+	"\x05depth\x18\x06 \x01(\v2\x15.binance.v1.DepthDataH\x00R\x05depth\x12-\n" +
+	"\x05trade\x18\a \x01(\v2\x15.binance.v1.TradeDataH\x00R\x05trade\x12\x18\n" +
+	"\aversion\x18\b \x01(\rR\aversion\x120\n" +
+	"\bmetadata\x18\t \x01(\v2\x14.binance.v1.MetadataR\bmetadataB\x06\n" +
+	"\x04data\"h\n" +
+	"\bMetadata\x12\x1a\n" +
+	"\bsequence\x18\x01 \x01(\x04R\bsequence\x12\x1d\n" +
+	"\n" +
+	"event_time\x18\x02 \x01(\x03R\teventTime\x12!\n" +
+	"\fpublish_time\x18\x03 \x01(\x03R\vpublishTime\"D\n" +
 	"\n" +
 	"SymbolList\x12\x18\n" +
 	"\asymbols\x18\x01 \x03(\tR\asymbols\x12\x1c\n" +
-	"\ttimestamp\x18\x02 \x01(\x03R\ttimestamp*z\n" +
+	"\ttimestamp\x18\x02 \x01(\x03R\ttimestamp\"Q\n" +
+	"\vChannelData\x12\x18\n" +
+	"\achannel\x18\x01 \x01(\tR\achannel\x12(\n" +
+	"\x04data\x18\x02 \x01(\v2\x14.binance.v1.LiveDataR\x04data\"[\n" +
+	"\fLiveDataList\x12*\n" +
+	"\x05items\x18\x01 \x03(\v2\x14.binance.v1.LiveDataR\x05items\x12\x1f\n" +
+	"\vnext_cursor\x18\x02 \x01(\tR\n" +
+	"nextCursor\"\x99\x01\n" +
+	"\n" +
+	"SymbolInfo\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x1d\n" +
+	"\n" +
+	"base_asset\x18\x02 \x01(\tR\tbaseAsset\x12\x1f\n" +
+	"\vquote_asset\x18\x03 \x01(\tR\n" +
+	"quoteAsset\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12\x1b\n" +
+	"\tis_active\x18\x05 \x01(\bR\bisActive\"B\n" +
+	"\x0eSymbolInfoList\x120\n" +
+	"\asymbols\x18\x01 \x03(\v2\x16.binance.v1.SymbolInfoR\asymbols\"\xb4\x02\n" +
+	"\n" +
+	"SyncStatus\x12\x16\n" +
+	"\x06market\x18\x01 \x01(\tR\x06market\x12\x16\n" +
+	"\x06symbol\x18\x02 \x01(\tR\x06symbol\x12\x1b\n" +
+	"\tdata_type\x18\x03 \x01(\tR\bdataType\x12\x1a\n" +
+	"\binterval\x18\x04 \x01(\tR\binterval\x12$\n" +
+	"\x0elast_sync_time\x18\x05 \x01(\x03R\flastSyncTime\x12$\n" +
+	"\x0elast_data_time\x18\x06 \x01(\x03R\flastDataTime\x12\x16\n" +
+	"\x06status\x18\a \x01(\tR\x06status\x12(\n" +
+	"\rerror_message\x18\b \x01(\tH\x00R\ferrorMessage\x88\x01\x01\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\t \x01(\x03R\tupdatedAtB\x10\n" +
+	"\x0e_error_message\"D\n" +
+	"\x0eSyncStatusList\x122\n" +
+	"\bstatuses\x18\x01 \x03(\v2\x16.binance.v1.SyncStatusR\bstatuses\"\xae\x01\n" +
+	"\x10GetKlinesRequest\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x1a\n" +
+	"\binterval\x18\x02 \x01(\tR\binterval\x12\x1d\n" +
+	"\n" +
+	"start_time\x18\x03 \x01(\x03R\tstartTime\x12\x19\n" +
+	"\bend_time\x18\x04 \x01(\x03R\aendTime\x12\x14\n" +
+	"\x05limit\x18\x05 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06cursor\x18\x06 \x01(\tR\x06cursor\"\x92\x01\n" +
+	"\x10GetTradesRequest\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x1d\n" +
+	"\n" +
+	"start_time\x18\x02 \x01(\x03R\tstartTime\x12\x19\n" +
+	"\bend_time\x18\x03 \x01(\x03R\aendTime\x12\x14\n" +
+	"\x05limit\x18\x04 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06cursor\x18\x05 \x01(\tR\x06cursor\"0\n" +
+	"\x16GetLatestTickerRequest\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\"v\n" +
+	"\x10SubscribeRequest\x12\x18\n" +
+	"\asymbols\x18\x01 \x03(\tR\asymbols\x12*\n" +
+	"\x05types\x18\x02 \x03(\x0e2\x14.binance.v1.DataTypeR\x05types\x12\x1c\n" +
+	"\tintervals\x18\x03 \x03(\tR\tintervals*z\n" +
 	"\bDataType\x12\x19\n" +
 	"\x15DATA_TYPE_UNSPECIFIED\x10\x00\x12\x13\n" +
 	"\x0fDATA_TYPE_KLINE\x10\x01\x12\x14\n" +
 	"\x10DATA_TYPE_TICKER\x10\x02\x12\x13\n" +
 	"\x0fDATA_TYPE_DEPTH\x10\x03\x12\x13\n" +
-	"\x0fDATA_TYPE_TRADE\x10\x04B'Z%github.com/binance-live/proto/binanceb\x06proto3"
+	"\x0fDATA_TYPE_TRADE\x10\x042\xad\x02\n" +
+	"\x11MarketDataService\x12C\n" +
+	"\tGetKlines\x12\x1c.binance.v1.GetKlinesRequest\x1a\x18.binance.v1.LiveDataList\x12C\n" +
+	"\tGetTrades\x12\x1c.binance.v1.GetTradesRequest\x1a\x18.binance.v1.LiveDataList\x12K\n" +
+	"\x0fGetLatestTicker\x12\".binance.v1.GetLatestTickerRequest\x1a\x14.binance.v1.LiveData\x12A\n" +
+	"\tSubscribe\x12\x1c.binance.v1.SubscribeRequest\x1a\x14.binance.v1.LiveData0\x01B'Z%github.com/binance-live/proto/binanceb\x06proto3"
 
 var (
 	file_proto_binance_proto_rawDescOnce sync.Once
@@ -829,30 +1637,55 @@ func file_proto_binance_proto_rawDescGZIP() []byte {
 }
 
 var file_proto_binance_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_proto_binance_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_proto_binance_proto_msgTypes = make([]protoimpl.MessageInfo, 18)
 var file_proto_binance_proto_goTypes = []any{
-	(DataType)(0),      // 0: binance.DataType
-	(*KlineData)(nil),  // 1: binance.KlineData
-	(*TickerData)(nil), // 2: binance.TickerData
-	(*DepthData)(nil),  // 3: binance.DepthData
-	(*PriceLevel)(nil), // 4: binance.PriceLevel
-	(*TradeData)(nil),  // 5: binance.TradeData
-	(*LiveData)(nil),   // 6: binance.LiveData
-	(*SymbolList)(nil), // 7: binance.SymbolList
+	(DataType)(0),                  // 0: binance.v1.DataType
+	(*KlineData)(nil),              // 1: binance.v1.KlineData
+	(*TickerData)(nil),             // 2: binance.v1.TickerData
+	(*DepthData)(nil),              // 3: binance.v1.DepthData
+	(*PriceLevel)(nil),             // 4: binance.v1.PriceLevel
+	(*TradeData)(nil),              // 5: binance.v1.TradeData
+	(*LiveData)(nil),               // 6: binance.v1.LiveData
+	(*Metadata)(nil),               // 7: binance.v1.Metadata
+	(*SymbolList)(nil),             // 8: binance.v1.SymbolList
+	(*ChannelData)(nil),            // 9: binance.v1.ChannelData
+	(*LiveDataList)(nil),           // 10: binance.v1.LiveDataList
+	(*SymbolInfo)(nil),             // 11: binance.v1.SymbolInfo
+	(*SymbolInfoList)(nil),         // 12: binance.v1.SymbolInfoList
+	(*SyncStatus)(nil),             // 13: binance.v1.SyncStatus
+	(*SyncStatusList)(nil),         // 14: binance.v1.SyncStatusList
+	(*GetKlinesRequest)(nil),       // 15: binance.v1.GetKlinesRequest
+	(*GetTradesRequest)(nil),       // 16: binance.v1.GetTradesRequest
+	(*GetLatestTickerRequest)(nil), // 17: binance.v1.GetLatestTickerRequest
+	(*SubscribeRequest)(nil),       // 18: binance.v1.SubscribeRequest
 }
 var file_proto_binance_proto_depIdxs = []int32{
-	4, // 0: binance.DepthData.bids:type_name -> binance.PriceLevel
-	4, // 1: binance.DepthData.asks:type_name -> binance.PriceLevel
-	0, // 2: binance.LiveData.type:type_name -> binance.DataType
-	1, // 3: binance.LiveData.kline:type_name -> binance.KlineData
-	2, // 4: binance.LiveData.ticker:type_name -> binance.TickerData
-	3, // 5: binance.LiveData.depth:type_name -> binance.DepthData
-	5, // 6: binance.LiveData.trade:type_name -> binance.TradeData
-	7, // [7:7] is the sub-list for method output_type
-	7, // [7:7] is the sub-list for method input_type
-	7, // [7:7] is the sub-list for extension type_name
-	7, // [7:7] is the sub-list for extension extendee
-	0, // [0:7] is the sub-list for field type_name
+	4,  // 0: binance.v1.DepthData.bids:type_name -> binance.v1.PriceLevel
+	4,  // 1: binance.v1.DepthData.asks:type_name -> binance.v1.PriceLevel
+	0,  // 2: binance.v1.LiveData.type:type_name -> binance.v1.DataType
+	1,  // 3: binance.v1.LiveData.kline:type_name -> binance.v1.KlineData
+	2,  // 4: binance.v1.LiveData.ticker:type_name -> binance.v1.TickerData
+	3,  // 5: binance.v1.LiveData.depth:type_name -> binance.v1.DepthData
+	5,  // 6: binance.v1.LiveData.trade:type_name -> binance.v1.TradeData
+	7,  // 7: binance.v1.LiveData.metadata:type_name -> binance.v1.Metadata
+	6,  // 8: binance.v1.ChannelData.data:type_name -> binance.v1.LiveData
+	6,  // 9: binance.v1.LiveDataList.items:type_name -> binance.v1.LiveData
+	11, // 10: binance.v1.SymbolInfoList.symbols:type_name -> binance.v1.SymbolInfo
+	13, // 11: binance.v1.SyncStatusList.statuses:type_name -> binance.v1.SyncStatus
+	0,  // 12: binance.v1.SubscribeRequest.types:type_name -> binance.v1.DataType
+	15, // 13: binance.v1.MarketDataService.GetKlines:input_type -> binance.v1.GetKlinesRequest
+	16, // 14: binance.v1.MarketDataService.GetTrades:input_type -> binance.v1.GetTradesRequest
+	17, // 15: binance.v1.MarketDataService.GetLatestTicker:input_type -> binance.v1.GetLatestTickerRequest
+	18, // 16: binance.v1.MarketDataService.Subscribe:input_type -> binance.v1.SubscribeRequest
+	10, // 17: binance.v1.MarketDataService.GetKlines:output_type -> binance.v1.LiveDataList
+	10, // 18: binance.v1.MarketDataService.GetTrades:output_type -> binance.v1.LiveDataList
+	6,  // 19: binance.v1.MarketDataService.GetLatestTicker:output_type -> binance.v1.LiveData
+	6,  // 20: binance.v1.MarketDataService.Subscribe:output_type -> binance.v1.LiveData
+	17, // [17:21] is the sub-list for method output_type
+	13, // [13:17] is the sub-list for method input_type
+	13, // [13:13] is the sub-list for extension type_name
+	13, // [13:13] is the sub-list for extension extendee
+	0,  // [0:13] is the sub-list for field type_name
 }
 
 func init() { file_proto_binance_proto_init() }
@@ -867,15 +1700,16 @@ func file_proto_binance_proto_init() {
 		(*LiveData_Depth)(nil),
 		(*LiveData_Trade)(nil),
 	}
+	file_proto_binance_proto_msgTypes[12].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_binance_proto_rawDesc), len(file_proto_binance_proto_rawDesc)),
 			NumEnums:      1,
-			NumMessages:   7,
+			NumMessages:   18,
 			NumExtensions: 0,
-			NumServices:   0,
+			NumServices:   1,
 		},
 		GoTypes:           file_proto_binance_proto_goTypes,
 		DependencyIndexes: file_proto_binance_proto_depIdxs,
@@ -0,0 +1,250 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v4.25.1
+// source: proto/binance.proto
+
+// Version 1 of the schema package. Changes within it must stay wire compatible: add
+// fields with new numbers, never renumber, retype or reuse a field (mark removed ones
+// reserved). Changes in meaning raise the schema version of published messages
+// (LiveData.version); incompatible changes go to a new package, binance.v2.
+
+package binance
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	MarketDataService_GetKlines_FullMethodName       = "/binance.v1.MarketDataService/GetKlines"
+	MarketDataService_GetTrades_FullMethodName       = "/binance.v1.MarketDataService/GetTrades"
+	MarketDataService_GetLatestTicker_FullMethodName = "/binance.v1.MarketDataService/GetLatestTicker"
+	MarketDataService_Subscribe_FullMethodName       = "/binance.v1.MarketDataService/Subscribe"
+)
+
+// MarketDataServiceClient is the client API for MarketDataService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Market data served over gRPC: history from the database and live data fanned out
+// in-process from the streams
+type MarketDataServiceClient interface {
+	GetKlines(ctx context.Context, in *GetKlinesRequest, opts ...grpc.CallOption) (*LiveDataList, error)
+	GetTrades(ctx context.Context, in *GetTradesRequest, opts ...grpc.CallOption) (*LiveDataList, error)
+	GetLatestTicker(ctx context.Context, in *GetLatestTickerRequest, opts ...grpc.CallOption) (*LiveData, error)
+	Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[LiveData], error)
+}
+
+type marketDataServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewMarketDataServiceClient(cc grpc.ClientConnInterface) MarketDataServiceClient {
+	return &marketDataServiceClient{cc}
+}
+
+func (c *marketDataServiceClient) GetKlines(ctx context.Context, in *GetKlinesRequest, opts ...grpc.CallOption) (*LiveDataList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LiveDataList)
+	err := c.cc.Invoke(ctx, MarketDataService_GetKlines_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marketDataServiceClient) GetTrades(ctx context.Context, in *GetTradesRequest, opts ...grpc.CallOption) (*LiveDataList, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LiveDataList)
+	err := c.cc.Invoke(ctx, MarketDataService_GetTrades_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marketDataServiceClient) GetLatestTicker(ctx context.Context, in *GetLatestTickerRequest, opts ...grpc.CallOption) (*LiveData, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LiveData)
+	err := c.cc.Invoke(ctx, MarketDataService_GetLatestTicker_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marketDataServiceClient) Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[LiveData], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &MarketDataService_ServiceDesc.Streams[0], MarketDataService_Subscribe_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SubscribeRequest, LiveData]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MarketDataService_SubscribeClient = grpc.ServerStreamingClient[LiveData]
+
+// MarketDataServiceServer is the server API for MarketDataService service.
+// All implementations must embed UnimplementedMarketDataServiceServer
+// for forward compatibility.
+//
+// Market data served over gRPC: history from the database and live data fanned out
+// in-process from the streams
+type MarketDataServiceServer interface {
+	GetKlines(context.Context, *GetKlinesRequest) (*LiveDataList, error)
+	GetTrades(context.Context, *GetTradesRequest) (*LiveDataList, error)
+	GetLatestTicker(context.Context, *GetLatestTickerRequest) (*LiveData, error)
+	Subscribe(*SubscribeRequest, grpc.ServerStreamingServer[LiveData]) error
+	mustEmbedUnimplementedMarketDataServiceServer()
+}
+
+// UnimplementedMarketDataServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedMarketDataServiceServer struct{}
+
+func (UnimplementedMarketDataServiceServer) GetKlines(context.Context, *GetKlinesRequest) (*LiveDataList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetKlines not implemented")
+}
+func (UnimplementedMarketDataServiceServer) GetTrades(context.Context, *GetTradesRequest) (*LiveDataList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTrades not implemented")
+}
+func (UnimplementedMarketDataServiceServer) GetLatestTicker(context.Context, *GetLatestTickerRequest) (*LiveData, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLatestTicker not implemented")
+}
+func (UnimplementedMarketDataServiceServer) Subscribe(*SubscribeRequest, grpc.ServerStreamingServer[LiveData]) error {
+	return status.Errorf(codes.Unimplemented, "method Subscribe not implemented")
+}
+func (UnimplementedMarketDataServiceServer) mustEmbedUnimplementedMarketDataServiceServer() {}
+func (UnimplementedMarketDataServiceServer) testEmbeddedByValue()                           {}
+
+// UnsafeMarketDataServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to MarketDataServiceServer will
+// result in compilation errors.
+type UnsafeMarketDataServiceServer interface {
+	mustEmbedUnimplementedMarketDataServiceServer()
+}
+
+func RegisterMarketDataServiceServer(s grpc.ServiceRegistrar, srv MarketDataServiceServer) {
+	// If the following call pancis, it indicates UnimplementedMarketDataServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&MarketDataService_ServiceDesc, srv)
+}
+
+func _MarketDataService_GetKlines_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetKlinesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketDataServiceServer).GetKlines(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarketDataService_GetKlines_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketDataServiceServer).GetKlines(ctx, req.(*GetKlinesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarketDataService_GetTrades_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTradesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketDataServiceServer).GetTrades(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarketDataService_GetTrades_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketDataServiceServer).GetTrades(ctx, req.(*GetTradesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarketDataService_GetLatestTicker_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLatestTickerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarketDataServiceServer).GetLatestTicker(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarketDataService_GetLatestTicker_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarketDataServiceServer).GetLatestTicker(ctx, req.(*GetLatestTickerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarketDataService_Subscribe_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MarketDataServiceServer).Subscribe(m, &grpc.GenericServerStream[SubscribeRequest, LiveData]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MarketDataService_SubscribeServer = grpc.ServerStreamingServer[LiveData]
+
+// MarketDataService_ServiceDesc is the grpc.ServiceDesc for MarketDataService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var MarketDataService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "binance.v1.MarketDataService",
+	HandlerType: (*MarketDataServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetKlines",
+			Handler:    _MarketDataService_GetKlines_Handler,
+		},
+		{
+			MethodName: "GetTrades",
+			Handler:    _MarketDataService_GetTrades_Handler,
+		},
+		{
+			MethodName: "GetLatestTicker",
+			Handler:    _MarketDataService_GetLatestTicker_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Subscribe",
+			Handler:       _MarketDataService_Subscribe_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "proto/binance.proto",
+}
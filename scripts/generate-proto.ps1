@@ -30,9 +30,22 @@ if (-not (Get-Command protoc -ErrorAction SilentlyContinue)) {
 # Use local protoc if available, otherwise try system protoc
 $protocCmd = if (Test-Path $protocExe) { $protocExe } else { "protoc" }
 
+# Check the schema stays wire compatible with the main branch, if buf is installed
+if (Get-Command buf -ErrorAction SilentlyContinue) {
+    Write-Host "Checking proto/binance.proto for breaking changes..."
+    & buf breaking proto --against ".git#branch=main,subdir=proto"
+
+    if ($LASTEXITCODE -ne 0) {
+        Write-Error "proto/binance.proto has breaking changes; add new fields or a new package version instead"
+        exit 1
+    }
+} else {
+    Write-Host "buf not found, skipping the breaking change check (https://buf.build)"
+}
+
 # Generate Go code
 Write-Host "Generating protobuf Go code..."
-& $protocCmd --go_out=. --go_opt=paths=source_relative proto/binance.proto
+& $protocCmd --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative proto/binance.proto
 
 if ($LASTEXITCODE -eq 0) {
     Write-Host "Protobuf Go code generated successfully!"
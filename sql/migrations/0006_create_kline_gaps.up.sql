@@ -0,0 +1,17 @@
+-- Table to track missing klines detected inside the stored history
+CREATE TABLE IF NOT EXISTS kline_gaps (
+    id BIGSERIAL PRIMARY KEY,
+    symbol VARCHAR(20) NOT NULL,
+    interval VARCHAR(5) NOT NULL,
+    gap_start BIGINT NOT NULL, -- Open time of the first missing kline (inclusive)
+    gap_end BIGINT NOT NULL, -- Open time of the next stored kline (exclusive)
+    missing_count INTEGER NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'open', -- 'open', 'repaired'
+    attempts INTEGER NOT NULL DEFAULT 0,
+    error_message TEXT,
+    detected_at BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW()) * 1000,
+    updated_at BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW()) * 1000,
+    UNIQUE (symbol, interval, gap_start)
+);
+
+CREATE INDEX IF NOT EXISTS idx_kline_gaps_status ON kline_gaps(status, attempts, detected_at);
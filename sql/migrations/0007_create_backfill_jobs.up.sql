@@ -0,0 +1,16 @@
+-- Table to checkpoint historical range backfills so they can be resumed
+CREATE TABLE IF NOT EXISTS backfill_jobs (
+    id BIGSERIAL PRIMARY KEY,
+    symbol VARCHAR(20) NOT NULL,
+    interval VARCHAR(5) NOT NULL,
+    range_start BIGINT NOT NULL, -- Inclusive, Unix timestamp in milliseconds
+    range_end BIGINT NOT NULL, -- Exclusive, Unix timestamp in milliseconds
+    cursor_time BIGINT NOT NULL, -- Everything before this open time has been processed
+    klines_fetched BIGINT NOT NULL DEFAULT 0,
+    windows_skipped INTEGER NOT NULL DEFAULT 0,
+    status VARCHAR(20) NOT NULL DEFAULT 'running', -- 'running', 'completed', 'failed'
+    error_message TEXT,
+    created_at BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW()) * 1000,
+    updated_at BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW()) * 1000,
+    UNIQUE (symbol, interval, range_start, range_end)
+);
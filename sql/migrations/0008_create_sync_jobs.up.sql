@@ -0,0 +1,25 @@
+-- Durable queue of sync work shared by every CLI and server process
+CREATE TABLE IF NOT EXISTS sync_jobs (
+    id BIGSERIAL PRIMARY KEY,
+    symbol VARCHAR(20) NOT NULL,
+    data_type VARCHAR(20) NOT NULL DEFAULT 'kline',
+    interval VARCHAR(5) NOT NULL DEFAULT '',
+    state VARCHAR(20) NOT NULL DEFAULT 'pending', -- 'pending', 'running', 'completed', 'failed'
+    attempts INTEGER NOT NULL DEFAULT 0,
+    max_attempts INTEGER NOT NULL DEFAULT 3,
+    last_error TEXT,
+    klines_fetched BIGINT NOT NULL DEFAULT 0,
+    last_data_time BIGINT NOT NULL DEFAULT 0, -- Open time of the newest stored kline
+    run_after BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW()) * 1000, -- Not claimed before this time
+    locked_by VARCHAR(100), -- Worker that claimed the job
+    locked_at BIGINT,
+    created_at BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW()) * 1000,
+    updated_at BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW()) * 1000
+);
+
+-- At most one open job per stream
+CREATE UNIQUE INDEX IF NOT EXISTS idx_sync_jobs_open ON sync_jobs(symbol, data_type, interval)
+    WHERE state IN ('pending', 'running');
+
+CREATE INDEX IF NOT EXISTS idx_sync_jobs_claim ON sync_jobs(run_after, id) WHERE state = 'pending';
+CREATE INDEX IF NOT EXISTS idx_sync_jobs_state ON sync_jobs(state, updated_at DESC);
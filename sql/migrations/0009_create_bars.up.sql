@@ -0,0 +1,28 @@
+-- Enable TimescaleDB extension
+CREATE EXTENSION IF NOT EXISTS timescaledb;
+
+-- Table to store bars built locally from the aggTrade stream
+CREATE TABLE IF NOT EXISTS bars (
+    symbol VARCHAR(20) NOT NULL,
+    spec VARCHAR(32) NOT NULL, -- Bar spec name, e.g. 2m, vol100, tick500, dollar1000000
+    open_time BIGINT NOT NULL,
+    close_time BIGINT NOT NULL,
+    open_price DECIMAL(20, 8) NOT NULL,
+    high_price DECIMAL(20, 8) NOT NULL,
+    low_price DECIMAL(20, 8) NOT NULL,
+    close_price DECIMAL(20, 8) NOT NULL,
+    volume DECIMAL(20, 8) NOT NULL,
+    quote_volume DECIMAL(20, 8) NOT NULL,
+    trades_count INTEGER NOT NULL,
+    taker_buy_volume DECIMAL(20, 8) NOT NULL,
+    taker_buy_quote_volume DECIMAL(20, 8) NOT NULL,
+    first_trade_id BIGINT NOT NULL, -- First aggregate trade ID (0 for empty time bars)
+    last_trade_id BIGINT NOT NULL,  -- Last aggregate trade ID (0 for empty time bars)
+    created_at BIGINT NOT NULL DEFAULT EXTRACT(EPOCH FROM NOW()) * 1000,
+    PRIMARY KEY (symbol, spec, open_time, first_trade_id)
+);
+
+-- Convert to hypertable (integer time column, one day chunks in milliseconds)
+SELECT create_hypertable('bars', 'open_time', chunk_time_interval => 86400000, if_not_exists => TRUE);
+
+CREATE INDEX IF NOT EXISTS idx_bars_symbol_spec ON bars(symbol, spec, open_time DESC);
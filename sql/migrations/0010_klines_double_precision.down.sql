@@ -0,0 +1,226 @@
+-- migrate:no-transaction
+-- Restore DECIMAL(20, 8) kline columns. Fails when a stored value has more than
+-- 12 integer digits; delete or rescale those klines first.
+--
+-- Converts chunk by chunk like the up migration, with the same downtime: stop everything
+-- writing klines first.
+
+CREATE OR REPLACE PROCEDURE klines_convert_prices(price_type TEXT)
+LANGUAGE plpgsql AS $$
+DECLARE
+    price_column TEXT;
+    current_type TEXT;
+    chunk_interval BIGINT;
+    segment_by TEXT;
+    order_by TEXT;
+    now_func REGPROC;
+    policy_after BIGINT;
+    policy_schedule INTERVAL;
+    chunk RECORD;
+    idx RECORD;
+    agg RECORD;
+BEGIN
+    SELECT format_type(atttypid, atttypmod) INTO current_type
+    FROM pg_attribute
+    WHERE attrelid = 'klines'::regclass AND attname = 'open_price';
+
+    -- klines already has the new types when a previous run got past the swap
+    IF current_type <> price_type THEN
+
+        -- New hypertable with the chunk interval, constraints, indexes and compression
+        -- settings of klines. Its constraints and indexes get a _converted suffix until
+        -- the swap.
+        IF to_regclass('klines_converted') IS NULL THEN
+            SELECT integer_interval INTO chunk_interval
+            FROM timescaledb_information.dimensions
+            WHERE hypertable_name = 'klines' AND column_name = 'open_time';
+
+            CREATE TABLE klines_converted (LIKE klines INCLUDING DEFAULTS INCLUDING CONSTRAINTS);
+            FOREACH price_column IN ARRAY ARRAY[
+                'open_price', 'high_price', 'low_price', 'close_price', 'volume',
+                'quote_volume', 'taker_buy_volume', 'taker_buy_quote_volume'
+            ] LOOP
+                EXECUTE format('ALTER TABLE klines_converted ALTER COLUMN %I TYPE %s', price_column, price_type);
+            END LOOP;
+            PERFORM create_hypertable('klines_converted', 'open_time',
+                chunk_time_interval => chunk_interval, create_default_indexes => FALSE);
+
+            FOR idx IN
+                SELECT index_class.relname AS index_name, con.conname,
+                       pg_get_constraintdef(con.oid) AS constraint_def,
+                       pg_get_indexdef(i.indexrelid) AS index_def
+                FROM pg_index i
+                JOIN pg_class index_class ON index_class.oid = i.indexrelid
+                LEFT JOIN pg_constraint con ON con.conindid = i.indexrelid AND con.conrelid = i.indrelid
+                WHERE i.indrelid = 'klines'::regclass
+            LOOP
+                IF idx.conname IS NOT NULL THEN
+                    EXECUTE format('ALTER TABLE klines_converted ADD CONSTRAINT %I %s',
+                        idx.conname || '_converted', idx.constraint_def);
+                ELSE
+                    EXECUTE format('CREATE %s INDEX %I ON klines_converted %s',
+                        CASE WHEN idx.index_def LIKE 'CREATE UNIQUE %' THEN 'UNIQUE' ELSE '' END,
+                        idx.index_name || '_converted', substring(idx.index_def FROM ' USING .*$'));
+                END IF;
+            END LOOP;
+
+            IF EXISTS (
+                SELECT 1 FROM timescaledb_information.hypertables
+                WHERE hypertable_name = 'klines' AND compression_enabled
+            ) THEN
+                SELECT string_agg(quote_ident(attname), ', ' ORDER BY segmentby_column_index)
+                           FILTER (WHERE segmentby_column_index IS NOT NULL),
+                       string_agg(quote_ident(attname)
+                           || CASE WHEN orderby_asc THEN ' ASC' ELSE ' DESC' END
+                           || CASE WHEN orderby_nullsfirst THEN ' NULLS FIRST' ELSE ' NULLS LAST' END,
+                           ', ' ORDER BY orderby_column_index)
+                           FILTER (WHERE orderby_column_index IS NOT NULL)
+                INTO segment_by, order_by
+                FROM timescaledb_information.compression_settings
+                WHERE hypertable_name = 'klines';
+
+                EXECUTE format('ALTER TABLE klines_converted SET (timescaledb.compress, '
+                    'timescaledb.compress_segmentby = %L, timescaledb.compress_orderby = %L)',
+                    coalesce(segment_by, ''), coalesce(order_by, ''));
+            END IF;
+
+            CREATE TABLE klines_converted_chunks (range_start BIGINT PRIMARY KEY);
+            COMMIT;
+        END IF;
+
+        -- Copy one chunk per transaction. Compressed chunks are read without decompressing
+        -- them, and their copies are compressed again.
+        FOR chunk IN
+            SELECT c.range_start_integer AS range_start, c.range_end_integer AS range_end, c.is_compressed
+            FROM timescaledb_information.chunks c
+            WHERE c.hypertable_name = 'klines'
+              AND NOT EXISTS (SELECT 1 FROM klines_converted_chunks d WHERE d.range_start = c.range_start_integer)
+            ORDER BY c.range_start_integer
+        LOOP
+            INSERT INTO klines_converted (
+                symbol, interval, open_time, close_time, open_price, high_price,
+                low_price, close_price, volume, quote_volume, trades_count,
+                taker_buy_volume, taker_buy_quote_volume, created_at
+            )
+            SELECT symbol, interval, open_time, close_time, open_price, high_price,
+                   low_price, close_price, volume, quote_volume, trades_count,
+                   taker_buy_volume, taker_buy_quote_volume, created_at
+            FROM klines
+            WHERE open_time >= chunk.range_start AND open_time < chunk.range_end;
+
+            IF chunk.is_compressed THEN
+                PERFORM compress_chunk(c, if_not_compressed => TRUE)
+                FROM show_chunks('klines_converted', newer_than => chunk.range_start, older_than => chunk.range_end) c;
+            END IF;
+
+            INSERT INTO klines_converted_chunks (range_start) VALUES (chunk.range_start);
+            COMMIT;
+        END LOOP;
+
+        -- Swap in one transaction. Rows written during the copy would be lost, so start
+        -- over when klines changed.
+        LOCK TABLE klines IN EXCLUSIVE MODE;
+        IF (SELECT count(*) FROM klines) <> (SELECT count(*) FROM klines_converted) THEN
+            TRUNCATE klines_converted, klines_converted_chunks;
+            COMMIT;
+            RAISE EXCEPTION 'klines changed while it was copied: stop all kline writers and run the migration again';
+        END IF;
+
+        CREATE TEMP TABLE klines_converted_aggregates ON COMMIT DROP AS
+        SELECT ca.view_schema, ca.view_name, ca.materialized_only, ca.compression_enabled,
+               regexp_replace(ca.view_definition, ';\s*$', '') AS definition,
+               (refresh_job.config->>'start_offset')::BIGINT AS refresh_start,
+               (refresh_job.config->>'end_offset')::BIGINT AS refresh_end,
+               refresh_job.schedule_interval AS refresh_schedule,
+               (compression_job.config->>'compress_after')::BIGINT AS compress_after,
+               compression_job.schedule_interval AS compress_schedule
+        FROM timescaledb_information.continuous_aggregates ca
+        LEFT JOIN timescaledb_information.jobs refresh_job
+            ON refresh_job.proc_name = 'policy_refresh_continuous_aggregate'
+           AND refresh_job.hypertable_schema = ca.materialization_hypertable_schema
+           AND refresh_job.hypertable_name = ca.materialization_hypertable_name
+        LEFT JOIN timescaledb_information.jobs compression_job
+            ON compression_job.proc_name = 'policy_compression'
+           AND compression_job.hypertable_schema = ca.materialization_hypertable_schema
+           AND compression_job.hypertable_name = ca.materialization_hypertable_name
+        WHERE ca.hypertable_name = 'klines';
+
+        SELECT to_regproc(integer_now_func) INTO now_func
+        FROM timescaledb_information.dimensions
+        WHERE hypertable_name = 'klines' AND column_name = 'open_time';
+
+        SELECT (config->>'compress_after')::BIGINT, schedule_interval INTO policy_after, policy_schedule
+        FROM timescaledb_information.jobs
+        WHERE proc_name = 'policy_compression' AND hypertable_name = 'klines';
+
+        FOR agg IN SELECT view_schema, view_name FROM klines_converted_aggregates LOOP
+            EXECUTE format('DROP MATERIALIZED VIEW %I.%I', agg.view_schema, agg.view_name);
+        END LOOP;
+
+        DROP TABLE klines;
+        ALTER TABLE klines_converted RENAME TO klines;
+        DROP TABLE klines_converted_chunks;
+
+        FOR idx IN
+            SELECT index_class.relname AS index_name, con.conname
+            FROM pg_index i
+            JOIN pg_class index_class ON index_class.oid = i.indexrelid
+            LEFT JOIN pg_constraint con ON con.conindid = i.indexrelid AND con.conrelid = i.indrelid
+            WHERE i.indrelid = 'klines'::regclass
+        LOOP
+            IF idx.conname IS NOT NULL THEN
+                EXECUTE format('ALTER TABLE klines RENAME CONSTRAINT %I TO %I',
+                    idx.conname, left(idx.conname, -length('_converted')));
+            ELSE
+                EXECUTE format('ALTER INDEX %I RENAME TO %I',
+                    idx.index_name, left(idx.index_name, -length('_converted')));
+            END IF;
+        END LOOP;
+
+        IF now_func IS NOT NULL THEN
+            PERFORM set_integer_now_func('klines', now_func);
+        END IF;
+        IF policy_after IS NOT NULL THEN
+            PERFORM add_compression_policy('klines',
+                compress_after => policy_after, schedule_interval => policy_schedule);
+        END IF;
+
+        -- Recreate the continuous aggregates empty, with their options and policies
+        FOR agg IN SELECT * FROM klines_converted_aggregates LOOP
+            EXECUTE format('CREATE MATERIALIZED VIEW %I.%I '
+                'WITH (timescaledb.continuous, timescaledb.materialized_only = %s) AS %s WITH NO DATA',
+                agg.view_schema, agg.view_name, agg.materialized_only, agg.definition);
+
+            IF agg.compression_enabled THEN
+                EXECUTE format('ALTER MATERIALIZED VIEW %I.%I SET (timescaledb.compress)',
+                    agg.view_schema, agg.view_name);
+            END IF;
+            IF agg.refresh_schedule IS NOT NULL THEN
+                PERFORM add_continuous_aggregate_policy(format('%I.%I', agg.view_schema, agg.view_name)::regclass,
+                    start_offset => agg.refresh_start, end_offset => agg.refresh_end,
+                    schedule_interval => agg.refresh_schedule);
+            END IF;
+            IF agg.compress_schedule IS NOT NULL THEN
+                PERFORM add_compression_policy(format('%I.%I', agg.view_schema, agg.view_name)::regclass,
+                    compress_after => agg.compress_after, schedule_interval => agg.compress_schedule);
+            END IF;
+        END LOOP;
+
+        COMMIT;
+    END IF;
+
+    -- Materialize the aggregates, one transaction each
+    FOR agg IN
+        SELECT view_schema, view_name
+        FROM timescaledb_information.continuous_aggregates
+        WHERE hypertable_name = 'klines'
+    LOOP
+        CALL refresh_continuous_aggregate(format('%I.%I', agg.view_schema, agg.view_name)::regclass,
+            NULL::BIGINT, NULL::BIGINT);
+        COMMIT;
+    END LOOP;
+END $$;
+
+CALL klines_convert_prices('numeric(20,8)');
+
+DROP PROCEDURE klines_convert_prices(TEXT);
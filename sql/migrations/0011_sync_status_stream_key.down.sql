@@ -0,0 +1,6 @@
+-- Streams of other markets have no place in the old key
+DELETE FROM sync_status WHERE market <> 'spot';
+
+ALTER TABLE sync_status DROP CONSTRAINT IF EXISTS sync_status_pkey;
+ALTER TABLE sync_status DROP COLUMN IF EXISTS market;
+ALTER TABLE sync_status ADD PRIMARY KEY (symbol, data_type, interval);
@@ -0,0 +1,6 @@
+-- Key sync_status by stream: market, symbol, data type and interval.
+-- interval stays NOT NULL; streams without an interval (ticker, trade, depth) use ''.
+ALTER TABLE sync_status ADD COLUMN IF NOT EXISTS market VARCHAR(20) NOT NULL DEFAULT 'spot';
+
+ALTER TABLE sync_status DROP CONSTRAINT IF EXISTS sync_status_pkey;
+ALTER TABLE sync_status ADD PRIMARY KEY (market, symbol, data_type, interval);
@@ -0,0 +1,10 @@
+// Package migrations embeds the versioned schema migrations applied by database.Migrator.
+// Each change is a pair of <version>_<name>.up.sql and <version>_<name>.down.sql files.
+package migrations
+
+import "embed"
+
+// FS holds the migration files
+//
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,33 @@
+-- name: UpsertBackfillJob :one
+INSERT INTO backfill_jobs (symbol, interval, range_start, range_end, cursor_time)
+VALUES ($1, $2, $3, $4, $3)
+ON CONFLICT (symbol, interval, range_start, range_end) DO UPDATE SET
+    status = CASE WHEN backfill_jobs.status = 'completed' THEN 'completed' ELSE 'running' END,
+    updated_at = EXTRACT(EPOCH FROM NOW()) * 1000
+RETURNING id, symbol, interval, range_start, range_end, cursor_time, klines_fetched,
+          windows_skipped, status, error_message, created_at, updated_at;
+
+-- name: ResetBackfillJob :exec
+UPDATE backfill_jobs
+SET cursor_time = range_start,
+    klines_fetched = 0,
+    windows_skipped = 0,
+    status = 'running',
+    error_message = NULL,
+    updated_at = EXTRACT(EPOCH FROM NOW()) * 1000
+WHERE id = $1;
+
+-- name: UpdateBackfillJobProgress :exec
+UPDATE backfill_jobs
+SET cursor_time = $2,
+    klines_fetched = $3,
+    windows_skipped = $4,
+    updated_at = EXTRACT(EPOCH FROM NOW()) * 1000
+WHERE id = $1;
+
+-- name: FinishBackfillJob :exec
+UPDATE backfill_jobs
+SET status = $2,
+    error_message = $3,
+    updated_at = EXTRACT(EPOCH FROM NOW()) * 1000
+WHERE id = $1;
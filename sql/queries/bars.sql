@@ -0,0 +1,20 @@
+-- name: InsertBar :exec
+INSERT INTO bars (
+    symbol, spec, open_time, close_time, open_price, high_price,
+    low_price, close_price, volume, quote_volume, trades_count,
+    taker_buy_volume, taker_buy_quote_volume, first_trade_id, last_trade_id
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+ON CONFLICT (symbol, spec, open_time, first_trade_id) DO NOTHING;
+
+-- name: GetBarsByTimeRange :many
+SELECT symbol, spec, open_time, close_time, open_price, high_price, low_price, close_price,
+       volume, quote_volume, trades_count, taker_buy_volume, taker_buy_quote_volume,
+       first_trade_id, last_trade_id, created_at
+FROM bars
+WHERE symbol = $1 AND spec = $2
+  AND open_time >= $3 AND open_time < $4
+ORDER BY open_time ASC, first_trade_id ASC;
+
+-- name: DeleteOldBars :execrows
+DELETE FROM bars
+WHERE open_time < $1;
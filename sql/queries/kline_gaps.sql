@@ -0,0 +1,55 @@
+-- name: FindKlineGaps :many
+-- Each stored kline should be followed by one opening at close_time + 1.
+-- Comparing against the next row's open time finds holes for every interval,
+-- including calendar months, without generating the full time series.
+SELECT (k.close_time + 1)::BIGINT AS gap_start, k.next_open_time::BIGINT AS gap_end
+FROM (
+    SELECT open_time, close_time,
+           LEAD(open_time) OVER (ORDER BY open_time) AS next_open_time
+    FROM klines
+    WHERE symbol = $1 AND interval = $2
+      AND open_time >= $3 AND open_time < $4
+) k
+WHERE k.next_open_time > k.close_time + 1
+ORDER BY k.open_time;
+
+-- name: UpsertKlineGap :exec
+INSERT INTO kline_gaps (symbol, interval, gap_start, gap_end, missing_count)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (symbol, interval, gap_start) DO UPDATE SET
+    gap_end = EXCLUDED.gap_end,
+    missing_count = EXCLUDED.missing_count,
+    attempts = CASE WHEN kline_gaps.status = 'open' THEN kline_gaps.attempts ELSE 0 END,
+    error_message = CASE WHEN kline_gaps.status = 'open' THEN kline_gaps.error_message END,
+    status = 'open',
+    updated_at = EXTRACT(EPOCH FROM NOW()) * 1000;
+
+-- name: GetOpenKlineGaps :many
+SELECT id, symbol, interval, gap_start, gap_end, missing_count, status,
+       attempts, error_message, detected_at, updated_at
+FROM kline_gaps
+WHERE status = 'open' AND attempts < $1
+ORDER BY detected_at, id
+LIMIT $2;
+
+-- name: GetKlineGapsBySymbol :many
+SELECT id, symbol, interval, gap_start, gap_end, missing_count, status,
+       attempts, error_message, detected_at, updated_at
+FROM kline_gaps
+WHERE symbol = $1
+ORDER BY interval, gap_start;
+
+-- name: MarkKlineGapRepaired :exec
+UPDATE kline_gaps
+SET status = 'repaired',
+    attempts = attempts + 1,
+    error_message = NULL,
+    updated_at = EXTRACT(EPOCH FROM NOW()) * 1000
+WHERE id = $1;
+
+-- name: MarkKlineGapFailed :exec
+UPDATE kline_gaps
+SET attempts = attempts + 1,
+    error_message = $2,
+    updated_at = EXTRACT(EPOCH FROM NOW()) * 1000
+WHERE id = $1;
@@ -43,6 +43,35 @@ WHERE symbol = $1 AND interval = $2
 ORDER BY open_time DESC
 LIMIT $3;
 
+-- name: GetKlinesBySymbols :many
+SELECT symbol, interval, open_time, close_time, open_price, high_price,
+       low_price, close_price, volume, quote_volume, trades_count,
+       taker_buy_volume, taker_buy_quote_volume, created_at
+FROM klines
+WHERE symbol = ANY(sqlc.arg(symbols)::text[]) AND interval = sqlc.arg(interval)
+  AND open_time >= sqlc.arg(start_time) AND open_time < sqlc.arg(end_time)
+ORDER BY symbol ASC, open_time ASC;
+
+-- name: GetKlinesPage :many
+SELECT symbol, interval, open_time, close_time, open_price, high_price,
+       low_price, close_price, volume, quote_volume, trades_count,
+       taker_buy_volume, taker_buy_quote_volume, created_at
+FROM klines
+WHERE symbol = $1 AND interval = $2
+  AND open_time >= $3 AND open_time < $4
+ORDER BY open_time ASC
+LIMIT $5;
+
 -- name: DeleteOldKlines :exec
 DELETE FROM klines 
 WHERE open_time < $1;
+
+-- name: DeleteOldKlinesByInterval :execrows
+DELETE FROM klines
+WHERE interval = $1 AND open_time < $2;
+
+-- name: CountKlinesByTimeRange :one
+SELECT COUNT(*)
+FROM klines
+WHERE symbol = $1 AND interval = $2
+  AND open_time >= $3 AND open_time < $4;
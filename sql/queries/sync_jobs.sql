@@ -0,0 +1,76 @@
+-- name: EnqueueSyncJob :execrows
+INSERT INTO sync_jobs (symbol, data_type, interval, max_attempts)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (symbol, data_type, interval) WHERE state IN ('pending', 'running') DO NOTHING;
+
+-- name: ClaimSyncJob :one
+UPDATE sync_jobs
+SET state = 'running',
+    attempts = attempts + 1,
+    locked_by = $1,
+    locked_at = EXTRACT(EPOCH FROM NOW()) * 1000,
+    updated_at = EXTRACT(EPOCH FROM NOW()) * 1000
+WHERE id = (
+    SELECT id FROM sync_jobs
+    WHERE state = 'pending' AND run_after <= EXTRACT(EPOCH FROM NOW()) * 1000
+    ORDER BY run_after, id
+    LIMIT 1
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING id, symbol, data_type, interval, state, attempts, max_attempts, last_error,
+          klines_fetched, last_data_time, run_after, locked_by, locked_at, created_at, updated_at;
+
+-- name: UpdateSyncJobProgress :execrows
+UPDATE sync_jobs
+SET klines_fetched = $2,
+    last_data_time = $3,
+    locked_at = EXTRACT(EPOCH FROM NOW()) * 1000,
+    updated_at = EXTRACT(EPOCH FROM NOW()) * 1000
+WHERE id = $1 AND state = 'running' AND locked_by = $4;
+
+-- name: HeartbeatSyncJob :execrows
+UPDATE sync_jobs
+SET locked_at = EXTRACT(EPOCH FROM NOW()) * 1000
+WHERE id = $1 AND state = 'running' AND locked_by = $2;
+
+-- name: CompleteSyncJob :execrows
+UPDATE sync_jobs
+SET state = 'completed',
+    last_error = NULL,
+    locked_by = NULL,
+    locked_at = NULL,
+    updated_at = EXTRACT(EPOCH FROM NOW()) * 1000
+WHERE id = $1 AND state = 'running' AND locked_by = $2;
+
+-- name: FailSyncJob :one
+UPDATE sync_jobs
+SET state = CASE WHEN attempts >= max_attempts THEN 'failed' ELSE 'pending' END,
+    last_error = $2,
+    run_after = $3,
+    locked_by = NULL,
+    locked_at = NULL,
+    updated_at = EXTRACT(EPOCH FROM NOW()) * 1000
+WHERE id = $1 AND state = 'running' AND locked_by = $4
+RETURNING state;
+
+-- name: RequeueStaleSyncJobs :execrows
+UPDATE sync_jobs
+SET state = CASE WHEN attempts >= max_attempts THEN 'failed' ELSE 'pending' END,
+    last_error = 'worker stopped responding',
+    locked_by = NULL,
+    locked_at = NULL,
+    updated_at = EXTRACT(EPOCH FROM NOW()) * 1000
+WHERE state = 'running' AND locked_at < $1;
+
+-- name: CountPendingSyncJobs :one
+SELECT COUNT(*)
+FROM sync_jobs
+WHERE state IN ('pending', 'running');
+
+-- name: ListSyncJobs :many
+SELECT id, symbol, data_type, interval, state, attempts, max_attempts, last_error,
+       klines_fetched, last_data_time, run_after, locked_by, locked_at, created_at, updated_at
+FROM sync_jobs
+WHERE sqlc.arg(state)::text = '' OR state = sqlc.arg(state)::text
+ORDER BY updated_at DESC
+LIMIT sqlc.arg(row_limit);
@@ -1,44 +1,76 @@
 -- name: GetSyncStatus :one
 SELECT symbol, data_type, interval, last_sync_time, last_data_time,
-       status, error_message, updated_at
+       status, error_message, updated_at, market
 FROM sync_status
-WHERE symbol = $1 AND data_type = $2 AND COALESCE(interval, '') = COALESCE($3, '');
+WHERE market = $1 AND symbol = $2 AND data_type = $3 AND interval = $4;
 
 -- name: UpsertSyncStatus :exec
 INSERT INTO sync_status (
-    symbol, data_type, interval, last_sync_time, last_data_time, status, error_message
-) VALUES ($1, $2, $3, $4, $5, $6, $7)
-ON CONFLICT (symbol, data_type, interval) DO UPDATE SET
+    market, symbol, data_type, interval, last_sync_time, last_data_time, status, error_message
+) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+ON CONFLICT (market, symbol, data_type, interval) DO UPDATE SET
     last_sync_time = EXCLUDED.last_sync_time,
     last_data_time = EXCLUDED.last_data_time,
     status = EXCLUDED.status,
     error_message = EXCLUDED.error_message,
     updated_at = EXTRACT(EPOCH FROM NOW()) * 1000;
 
--- name: UpdateLastDataTime :exec
+-- name: RecordSyncProgress :exec
+INSERT INTO sync_status (
+    market, symbol, data_type, interval, last_sync_time, last_data_time, status
+) VALUES ($1, $2, $3, $4, EXTRACT(EPOCH FROM NOW()) * 1000, $5, 'active')
+ON CONFLICT (market, symbol, data_type, interval) DO UPDATE SET
+    last_sync_time = EXCLUDED.last_sync_time,
+    last_data_time = GREATEST(sync_status.last_data_time, EXCLUDED.last_data_time),
+    updated_at = EXTRACT(EPOCH FROM NOW()) * 1000;
+
+-- name: ListSyncStatuses :many
+SELECT s.symbol, s.data_type, s.interval, s.last_sync_time, s.last_data_time,
+       s.status, s.error_message, s.updated_at, s.market
+FROM sync_status s
+WHERE (sqlc.arg(market)::text = '' OR s.market = sqlc.arg(market)::text)
+  AND (sqlc.arg(symbol)::text = '' OR s.symbol = sqlc.arg(symbol)::text)
+  AND (sqlc.arg(data_type)::text = '' OR s.data_type = sqlc.arg(data_type)::text)
+  AND (sqlc.arg(interval)::text = '' OR s.interval = sqlc.arg(interval)::text)
+  AND (NOT sqlc.arg(active_only)::boolean OR EXISTS (
+      SELECT 1 FROM symbols sym WHERE sym.symbol = s.symbol AND sym.is_active = true
+  ))
+ORDER BY s.market, s.symbol, s.data_type, s.interval;
+
+-- name: ResetSyncStatuses :execrows
 UPDATE sync_status
-SET last_data_time = $4,
+SET last_data_time = 0,
     last_sync_time = EXTRACT(EPOCH FROM NOW()) * 1000,
     status = 'active',
     error_message = NULL,
     updated_at = EXTRACT(EPOCH FROM NOW()) * 1000
-WHERE symbol = $1 AND data_type = $2 AND COALESCE(interval, '') = COALESCE($3, '');
+WHERE (sqlc.arg(market)::text = '' OR market = sqlc.arg(market)::text)
+  AND (sqlc.arg(symbol)::text = '' OR symbol = sqlc.arg(symbol)::text)
+  AND (sqlc.arg(data_type)::text = '' OR data_type = sqlc.arg(data_type)::text)
+  AND (sqlc.arg(interval)::text = '' OR interval = sqlc.arg(interval)::text);
 
--- name: GetAllSyncStatuses :many
-SELECT s.symbol, s.data_type, s.interval, s.last_sync_time, s.last_data_time,
-       s.status, s.error_message, s.updated_at
-FROM sync_status s
-INNER JOIN symbols sym ON s.symbol = sym.symbol
-WHERE sym.is_active = true
-ORDER BY s.symbol, s.data_type, s.interval;
+-- name: DeleteSyncStatuses :execrows
+DELETE FROM sync_status
+WHERE (sqlc.arg(market)::text = '' OR market = sqlc.arg(market)::text)
+  AND (sqlc.arg(symbol)::text = '' OR symbol = sqlc.arg(symbol)::text)
+  AND (sqlc.arg(data_type)::text = '' OR data_type = sqlc.arg(data_type)::text)
+  AND (sqlc.arg(interval)::text = '' OR interval = sqlc.arg(interval)::text);
 
--- name: GetSyncStatusesBySymbol :many
-SELECT symbol, data_type, interval, last_sync_time, last_data_time,
-       status, error_message, updated_at
-FROM sync_status
-WHERE symbol = $1
-ORDER BY data_type, interval;
+-- name: SetSyncStatusError :exec
+INSERT INTO sync_status (
+    market, symbol, data_type, interval, last_sync_time, last_data_time, status, error_message
+) VALUES ($1, $2, $3, $4, EXTRACT(EPOCH FROM NOW()) * 1000, 0, 'error', $5)
+ON CONFLICT (market, symbol, data_type, interval) DO UPDATE SET
+    last_sync_time = EXCLUDED.last_sync_time,
+    status = 'error',
+    error_message = EXCLUDED.error_message,
+    updated_at = EXTRACT(EPOCH FROM NOW()) * 1000;
 
--- name: DeleteSyncStatus :exec
-DELETE FROM sync_status 
-WHERE symbol = $1 AND data_type = $2 AND COALESCE(interval, '') = COALESCE($3, '');
+-- name: ClearSyncStatusError :exec
+UPDATE sync_status
+SET status = 'active',
+    error_message = NULL,
+    last_sync_time = EXTRACT(EPOCH FROM NOW()) * 1000,
+    updated_at = EXTRACT(EPOCH FROM NOW()) * 1000
+WHERE market = $1 AND symbol = $2 AND data_type = $3 AND interval = $4
+  AND status = 'error';
@@ -43,6 +43,6 @@ SELECT DISTINCT ON (symbol) symbol, timestamp, price, bid_price, bid_qty, ask_pr
 FROM tickers
 ORDER BY symbol, timestamp DESC;
 
--- name: DeleteOldTickers :exec
+-- name: DeleteOldTickers :execrows
 DELETE FROM tickers 
 WHERE timestamp < $1;